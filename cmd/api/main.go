@@ -17,6 +17,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"pemira-api/internal/adminuser"
+	"pemira-api/internal/analytics"
+	"pemira-api/internal/audit"
 	"pemira-api/internal/auth"
 	"pemira-api/internal/candidate"
 	"pemira-api/internal/config"
@@ -25,6 +27,7 @@ import (
 	"pemira-api/internal/electionvoter"
 	httpMiddleware "pemira-api/internal/http/middleware"
 	"pemira-api/internal/http/response"
+	"pemira-api/internal/idempotency"
 	"pemira-api/internal/master"
 	"pemira-api/internal/monitoring"
 	"pemira-api/internal/settings"
@@ -62,8 +65,8 @@ func main() {
 	dptRepo := dpt.NewRepository(pool)
 	tpsAdminRepo := tps.NewPgAdminRepository(pool)
 	candidatePgRepo := candidate.NewPgCandidateRepository(pool)
-	candidateStatsProvider := candidate.NewPgStatsProvider(pool)
 	monitoringRepo := monitoring.NewPgRepository(pool)
+	candidateStatsProvider := candidate.NewPgStatsProvider(pool, monitoringRepo)
 	tpsRepo := tps.NewPostgresRepositoryFromPool(pool)
 
 	voterRepo := voting.NewVoterRepository()
@@ -71,6 +74,7 @@ func main() {
 	voteRepo := voting.NewVoteRepository()
 	statsRepo := voting.NewVoteStatsRepository()
 	auditSvc := voting.NewAuditService()
+	auditRepo := audit.NewPgRepository(pool)
 
 	// Voter profile repositories
 	voterProfileRepo := voter.NewPgRepository(pool)
@@ -97,13 +101,22 @@ func main() {
 
 	electionService := election.NewService(electionRepo, electionAdminRepo)
 	electionAdminService := election.NewAdminService(electionAdminRepo)
+	electionAdminService.SetResultSignoffsRequired(cfg.ResultSignoffsRequired)
 	dptService := dpt.NewService(dptRepo)
 	tpsAdminService := tps.NewAdminService(tpsAdminRepo)
 	tpsService := tps.NewService(tpsRepo)
 	tpsPanelService := tps.NewPanelService(tpsRepo)
+	rotatingQR := tps.NewRotatingQR(cfg.TPSQRPeriod, cfg.TPSQRSkewWindows, cfg.TPSQRStaticAllowed)
+	tpsService.SetRotatingQR(rotatingQR)
+	tpsPanelService.SetRotatingQR(rotatingQR)
 	candidateService := candidate.NewService(candidatePgRepo, candidateStatsProvider)
+	candidateService.SetResultSeal(electionAdminRepo)
 	candidateHandler := candidate.NewHandler(candidateService)
 	monitoringService := monitoring.NewService(monitoringRepo)
+	monitoringService.SetResultSeal(electionAdminRepo)
+	analyticsService := analytics.NewService(analytics.NewAnalyticsRepo(pool))
+	analyticsService.SetCacheTTL(cfg.AnalyticsCacheTTL)
+	analyticsService.SetResultSeal(electionAdminRepo)
 
 	votingService := voting.NewVotingService(
 		pool,
@@ -114,6 +127,15 @@ func main() {
 		statsRepo,
 		auditSvc,
 	)
+	votingService.SetModeSettingsProvider(electionAdminRepo)
+	votingService.SetBallotQRSecret(cfg.BallotQRSecret)
+	tpsCheckinService := tps.NewCheckinService(pool)
+	tpsCheckinService.SetRotatingQR(rotatingQR)
+	votingService.SetOfflineCheckinApplier(tpsCheckinService)
+
+	idempotencyStore := idempotency.NewPgStore(pool, cfg.IdempotencyKeyTTL)
+	go idempotencyStore.Run(ctx)
+	idempotent := idempotency.NewMiddleware(idempotencyStore).Handler
 
 	voterProfileService := voter.NewService(voterProfileRepo, voterAuthRepo)
 	settingsService := settings.NewService(settingsRepo)
@@ -122,12 +144,15 @@ func main() {
 	adminUserRepo := adminuser.NewPgRepository(pool)
 	adminUserService := adminuser.NewService(adminUserRepo)
 	masterService := master.NewService(masterRepo)
+	auditService := audit.NewService(auditRepo)
 
 	// Initialize handlers
 	authHandler := auth.NewAuthHandler(authService)
 	electionHandler := election.NewHandler(electionService)
 	electionAdminHandler := election.NewAdminHandler(electionAdminService)
 	votingHandler := voting.NewVotingHandler(votingService)
+	// Vote casts keep neither the ballot nor its response under the key
+	idempotentVote := idempotency.NewReceiptMiddleware(idempotencyStore, []byte(cfg.IdempotencySecret), votingHandler.CastReceipt).Handler
 	dptHandler := dpt.NewHandler(dptService)
 	tpsAdminHandler := tps.NewAdminHandler(tpsAdminService)
 	tpsHandler := tps.NewTPSHandler(tpsService)
@@ -135,35 +160,64 @@ func main() {
 	tpsPanelAuthHandler := tps.NewPanelAuthHandler(authService, tpsRepo)
 	candidateAdminHandler := candidate.NewAdminHandler(candidateService)
 	monitoringHandler := monitoring.NewHandler(monitoringService)
+	analyticsHandler := analytics.NewHandler(analyticsService, analytics.NewStandardResponseWriter())
 	voterProfileHandler := voter.NewProfileHandler(voterProfileService)
 	settingsHandler := settings.NewHandler(settingsService)
 	electionVoterHandler := electionvoter.NewHandler(electionVoterService)
 	adminUserHandler := adminuser.NewHandler(adminUserService)
 	masterHandler := master.NewHandler(masterService)
+	auditHandler := audit.NewHandler(auditService)
 
 	logger.Info("services initialized successfully")
 
 	allowedOrigins := parseOrigins(cfg.CORSAllowedOrigins)
 	hub := ws.NewHub()
+	if cfg.RealtimeBackend == "postgres" {
+		hub.SetBackend(ws.NewPgBackend(pool))
+	}
 	go hub.Run(ctx)
+	electionAdminService.SetNotifier(hub)
+
+	liveCount := monitoring.NewLiveCount(monitoringService, hub)
+	liveCount.SetInterval(cfg.LiveCountInterval)
+	go liveCount.Run(ctx)
+	votingService.SetVoteListener(liveCount)
+	tpsService.SetCheckinListener(liveCount)
+	tpsPanelService.SetCheckinListener(liveCount)
+	votingService.SetEventPublisher(hub)
+	tpsService.SetEventPublisher(hub)
+	tpsPanelService.SetEventPublisher(hub)
+	tpsAdminService.SetEventPublisher(hub)
 
-	r := chi.NewRouter()
+	root := chi.NewRouter()
 
 	// CORS middleware
-	r.Use(cors.Handler(cors.Options{
+	root.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", idempotency.HeaderKey},
+		ExposedHeaders:   []string{"Link", idempotency.HeaderReplayed},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	root.Use(middleware.RequestID)
+	root.Use(middleware.RealIP)
+	root.Use(httpMiddleware.ClientInfo)
+	root.Use(middleware.Logger)
+	root.Use(middleware.Recoverer)
+
+	// Audit log exports stream for longer than the request timeout, so they
+	// are mounted outside it with their own deadline (super admin only)
+	root.With(httpMiddleware.AuthSuperAdminOnly(jwtManager)).Get("/api/v1/admin/audit-logs/export", auditHandler.Export)
+
+	// Websocket subscriptions are long-lived, so they are mounted outside the
+	// request timeout too
+	wsHandler := ws.NewHandler(hub, jwtManager, allowedOrigins)
+	wsHandler.SetSnapshotFunc(liveCount.Snapshot)
+	wsHandler.RegisterRoutes(root)
+
+	r := root.With(middleware.Timeout(60 * time.Second))
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		response.Success(w, http.StatusOK, map[string]string{
@@ -184,9 +238,6 @@ func main() {
 
 	r.Handle("/metrics", promhttp.Handler())
 
-	wsHandler := ws.NewHandler(hub)
-	wsHandler.RegisterRoutes(r)
-
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 			response.Success(w, http.StatusOK, map[string]string{
@@ -217,10 +268,15 @@ func main() {
 		r.Get("/elections", electionHandler.ListPublic)
 		r.Get("/elections/{electionID}/phases", electionHandler.GetPublicPhases)
 		r.Get("/elections/{electionID}/timeline", electionHandler.GetPublicPhases)
+		r.Get("/elections/{electionID}/results", electionHandler.GetPublicResults)
 		r.Get("/elections/{electionID}/candidates", candidateHandler.ListPublic)
 		r.Get("/elections/{electionID}/candidates/{candidateID}", candidateHandler.DetailPublic)
 		r.Get("/elections/{electionID}/candidates/{candidateID}/media/profile", candidateHandler.GetPublicProfileMedia)
 
+		// Public receipt verification (does not reveal the candidate)
+		receiptLimiter := httpMiddleware.NewRateLimiter(30, 10)
+		r.With(receiptLimiter.Limit).Post("/voting/receipts/verify", votingHandler.VerifyReceipt)
+
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(httpMiddleware.JWTAuth(jwtManager))
@@ -240,7 +296,7 @@ func main() {
 			r.Post("/voters/me/elections/{electionID}/register", electionVoterHandler.VoterSelfRegister)
 			r.Get("/voters/me/elections/{electionID}/status", electionVoterHandler.VoterStatus)
 
-			// Voter TPS QR (student/admin)
+			// Voter TPS QR (voter/admin)
 			r.Get("/voters/{voterID}/tps/qr", votingHandler.GetVoterTPSQR)
 			r.Post("/voters/{voterID}/tps/qr", votingHandler.GenerateVoterTPSQR)
 
@@ -248,13 +304,14 @@ func main() {
 			r.Post("/tps/checkin/scan", tpsHandler.ScanQR)
 			r.Get("/tps/checkin/status", tpsHandler.StudentCheckinStatus)
 
-			// Voting routes (student only)
+			// Voting routes (students, lecturers and staff; eligibility per election_voters)
 			r.Group(func(r chi.Router) {
-				r.Use(httpMiddleware.AuthStudentOnly(jwtManager))
-				r.Post("/voting/online/cast", votingHandler.CastOnlineVote)
-				r.Post("/voting/tps/cast", votingHandler.CastTPSVote)
+				r.Use(httpMiddleware.AuthVoterOnly(jwtManager))
+				r.With(idempotentVote).Post("/voting/online/cast", votingHandler.CastOnlineVote)
+				r.With(idempotentVote).Post("/voting/tps/cast", votingHandler.CastTPSVote)
 				r.Post("/voting/tps/ballots/parse-qr", votingHandler.ParseBallotQR)
-				r.Post("/voting/tps/ballots/cast-from-qr", votingHandler.CastBallotFromQR)
+				r.With(idempotentVote).Post("/voting/tps/ballots/cast-from-qr", votingHandler.CastBallotFromQR)
+				r.Get("/voting/config", votingHandler.GetVotingConfig)
 				r.Get("/voting/tps/status", votingHandler.GetTPSVotingStatus)
 				r.Get("/voting/receipt", votingHandler.GetVotingReceipt)
 			})
@@ -272,10 +329,13 @@ func main() {
 					r.Patch("/{electionID}", electionAdminHandler.PatchGeneralInfo)
 					r.Post("/{electionID}/open-voting", electionAdminHandler.OpenVoting)
 					r.Post("/{electionID}/close-voting", electionAdminHandler.CloseVoting)
+					r.Get("/{electionID}/status-history", electionAdminHandler.GetStatusHistory)
+					r.Get("/{electionID}/voting-extensions", electionAdminHandler.ListVotingExtensions)
 					r.Route("/{electionID}/actions", func(r chi.Router) {
 						r.Post("/open-voting", electionAdminHandler.OpenVoting)
 						r.Post("/close-voting", electionAdminHandler.CloseVoting)
 						r.Post("/archive", electionAdminHandler.Archive)
+						r.Post("/extend-voting", electionAdminHandler.ExtendVoting)
 					})
 					r.Route("/{electionID}/phases", func(r chi.Router) {
 						r.Get("/", electionAdminHandler.GetPhases)
@@ -285,8 +345,28 @@ func main() {
 						r.Get("/", electionAdminHandler.GetAllSettings)
 						r.Get("/mode", electionAdminHandler.GetModeSettings)
 						r.Put("/mode", electionAdminHandler.UpdateModeSettings)
+						r.Get("/weights", electionAdminHandler.GetVoterTypeWeights)
+						r.Put("/weights", electionAdminHandler.UpdateVoterTypeWeights)
+					})
+					r.Route("/{electionID}/analytics", analyticsHandler.Mount)
+					r.Route("/{electionID}/results", func(r chi.Router) {
+						r.Get("/", electionAdminHandler.GetResults)
+						r.Get("/irv", electionAdminHandler.GetIRVReport)
+						r.Post("/compute", electionAdminHandler.ComputeResults)
+						r.Post("/sign-off", electionAdminHandler.SignOffResults)
+						r.Post("/publish", electionAdminHandler.PublishResults)
+					})
+					r.Route("/{electionID}/races", func(r chi.Router) {
+						r.Get("/", electionAdminHandler.ListRaces)
+						r.Post("/", electionAdminHandler.CreateRace)
+						r.Put("/{raceID}", electionAdminHandler.UpdateRace)
+						r.Delete("/{raceID}", electionAdminHandler.DeleteRace)
 					})
 					r.Get("/{electionID}/summary", electionAdminHandler.GetSummary)
+					r.Route("/{electionID}/ballot-qr", func(r chi.Router) {
+						r.Get("/sheet", votingHandler.GetBallotQRSheet)
+						r.Post("/rotate", votingHandler.RotateBallotQR)
+					})
 					r.Route("/{electionID}/branding", func(r chi.Router) {
 						r.Get("/", electionAdminHandler.GetBranding)
 						r.Get("/logo/{slot}", electionAdminHandler.GetBrandingLogo)
@@ -405,16 +485,24 @@ func main() {
 
 			})
 
+			// Audit logs (super admin only)
+			r.Group(func(r chi.Router) {
+				r.Use(httpMiddleware.AuthSuperAdminOnly(jwtManager))
+				auditHandler.RegisterRoutes(r)
+			})
+
 			// TPS panel endpoints under admin namespace (admin + TPS operator scoped)
 			r.Route("/admin/elections/{electionID}/tps/{tpsID}", func(r chi.Router) {
 				r.Use(httpMiddleware.AuthAdminOrTPSOperator(jwtManager))
 				r.Get("/dashboard", tpsPanelHandler.Dashboard)
 				r.Get("/stats", tpsPanelHandler.Stats)
 				r.Get("/status", tpsPanelHandler.Status)
+				r.Get("/qr/current", tpsPanelHandler.CurrentQR)
 				r.Get("/checkins", tpsPanelHandler.ListCheckins)
 				r.Get("/checkins/{checkinId}", tpsPanelHandler.GetCheckin)
-				r.Post("/checkin/scan", tpsPanelHandler.ScanCheckin)
-				r.Post("/checkin/manual", tpsPanelHandler.ManualCheckin)
+				r.With(idempotent).Post("/checkin/scan", tpsPanelHandler.ScanCheckin)
+				r.With(idempotent).Post("/checkin/manual", tpsPanelHandler.ManualCheckin)
+				r.Post("/sync", votingHandler.SyncTPS)
 				r.Get("/stats/timeline", tpsPanelHandler.Timeline)
 				r.Get("/logs", tpsPanelHandler.Logs)
 
@@ -429,19 +517,39 @@ func main() {
 			r.Group(func(r chi.Router) {
 				r.Use(httpMiddleware.AuthTPSOperatorOnly(jwtManager))
 				r.Post("/tps/{tpsID}/checkins/{checkinID}/scan-candidate", votingHandler.ScanTPSCandidate)
-				r.Post("/tps/{tpsID}/checkins", tpsPanelHandler.CreateCheckinSimple)
+				r.With(idempotent).Post("/tps/{tpsID}/checkins", tpsPanelHandler.CreateCheckinSimple)
 			})
 		})
 	})
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.HTTPPort,
-		Handler:      r,
+		Handler:      root,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Advance election statuses along their phase schedule
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.ElectionSchedulerInterval > 0 {
+		go election.NewScheduler(pool, electionAdminService, cfg.ElectionSchedulerInterval, logger).Run(schedulerCtx)
+	}
+
+	// Link committed audit entries into their hash chains
+	if cfg.AuditChainInterval > 0 {
+		go audit.NewChainer(pool, cfg.AuditChainInterval, logger).Run(schedulerCtx)
+	}
+
+	// Expire TPS check-ins nobody acted on
+	if cfg.CheckinSweepInterval > 0 {
+		checkinSweeper := tps.NewSweeper(pool, cfg.CheckinSweepInterval, cfg.CheckinPendingTTL, cfg.CheckinApprovedTTL, logger)
+		checkinSweeper.SetCheckinListener(liveCount)
+		checkinSweeper.SetEventPublisher(hub)
+		go checkinSweeper.Run(schedulerCtx)
+	}
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
@@ -455,6 +563,7 @@ func main() {
 
 	<-done
 	logger.Info("shutting down server")
+	stopScheduler()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
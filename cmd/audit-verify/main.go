@@ -0,0 +1,110 @@
+// Command audit-verify walks the audit_logs hash chain and reports the first
+// broken link.
+//
+// Usage:
+//
+//	go run ./cmd/audit-verify -election-id 1
+//	go run ./cmd/audit-verify -all
+//
+// Exit codes: 0 when every checked chain is intact, 2 when a chain is broken,
+// 1 on any other error.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+
+	"pemira-api/internal/audit"
+	"pemira-api/pkg/database"
+)
+
+func main() {
+	var (
+		electionID  = flag.Int64("election-id", 0, "election whose chain to verify (0 = entries without an election)")
+		all         = flag.Bool("all", false, "verify every chain in audit_logs")
+		databaseURL = flag.String("database-url", os.Getenv("DATABASE_URL"), "PostgreSQL connection string (defaults to $DATABASE_URL)")
+		asJSON      = flag.Bool("json", false, "print results as JSON")
+		timeout     = flag.Duration("timeout", 10*time.Minute, "overall timeout")
+	)
+	flag.Parse()
+
+	if *databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL is not set")
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	pool, err := database.NewPostgresPool(ctx, *databaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connect database: %v\n", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	svc := audit.NewService(audit.NewPgRepository(pool))
+
+	var results []*audit.ChainVerification
+	if *all {
+		results, err = svc.VerifyAllChains(ctx)
+	} else {
+		var id *int64
+		if *electionID > 0 {
+			id = electionID
+		}
+		var result *audit.ChainVerification
+		result, err = svc.VerifyChain(ctx, id)
+		results = []*audit.ChainVerification{result}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify audit chain: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(results)
+	} else {
+		for _, r := range results {
+			printResult(r)
+		}
+	}
+
+	for _, r := range results {
+		if !r.Valid {
+			os.Exit(2)
+		}
+	}
+}
+
+func printResult(r *audit.ChainVerification) {
+	chain := "no election"
+	if r.ElectionID != nil {
+		chain = fmt.Sprintf("election %d", *r.ElectionID)
+	}
+
+	if r.Valid {
+		fmt.Printf("OK      %s: %d entries verified, head %s\n", chain, r.Checked, r.HeadHash)
+		return
+	}
+
+	b := r.BrokenLink
+	fmt.Printf("BROKEN  %s: %d entries verified before the break\n", chain, r.Checked)
+	fmt.Printf("        first broken entry id=%d chain_seq=%d reason=%s\n", b.ID, b.ChainSeq, b.Reason)
+	switch b.Reason {
+	case audit.BrokenReasonSequenceGap:
+		fmt.Printf("        expected chain_seq %d\n", b.ExpectedSeq)
+	case audit.BrokenReasonPrevHash:
+		fmt.Printf("        expected prev_hash %s\n        actual   prev_hash %s\n", b.ExpectedPrevHash, b.ActualPrevHash)
+	case audit.BrokenReasonRowHash:
+		fmt.Printf("        expected row_hash %s\n        actual   row_hash %s\n", b.ExpectedHash, b.ActualHash)
+	}
+}
@@ -0,0 +1,90 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTL is how long analytics results are reused unless configured otherwise.
+const DefaultCacheTTL = 15 * time.Second
+
+// cache is a small in-process TTL cache. Concurrent misses for the same key
+// share one load, so a burst of dashboard refreshes runs the queries once.
+// Errors are never cached.
+type cache struct {
+	ttl   time.Duration
+	now   func() time.Time
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+// cached returns the value stored under name for the election, loading it
+// when missing or expired. A nil cache always loads.
+func cached[T any](ctx context.Context, c *cache, name string, electionID int64, load func(context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	key := fmt.Sprintf("%s:%d", name, electionID)
+	if v, ok := c.get(key); ok {
+		return v.(T), nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		if v, ok := c.get(key); ok {
+			return v, nil
+		}
+		// The load is shared by every waiting request, so one caller going
+		// away must not cancel it for the others.
+		v, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, v)
+		return v, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+func (c *cache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *cache) set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheSharesConcurrentLoads(t *testing.T) {
+	c := newCache(time.Minute)
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	load := func(context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cached(context.Background(), c, "dashboard", 1, load); err != nil || v != 42 {
+				t.Errorf("cached = %v, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loads = %d, want 1", n)
+	}
+}
+
+func TestCacheExpiresAndSkipsErrors(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := newCache(10 * time.Second)
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	load := func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("db down")
+		}
+		return calls, nil
+	}
+
+	if _, err := cached(context.Background(), c, "velocity", 1, load); err == nil {
+		t.Fatal("expected load error")
+	}
+	if v, _ := cached(context.Background(), c, "velocity", 1, load); v != 2 {
+		t.Fatalf("after error got %d, want a fresh load", v)
+	}
+	if v, _ := cached(context.Background(), c, "velocity", 1, load); v != 2 {
+		t.Fatalf("within TTL got %d, want cached 2", v)
+	}
+	if v, _ := cached(context.Background(), c, "velocity", 2, load); v != 3 {
+		t.Fatalf("other election got %d, want its own load", v)
+	}
+
+	now = now.Add(11 * time.Second)
+	if v, _ := cached(context.Background(), c, "velocity", 1, load); v != 4 {
+		t.Fatalf("after TTL got %d, want reload", v)
+	}
+}
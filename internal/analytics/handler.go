@@ -7,6 +7,8 @@ import (
 "strconv"
 
 "github.com/go-chi/chi/v5"
+
+"pemira-api/internal/election"
 )
 
 // Response helper interface (compatible with internal/http/response)
@@ -15,6 +17,7 @@ Success(w http.ResponseWriter, statusCode int, data interface{})
 BadRequest(w http.ResponseWriter, message string, details interface{})
 InternalServerError(w http.ResponseWriter, message string)
 NotFound(w http.ResponseWriter, message string)
+Forbidden(w http.ResponseWriter, code, message string)
 }
 
 // AnalyticsService defines the interface for analytics operations
@@ -235,9 +238,12 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 var notFoundErr interface{ NotFound() bool }
 
 switch {
-case errors.As(err, &notFoundErr):
+case errors.Is(err, ErrElectionNotFound), errors.As(err, &notFoundErr):
 h.res.NotFound(w, "Pemilu tidak ditemukan.")
 
+case errors.Is(err, election.ErrResultsSealed):
+h.res.Forbidden(w, "RESULTS_SEALED", "Perolehan suara kandidat disegel sampai voting ditutup.")
+
 default:
 // Log internal error here if needed
 h.res.InternalServerError(w, "Terjadi kesalahan pada sistem.")
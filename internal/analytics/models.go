@@ -8,6 +8,8 @@ BucketStart time.Time `json:"bucket_start"`
 TotalVotes  int64     `json:"total_votes"`
 VotesOnline int64     `json:"votes_online"`
 VotesTPS    int64     `json:"votes_tps"`
+// VotesAbstain is the part of TotalVotes cast as abstain (blank) ballots.
+VotesAbstain int64 `json:"votes_abstain"`
 }
 
 // HourlyCandidateVotes represents votes per candidate per hour
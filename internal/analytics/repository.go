@@ -2,34 +2,25 @@ package analytics
 
 import (
 "context"
-_ "embed"
 
 "github.com/jackc/pgx/v5/pgxpool"
-)
-
-//go:embed ../../queries/analytics_02_timeline_votes_by_channel.sql
-var qGetHourlyVotesByChannel string
-
-//go:embed ../../queries/analytics_03_timeline_votes_per_candidate.sql
-var qGetHourlyVotesByCandidate string
-
-//go:embed ../../queries/analytics_05_heatmap_faculty_candidate_percent.sql
-var qFacultyCandidateHeatmap string
-
-//go:embed ../../queries/analytics_06_turnout_cumulative_timeline.sql
-var qTurnoutTimeline string
 
-//go:embed ../../queries/analytics_07_votes_by_cohort_candidate.sql
-var qCohortCandidateVotes string
-
-//go:embed ../../queries/analytics_09_peak_hours_analysis.sql
-var qPeakHoursAnalysis string
+"pemira-api/queries"
+)
 
-//go:embed ../../queries/analytics_10_voting_velocity.sql
-var qVotingVelocity string
+var (
+qGetHourlyVotesByChannel   = queries.AnalyticsHourlyVotesByChannel
+qGetHourlyVotesByCandidate = queries.AnalyticsHourlyVotesByCandidate
+qFacultyCandidateHeatmap   = queries.AnalyticsFacultyCandidateHeatmap
+qTurnoutTimeline           = queries.AnalyticsTurnoutTimeline
+qCohortCandidateVotes      = queries.AnalyticsCohortCandidateVotes
+qPeakHoursAnalysis         = queries.AnalyticsPeakHours
+qVotingVelocity            = queries.AnalyticsVotingVelocity
+)
 
 // AnalyticsRepository defines the interface for analytics data access
 type AnalyticsRepository interface {
+ElectionExists(ctx context.Context, electionID int64) (bool, error)
 GetHourlyVotesByChannel(ctx context.Context, electionID int64) ([]HourlyVotes, error)
 GetHourlyVotesByCandidate(ctx context.Context, electionID int64) ([]HourlyCandidateVotes, error)
 GetFacultyCandidateHeatmap(ctx context.Context, electionID int64) ([]FacultyCandidateHeatmapRow, error)
@@ -49,6 +40,13 @@ func NewAnalyticsRepo(db *pgxpool.Pool) *AnalyticsRepo {
 return &AnalyticsRepo{db: db}
 }
 
+// ElectionExists reports whether the election exists
+func (r *AnalyticsRepo) ElectionExists(ctx context.Context, electionID int64) (bool, error) {
+var exists bool
+err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM elections WHERE id = $1)`, electionID).Scan(&exists)
+return exists, err
+}
+
 // GetHourlyVotesByChannel returns hourly vote counts with channel breakdown
 func (r *AnalyticsRepo) GetHourlyVotesByChannel(
 ctx context.Context,
@@ -68,6 +66,7 @@ if err := rows.Scan(
 &hv.VotesOnline,
 &hv.VotesTPS,
 &hv.TotalVotes,
+&hv.VotesAbstain,
 ); err != nil {
 return nil, err
 }
@@ -70,3 +70,18 @@ Code:    "NOT_FOUND",
 Message: message,
 })
 }
+
+// Forbidden sends a forbidden error response
+func (s *StandardResponseWriter) Forbidden(w http.ResponseWriter, code, message string) {
+type ErrorResponse struct {
+Code    string `json:"code"`
+Message string `json:"message"`
+}
+
+w.Header().Set("Content-Type", "application/json")
+w.WriteHeader(http.StatusForbidden)
+json.NewEncoder(w).Encode(ErrorResponse{
+Code:    code,
+Message: message,
+})
+}
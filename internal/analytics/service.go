@@ -2,19 +2,71 @@ package analytics
 
 import (
 "context"
+"errors"
 "fmt"
+"time"
 
 "golang.org/x/sync/errgroup"
+
+"pemira-api/internal/election"
 )
 
+// ErrElectionNotFound is returned for analytics of an unknown election
+var ErrElectionNotFound = errors.New("election not found")
+
 // Service handles analytics business logic
 type Service struct {
-repo AnalyticsRepository
+repo  AnalyticsRepository
+cache *cache
+seal  election.ResultSeal
 }
 
-// NewService creates a new analytics service
+// NewService creates a new analytics service with a DefaultCacheTTL cache
 func NewService(repo AnalyticsRepository) *Service {
-return &Service{repo: repo}
+return &Service{repo: repo, cache: newCache(DefaultCacheTTL)}
+}
+
+// SetCacheTTL sets how long results are reused; 0 disables the cache
+func (s *Service) SetCacheTTL(ttl time.Duration) {
+if ttl <= 0 {
+s.cache = nil
+return
+}
+s.cache = newCache(ttl)
+}
+
+// SetResultSeal sets the check that withholds per-candidate charts while an
+// election's results are sealed
+func (s *Service) SetResultSeal(seal election.ResultSeal) {
+s.seal = seal
+}
+
+// load returns the cached result of fn for the election, checking that the
+// election exists before running the queries
+func load[T any](ctx context.Context, s *Service, name string, electionID int64, fn func(context.Context) (T, error)) (T, error) {
+return cached(ctx, s.cache, name, electionID, func(ctx context.Context) (T, error) {
+exists, err := s.repo.ElectionExists(ctx, electionID)
+if err == nil && !exists {
+err = ErrElectionNotFound
+}
+if err != nil {
+var zero T
+return zero, err
+}
+return fn(ctx)
+})
+}
+
+// ensureUnsealed rejects per-candidate data while results are sealed for the caller
+func (s *Service) ensureUnsealed(ctx context.Context, electionID int64) error {
+sealed, err := election.SealedFor(ctx, s.seal, electionID)
+if err != nil {
+return err
+}
+if sealed {
+return election.ErrResultsSealed
+}
+return nil
 }
 
 // DashboardCharts contains all chart data for analytics dashboard
@@ -26,10 +78,38 @@ TurnoutTimeline       []TurnoutPoint                `json:"turnout_timeline"`
 CohortBreakdown       []CohortCandidateVotes        `json:"cohort_breakdown"`
 PeakHours             []PeakHour                    `json:"peak_hours"`
 VotingVelocity        *VotingVelocity               `json:"voting_velocity"`
+// ResultsSealed is set when the per-candidate charts are withheld
+ResultsSealed bool `json:"results_sealed"`
 }
 
-// GetDashboardCharts fetches all analytics data in parallel
+// GetDashboardCharts returns all dashboard charts. While results are sealed
+// the per-candidate charts are left out and the rest is still returned.
 func (s *Service) GetDashboardCharts(ctx context.Context, electionID int64) (*DashboardCharts, error) {
+charts, err := load(ctx, s, "dashboard", electionID, func(ctx context.Context) (*DashboardCharts, error) {
+return s.loadDashboardCharts(ctx, electionID)
+})
+if err != nil {
+return nil, err
+}
+
+sealed, err := election.SealedFor(ctx, s.seal, electionID)
+if err != nil {
+return nil, err
+}
+if !sealed {
+return charts, nil
+}
+// The cached value is shared; redact a copy.
+redacted := *charts
+redacted.HourlyByCandidate = nil
+redacted.FacultyHeatmap = nil
+redacted.CohortBreakdown = nil
+redacted.ResultsSealed = true
+return &redacted, nil
+}
+
+// loadDashboardCharts fetches all analytics data in parallel
+func (s *Service) loadDashboardCharts(ctx context.Context, electionID int64) (*DashboardCharts, error) {
 var result DashboardCharts
 g, ctx := errgroup.WithContext(ctx)
 
@@ -112,35 +192,58 @@ return &result, nil
 
 // GetHourlyVotesByChannel wraps repository method
 func (s *Service) GetHourlyVotesByChannel(ctx context.Context, electionID int64) ([]HourlyVotes, error) {
+return load(ctx, s, "hourly-votes", electionID, func(ctx context.Context) ([]HourlyVotes, error) {
 return s.repo.GetHourlyVotesByChannel(ctx, electionID)
+})
 }
 
 // GetHourlyVotesByCandidate wraps repository method
 func (s *Service) GetHourlyVotesByCandidate(ctx context.Context, electionID int64) ([]HourlyCandidateVotes, error) {
+if err := s.ensureUnsealed(ctx, electionID); err != nil {
+return nil, err
+}
+return load(ctx, s, "hourly-candidates", electionID, func(ctx context.Context) ([]HourlyCandidateVotes, error) {
 return s.repo.GetHourlyVotesByCandidate(ctx, electionID)
+})
 }
 
 // GetFacultyCandidateHeatmap wraps repository method
 func (s *Service) GetFacultyCandidateHeatmap(ctx context.Context, electionID int64) ([]FacultyCandidateHeatmapRow, error) {
+if err := s.ensureUnsealed(ctx, electionID); err != nil {
+return nil, err
+}
+return load(ctx, s, "faculty-heatmap", electionID, func(ctx context.Context) ([]FacultyCandidateHeatmapRow, error) {
 return s.repo.GetFacultyCandidateHeatmap(ctx, electionID)
+})
 }
 
 // GetTurnoutTimeline wraps repository method
 func (s *Service) GetTurnoutTimeline(ctx context.Context, electionID int64) ([]TurnoutPoint, error) {
+return load(ctx, s, "turnout", electionID, func(ctx context.Context) ([]TurnoutPoint, error) {
 return s.repo.GetTurnoutTimeline(ctx, electionID)
+})
 }
 
 // GetCohortCandidateVotes wraps repository method
 func (s *Service) GetCohortCandidateVotes(ctx context.Context, electionID int64) ([]CohortCandidateVotes, error) {
+if err := s.ensureUnsealed(ctx, electionID); err != nil {
+return nil, err
+}
+return load(ctx, s, "cohort", electionID, func(ctx context.Context) ([]CohortCandidateVotes, error) {
 return s.repo.GetCohortCandidateVotes(ctx, electionID)
+})
 }
 
 // GetPeakHours wraps repository method
 func (s *Service) GetPeakHours(ctx context.Context, electionID int64) ([]PeakHour, error) {
+return load(ctx, s, "peak-hours", electionID, func(ctx context.Context) ([]PeakHour, error) {
 return s.repo.GetPeakHours(ctx, electionID)
+})
 }
 
 // GetVotingVelocity wraps repository method
 func (s *Service) GetVotingVelocity(ctx context.Context, electionID int64) (*VotingVelocity, error) {
+return load(ctx, s, "velocity", electionID, func(ctx context.Context) (*VotingVelocity, error) {
 return s.repo.GetVotingVelocity(ctx, electionID)
+})
 }
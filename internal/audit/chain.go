@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// GenesisHash is the prev_hash of the first entry of every chain.
+var GenesisHash = strings.Repeat("0", 64)
+
+const (
+	BrokenReasonSequenceGap = "SEQUENCE_GAP"
+	BrokenReasonPrevHash    = "PREV_HASH_MISMATCH"
+	BrokenReasonRowHash     = "ROW_HASH_MISMATCH"
+)
+
+// ChainRow is an audit row as stored, with every hashed column already
+// rendered to the text form used by the database trigger.
+type ChainRow struct {
+	ID           int64
+	ChainSeq     int64
+	PrevHash     string
+	RowHash      string
+	ElectionID   string
+	ActorUserID  string
+	ActorVoterID string
+	Action       string
+	EntityType   string
+	EntityID     string
+	Metadata     string
+	IPAddress    string
+	UserAgent    string
+	CreatedAt    string
+}
+
+// ChainPayload mirrors audit_logs_chain_payload() in migration 035.
+func ChainPayload(row ChainRow) string {
+	return strings.Join([]string{
+		row.PrevHash,
+		strconv.FormatInt(row.ChainSeq, 10),
+		strconv.FormatInt(row.ID, 10),
+		row.ElectionID,
+		row.ActorUserID,
+		row.ActorVoterID,
+		row.Action,
+		row.EntityType,
+		row.EntityID,
+		row.Metadata,
+		row.IPAddress,
+		row.UserAgent,
+		row.CreatedAt,
+	}, "\x1f")
+}
+
+// ComputeRowHash returns the expected row_hash for a row.
+func ComputeRowHash(row ChainRow) string {
+	sum := sha256.Sum256([]byte(ChainPayload(row)))
+	return hex.EncodeToString(sum[:])
+}
+
+// BrokenLink describes the first entry whose link does not verify.
+type BrokenLink struct {
+	ID               int64  `json:"id"`
+	ChainSeq         int64  `json:"chain_seq"`
+	Reason           string `json:"reason"`
+	ExpectedSeq      int64  `json:"expected_seq"`
+	ExpectedPrevHash string `json:"expected_prev_hash"`
+	ActualPrevHash   string `json:"actual_prev_hash"`
+	ExpectedHash     string `json:"expected_hash"`
+	ActualHash       string `json:"actual_hash"`
+}
+
+// ChainVerification is the outcome of walking one chain.
+type ChainVerification struct {
+	ElectionID *int64      `json:"election_id"`
+	Checked    int64       `json:"checked"`
+	Valid      bool        `json:"valid"`
+	HeadHash   string      `json:"head_hash,omitempty"`
+	BrokenLink *BrokenLink `json:"broken_link,omitempty"`
+}
+
+// ChainVerifier checks rows one at a time in chain_seq order.
+type ChainVerifier struct {
+	checked  int64
+	prevHash string
+}
+
+func NewChainVerifier() *ChainVerifier {
+	return &ChainVerifier{prevHash: GenesisHash}
+}
+
+// Next verifies row against the rows seen so far. It returns nil while the
+// chain holds and the first broken link otherwise.
+func (v *ChainVerifier) Next(row ChainRow) *BrokenLink {
+	expectedSeq := v.checked + 1
+	expectedHash := ComputeRowHash(row)
+
+	link := &BrokenLink{
+		ID:               row.ID,
+		ChainSeq:         row.ChainSeq,
+		ExpectedSeq:      expectedSeq,
+		ExpectedPrevHash: v.prevHash,
+		ActualPrevHash:   row.PrevHash,
+		ExpectedHash:     expectedHash,
+		ActualHash:       row.RowHash,
+	}
+
+	switch {
+	case row.ChainSeq != expectedSeq:
+		link.Reason = BrokenReasonSequenceGap
+		return link
+	case row.PrevHash != v.prevHash:
+		link.Reason = BrokenReasonPrevHash
+		return link
+	case row.RowHash != expectedHash:
+		link.Reason = BrokenReasonRowHash
+		return link
+	}
+
+	v.checked++
+	v.prevHash = row.RowHash
+	return nil
+}
+
+func (v *ChainVerifier) Checked() int64 {
+	return v.checked
+}
+
+// Head returns the hash of the last verified row.
+func (v *ChainVerifier) Head() string {
+	return v.prevHash
+}
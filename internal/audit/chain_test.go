@@ -0,0 +1,81 @@
+package audit
+
+import "testing"
+
+func buildChain(n int) []ChainRow {
+	rows := make([]ChainRow, 0, n)
+	prev := GenesisHash
+	for i := 1; i <= n; i++ {
+		row := ChainRow{
+			ID:         int64(i * 10),
+			ChainSeq:   int64(i),
+			PrevHash:   prev,
+			ElectionID: "1",
+			Action:     "CAST_VOTE_ONLINE",
+			EntityType: "VOTE",
+			EntityID:   "1",
+			Metadata:   `{"channel": "ONLINE"}`,
+			CreatedAt:  "2025-02-15T08:00:00.000000Z",
+		}
+		row.RowHash = ComputeRowHash(row)
+		prev = row.RowHash
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func verifyRows(rows []ChainRow) (*ChainVerifier, *BrokenLink) {
+	v := NewChainVerifier()
+	for _, row := range rows {
+		if link := v.Next(row); link != nil {
+			return v, link
+		}
+	}
+	return v, nil
+}
+
+func TestChainVerifier_IntactChain(t *testing.T) {
+	rows := buildChain(5)
+
+	v, link := verifyRows(rows)
+	if link != nil {
+		t.Fatalf("expected intact chain, got break at id %d: %s", link.ID, link.Reason)
+	}
+	if v.Checked() != 5 {
+		t.Fatalf("expected 5 rows checked, got %d", v.Checked())
+	}
+	if v.Head() != rows[4].RowHash {
+		t.Fatalf("expected head to be last row hash")
+	}
+}
+
+func TestChainVerifier_DetectsEditedRow(t *testing.T) {
+	rows := buildChain(5)
+	rows[2].Metadata = `{"channel": "TPS"}`
+
+	_, link := verifyRows(rows)
+	if link == nil || link.ID != rows[2].ID || link.Reason != BrokenReasonRowHash {
+		t.Fatalf("expected ROW_HASH_MISMATCH at id %d, got %+v", rows[2].ID, link)
+	}
+}
+
+func TestChainVerifier_DetectsRehashedRow(t *testing.T) {
+	rows := buildChain(5)
+	rows[2].Metadata = `{"channel": "TPS"}`
+	rows[2].RowHash = ComputeRowHash(rows[2])
+
+	_, link := verifyRows(rows)
+	if link == nil || link.ID != rows[3].ID || link.Reason != BrokenReasonPrevHash {
+		t.Fatalf("expected PREV_HASH_MISMATCH at id %d, got %+v", rows[3].ID, link)
+	}
+}
+
+func TestChainVerifier_DetectsDeletedRow(t *testing.T) {
+	rows := buildChain(5)
+	rows = append(rows[:1], rows[2:]...)
+
+	_, link := verifyRows(rows)
+	if link == nil || link.ChainSeq != 3 || link.Reason != BrokenReasonSequenceGap {
+		t.Fatalf("expected SEQUENCE_GAP at chain_seq 3, got %+v", link)
+	}
+}
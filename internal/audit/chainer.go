@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// chainBatchSize caps the entries linked per chain and tick; the rest are
+// linked on the next one.
+const chainBatchSize = 1000
+
+// Chainer links committed audit entries into their election's hash chain
+// (audit_logs_link_pending() in migration 050). Writers insert entries
+// unlinked, so they never wait on the chain. Every replica may run one; a
+// chain another replica is linking is skipped until a later tick.
+type Chainer struct {
+	db       *pgxpool.Pool
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewChainer(db *pgxpool.Pool, interval time.Duration, logger *slog.Logger) *Chainer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Chainer{db: db, interval: interval, logger: logger}
+}
+
+// Run ticks until ctx is cancelled.
+func (c *Chainer) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.logger.Info("audit chainer started", "interval", c.interval.String())
+	for {
+		if _, err := c.Tick(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			c.logger.Error("audit chainer tick failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			c.logger.Info("audit chainer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick links pending entries and returns how many were linked.
+func (c *Chainer) Tick(ctx context.Context) (int64, error) {
+	var linked int64
+	err := c.db.QueryRow(ctx, `SELECT audit_logs_link_pending($1)`, chainBatchSize).Scan(&linked)
+	return linked, err
+}
@@ -3,29 +3,54 @@ package audit
 import "time"
 
 type AuditLog struct {
-	ID         int64                  `json:"id"`
-	ActorID    int64                  `json:"actor_id"`
-	Action     string                 `json:"action"`
-	EntityType string                 `json:"entity_type"`
-	EntityID   int64                  `json:"entity_id"`
-	Metadata   map[string]interface{} `json:"metadata"`
-	IPAddress  string                 `json:"ip_address"`
-	UserAgent  string                 `json:"user_agent"`
-	CreatedAt  time.Time              `json:"created_at"`
+	ID           int64                  `json:"id"`
+	ElectionID   *int64                 `json:"election_id,omitempty"`
+	ActorUserID  *int64                 `json:"actor_user_id,omitempty"`
+	ActorVoterID *int64                 `json:"actor_voter_id,omitempty"`
+	Action       string                 `json:"action"`
+	EntityType   string                 `json:"entity_type"`
+	EntityID     *int64                 `json:"entity_id,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	IPAddress    string                 `json:"ip_address"`
+	UserAgent    string                 `json:"user_agent"`
+	CreatedAt    time.Time              `json:"created_at"`
+	// Chain columns stay nil until the Chainer links the entry.
+	ChainSeq *int64  `json:"chain_seq"`
+	PrevHash *string `json:"prev_hash"`
+	RowHash  *string `json:"row_hash"`
+}
+
+// ListFilter narrows audit log queries. Zero values are ignored.
+type ListFilter struct {
+	ActorUserID  *int64
+	ActorVoterID *int64
+	ElectionID   *int64
+	EntityType   string
+	EntityID     *int64
+	Action       string
+	From         *time.Time // inclusive
+	To           *time.Time // exclusive
+}
+
+// ListResult is a cursor-paginated page of audit logs, newest first.
+// NextCursor is nil when there are no older entries.
+type ListResult struct {
+	Items      []*AuditLog `json:"items"`
+	NextCursor *int64      `json:"next_cursor"`
 }
 
 type AuditAction string
 
 const (
-	ActionVoteCast          AuditAction = "VOTE_CAST"
-	ActionElectionCreated   AuditAction = "ELECTION_CREATED"
-	ActionElectionUpdated   AuditAction = "ELECTION_UPDATED"
-	ActionCandidateCreated  AuditAction = "CANDIDATE_CREATED"
-	ActionCandidateUpdated  AuditAction = "CANDIDATE_UPDATED"
-	ActionTPSCreated        AuditAction = "TPS_CREATED"
-	ActionTPSQRRegenerated  AuditAction = "TPS_QR_REGENERATED"
-	ActionDPTImported       AuditAction = "DPT_IMPORTED"
-	ActionVoterStatusReset  AuditAction = "VOTER_STATUS_RESET"
-	ActionCheckinApproved   AuditAction = "CHECKIN_APPROVED"
-	ActionCheckinRejected   AuditAction = "CHECKIN_REJECTED"
+	ActionVoteCast         AuditAction = "VOTE_CAST"
+	ActionElectionCreated  AuditAction = "ELECTION_CREATED"
+	ActionElectionUpdated  AuditAction = "ELECTION_UPDATED"
+	ActionCandidateCreated AuditAction = "CANDIDATE_CREATED"
+	ActionCandidateUpdated AuditAction = "CANDIDATE_UPDATED"
+	ActionTPSCreated       AuditAction = "TPS_CREATED"
+	ActionTPSQRRegenerated AuditAction = "TPS_QR_REGENERATED"
+	ActionDPTImported      AuditAction = "DPT_IMPORTED"
+	ActionVoterStatusReset AuditAction = "VOTER_STATUS_RESET"
+	ActionCheckinApproved  AuditAction = "CHECKIN_APPROVED"
+	ActionCheckinRejected  AuditAction = "CHECKIN_REJECTED"
 )
@@ -1,11 +1,20 @@
 package audit
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	
+
 	"pemira-api/internal/http/response"
 	"pemira-api/internal/shared"
 )
@@ -18,33 +27,46 @@ func NewHandler(service *Service) *Handler {
 	return &Handler{service: service}
 }
 
+// exportTimeout bounds a streaming export. Export is mounted by the caller
+// outside the global request timeout, so this is its only deadline.
+const exportTimeout = 15 * time.Minute
+
+// RegisterRoutes mounts the audit log endpoints except Export, which streams
+// past the request timeout and is mounted separately. The caller is
+// responsible for restricting the router to super admins.
 func (h *Handler) RegisterRoutes(r chi.Router) {
-	// Super admin only
 	r.Get("/admin/audit-logs", h.List)
+	r.Get("/admin/audit-logs/verify", h.VerifyChain)
 	r.Get("/admin/audit-logs/{id}", h.GetByID)
 }
 
+// List handles GET /admin/audit-logs?cursor=&limit=&election_id=&actor_user_id=&actor_voter_id=&entity_type=&entity_id=&action=&from=&to=
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	
-	params := shared.NewPaginationParams(page, perPage)
-	
-	filters := make(map[string]interface{})
-	if entityType := r.URL.Query().Get("entity_type"); entityType != "" {
-		filters["entity_type"] = entityType
-	}
-	if action := r.URL.Query().Get("action"); action != "" {
-		filters["action"] = action
-	}
-	
-	logs, total, err := h.service.repo.List(r.Context(), params, filters)
+	q := r.URL.Query()
+
+	filter, err := parseFilter(q)
+	if err != nil {
+		response.BadRequest(w, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	var cursor int64
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor < 0 {
+			response.BadRequest(w, "INVALID_REQUEST", "Invalid cursor")
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	result, err := h.service.List(r.Context(), filter, cursor, limit)
 	if err != nil {
 		response.InternalServerError(w, "INTERNAL_ERROR", "Failed to fetch audit logs")
 		return
 	}
 
-	response.Success(w, http.StatusOK, shared.NewPaginatedResponse(logs, params, total))
+	response.Success(w, http.StatusOK, result)
 }
 
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -54,11 +76,217 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log, err := h.service.repo.GetByID(r.Context(), id)
+	log, err := h.service.Get(r.Context(), id)
 	if err != nil {
-		response.NotFound(w, "NOT_FOUND", "Audit log not found")
+		if errors.Is(err, shared.ErrNotFound) {
+			response.NotFound(w, "NOT_FOUND", "Audit log not found")
+			return
+		}
+		response.InternalServerError(w, "INTERNAL_ERROR", "Failed to fetch audit log")
 		return
 	}
 
 	response.Success(w, http.StatusOK, log)
 }
+
+// VerifyChain handles GET /admin/audit-logs/verify?election_id=
+// Without election_id the chain of entries that have no election is verified.
+func (h *Handler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	electionID, err := parseOptionalID(r.URL.Query(), "election_id")
+	if err != nil {
+		response.BadRequest(w, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	result, err := h.service.VerifyChain(r.Context(), electionID)
+	if err != nil {
+		response.InternalServerError(w, "INTERNAL_ERROR", "Failed to verify audit chain")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
+var csvHeader = []string{
+	"id", "created_at", "election_id", "actor_user_id", "actor_voter_id",
+	"action", "entity_type", "entity_id", "ip_address", "user_agent", "metadata",
+	"chain_seq", "prev_hash", "row_hash",
+}
+
+// Export handles GET /admin/audit-logs/export?format=csv|jsonl with the same filters as List.
+// Rows are streamed in chronological order so large exports never sit in memory.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := parseFilter(q)
+	if err != nil {
+		response.BadRequest(w, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" {
+		response.BadRequest(w, "INVALID_REQUEST", "format must be csv or jsonl")
+		return
+	}
+
+	// Exports can outlive the server's default write timeout.
+	ctx, cancel := context.WithTimeout(r.Context(), exportTimeout)
+	defer cancel()
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportTimeout))
+	flusher, _ := w.(http.Flusher)
+
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		count   int
+		csvW    *csv.Writer
+		encoder *json.Encoder
+	)
+	if format == "csv" {
+		csvW = csv.NewWriter(w)
+		if err := csvW.Write(csvHeader); err != nil {
+			return
+		}
+	} else {
+		encoder = json.NewEncoder(w)
+	}
+
+	err = h.service.Export(ctx, filter, func(log *AuditLog) error {
+		if csvW != nil {
+			if err := csvW.Write(csvRecord(log)); err != nil {
+				return err
+			}
+		} else if err := encoder.Encode(log); err != nil {
+			return err
+		}
+
+		count++
+		if count%500 == 0 {
+			if csvW != nil {
+				csvW.Flush()
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if csvW != nil {
+		csvW.Flush()
+	}
+	if err != nil {
+		// Headers are already sent; the truncated body is the only signal left to the client.
+		slog.Error("audit log export aborted", "error", err, "rows", count)
+	}
+}
+
+func csvRecord(log *AuditLog) []string {
+	meta, _ := json.Marshal(log.Metadata)
+	return []string{
+		strconv.FormatInt(log.ID, 10),
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+		formatOptionalID(log.ElectionID),
+		formatOptionalID(log.ActorUserID),
+		formatOptionalID(log.ActorVoterID),
+		log.Action,
+		log.EntityType,
+		formatOptionalID(log.EntityID),
+		log.IPAddress,
+		log.UserAgent,
+		string(meta),
+		formatOptionalID(log.ChainSeq),
+		formatOptionalString(log.PrevHash),
+		formatOptionalString(log.RowHash),
+	}
+}
+
+func formatOptionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func parseFilter(q url.Values) (ListFilter, error) {
+	var (
+		filter ListFilter
+		err    error
+	)
+
+	if filter.ActorUserID, err = parseOptionalID(q, "actor_user_id"); err != nil {
+		return filter, err
+	}
+	if filter.ActorVoterID, err = parseOptionalID(q, "actor_voter_id"); err != nil {
+		return filter, err
+	}
+	if filter.ElectionID, err = parseOptionalID(q, "election_id"); err != nil {
+		return filter, err
+	}
+	if filter.EntityID, err = parseOptionalID(q, "entity_id"); err != nil {
+		return filter, err
+	}
+	filter.EntityType = strings.TrimSpace(q.Get("entity_type"))
+	filter.Action = strings.TrimSpace(q.Get("action"))
+
+	if filter.From, err = parseTimeParam(q.Get("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseTimeParam(q.Get("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	return filter, nil
+}
+
+func parseOptionalID(q url.Values, key string) (*int64, error) {
+	raw := strings.TrimSpace(q.Get(key))
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &id, nil
+}
+
+// parseTimeParam accepts RFC3339 timestamps or plain dates (YYYY-MM-DD).
+// A plain date used as an upper bound covers the whole day.
+func parseTimeParam(raw string, endOfDay bool) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, errors.New("use RFC3339 or YYYY-MM-DD")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
@@ -2,11 +2,21 @@ package audit
 
 import (
 	"context"
-	"pemira-api/internal/shared"
 )
 
 type Repository interface {
 	Create(ctx context.Context, log *AuditLog) error
-	List(ctx context.Context, params shared.PaginationParams, filters map[string]interface{}) ([]*AuditLog, int64, error)
+	// List returns up to limit entries older than cursor (by id), newest first.
+	// A cursor of 0 starts from the most recent entry.
+	List(ctx context.Context, filter ListFilter, cursor int64, limit int) ([]*AuditLog, error)
 	GetByID(ctx context.Context, id int64) (*AuditLog, error)
+	// Stream calls fn for every matching entry in chronological order without
+	// loading the whole result set into memory.
+	Stream(ctx context.Context, filter ListFilter, fn func(*AuditLog) error) error
+	// StreamChain calls fn for every linked row of one hash chain in
+	// chain_seq order. A nil electionID selects the chain of entries without
+	// an election.
+	StreamChain(ctx context.Context, electionID *int64, fn func(ChainRow) error) error
+	// ListChains returns the election IDs that have audit entries (nil for entries without an election).
+	ListChains(ctx context.Context) ([]*int64, error)
 }
@@ -0,0 +1,303 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"pemira-api/internal/shared"
+)
+
+type pgRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgRepository(db *pgxpool.Pool) Repository {
+	return &pgRepository{db: db}
+}
+
+const auditLogColumns = `
+    id,
+    election_id,
+    actor_user_id,
+    actor_voter_id,
+    action,
+    entity_type,
+    entity_id,
+    metadata,
+    COALESCE(ip_address, '') AS ip_address,
+    COALESCE(user_agent, '') AS user_agent,
+    created_at,
+    chain_seq,
+    prev_hash,
+    row_hash
+`
+
+func (r *pgRepository) Create(ctx context.Context, log *AuditLog) error {
+	metadata := log.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(ctx, `
+INSERT INTO audit_logs (
+    election_id, actor_user_id, actor_voter_id, action, entity_type, entity_id,
+    metadata, ip_address, user_agent, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+RETURNING id
+`,
+		log.ElectionID,
+		log.ActorUserID,
+		log.ActorVoterID,
+		log.Action,
+		log.EntityType,
+		log.EntityID,
+		metaJSON,
+		log.IPAddress,
+		log.UserAgent,
+		log.CreatedAt,
+	).Scan(&log.ID)
+}
+
+func (r *pgRepository) List(ctx context.Context, filter ListFilter, cursor int64, limit int) ([]*AuditLog, error) {
+	where, args := buildWhere(filter)
+	if cursor > 0 {
+		where = append(where, fmt.Sprintf("id < $%d", len(args)+1))
+		args = append(args, cursor)
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+SELECT %s
+FROM audit_logs
+%s
+ORDER BY id DESC
+LIMIT $%d
+`, auditLogColumns, whereClause(where), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list audit_logs: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*AuditLog, 0, limit)
+	for rows.Next() {
+		item, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *pgRepository) GetByID(ctx context.Context, id int64) (*AuditLog, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+auditLogColumns+` FROM audit_logs WHERE id = $1`, id)
+	item, err := scanAuditLog(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *pgRepository) Stream(ctx context.Context, filter ListFilter, fn func(*AuditLog) error) error {
+	where, args := buildWhere(filter)
+	query := fmt.Sprintf(`
+SELECT %s
+FROM audit_logs
+%s
+ORDER BY id ASC
+`, auditLogColumns, whereClause(where))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("stream audit_logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// chainRowColumns renders hashed columns exactly as audit_logs_chain_payload() does.
+const chainRowColumns = `
+    id,
+    chain_seq,
+    prev_hash,
+    row_hash,
+    COALESCE(election_id::text, ''),
+    COALESCE(actor_user_id::text, ''),
+    COALESCE(actor_voter_id::text, ''),
+    action,
+    entity_type,
+    COALESCE(entity_id::text, ''),
+    metadata::text,
+    COALESCE(ip_address, ''),
+    COALESCE(user_agent, ''),
+    to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+`
+
+func (r *pgRepository) StreamChain(ctx context.Context, electionID *int64, fn func(ChainRow) error) error {
+	var key int64
+	if electionID != nil {
+		key = *electionID
+	}
+
+	rows, err := r.db.Query(ctx, `
+SELECT `+chainRowColumns+`
+FROM audit_logs
+WHERE COALESCE(election_id, 0) = $1 AND row_hash IS NOT NULL
+ORDER BY chain_seq ASC
+`, key)
+	if err != nil {
+		return fmt.Errorf("stream audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ChainRow
+		if err := rows.Scan(
+			&row.ID,
+			&row.ChainSeq,
+			&row.PrevHash,
+			&row.RowHash,
+			&row.ElectionID,
+			&row.ActorUserID,
+			&row.ActorVoterID,
+			&row.Action,
+			&row.EntityType,
+			&row.EntityID,
+			&row.Metadata,
+			&row.IPAddress,
+			&row.UserAgent,
+			&row.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *pgRepository) ListChains(ctx context.Context) ([]*int64, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT election_id FROM audit_logs ORDER BY election_id NULLS FIRST`)
+	if err != nil {
+		return nil, fmt.Errorf("list audit chains: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*int64
+	for rows.Next() {
+		var id *int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func buildWhere(filter ListFilter) ([]string, []interface{}) {
+	var (
+		where []string
+		args  []interface{}
+	)
+
+	if filter.ActorUserID != nil {
+		where = append(where, fmt.Sprintf("actor_user_id = $%d", len(args)+1))
+		args = append(args, *filter.ActorUserID)
+	}
+	if filter.ActorVoterID != nil {
+		where = append(where, fmt.Sprintf("actor_voter_id = $%d", len(args)+1))
+		args = append(args, *filter.ActorVoterID)
+	}
+	if filter.ElectionID != nil {
+		where = append(where, fmt.Sprintf("election_id = $%d", len(args)+1))
+		args = append(args, *filter.ElectionID)
+	}
+	if filter.EntityType != "" {
+		where = append(where, fmt.Sprintf("entity_type = $%d", len(args)+1))
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		where = append(where, fmt.Sprintf("entity_id = $%d", len(args)+1))
+		args = append(args, *filter.EntityID)
+	}
+	if filter.Action != "" {
+		where = append(where, fmt.Sprintf("action = $%d", len(args)+1))
+		args = append(args, filter.Action)
+	}
+	if filter.From != nil {
+		where = append(where, fmt.Sprintf("created_at >= $%d", len(args)+1))
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		where = append(where, fmt.Sprintf("created_at < $%d", len(args)+1))
+		args = append(args, *filter.To)
+	}
+
+	return where, args
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(where, " AND ")
+}
+
+func scanAuditLog(row pgx.Row) (*AuditLog, error) {
+	var (
+		item     AuditLog
+		metaJSON []byte
+	)
+	if err := row.Scan(
+		&item.ID,
+		&item.ElectionID,
+		&item.ActorUserID,
+		&item.ActorVoterID,
+		&item.Action,
+		&item.EntityType,
+		&item.EntityID,
+		&metaJSON,
+		&item.IPAddress,
+		&item.UserAgent,
+		&item.CreatedAt,
+		&item.ChainSeq,
+		&item.PrevHash,
+		&item.RowHash,
+	); err != nil {
+		return nil, err
+	}
+
+	item.Metadata = map[string]interface{}{}
+	if len(metaJSON) > 0 {
+		if err := json.Unmarshal(metaJSON, &item.Metadata); err != nil {
+			return nil, fmt.Errorf("decode audit metadata: %w", err)
+		}
+	}
+	return &item, nil
+}
@@ -2,9 +2,15 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
 type Service struct {
 	repo Repository
 }
@@ -23,20 +29,99 @@ func (s *Service) Log(
 	ipAddress, userAgent string,
 ) error {
 	log := &AuditLog{
-		ActorID:    actorID,
 		Action:     string(action),
 		EntityType: entityType,
-		EntityID:   entityID,
+		EntityID:   &entityID,
 		Metadata:   metadata,
 		IPAddress:  ipAddress,
 		UserAgent:  userAgent,
-		CreatedAt:  time.Now(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if actorID > 0 {
+		log.ActorUserID = &actorID
 	}
 
 	return s.repo.Create(ctx, log)
 }
 
-func (s *Service) GetLogs(ctx context.Context, filters map[string]interface{}) ([]*AuditLog, error) {
-	logs, _, err := s.repo.List(ctx, shared.PaginationParams{Page: 1, PerPage: 100}, filters)
-	return logs, err
+// List returns a page of audit logs, newest first.
+func (s *Service) List(ctx context.Context, filter ListFilter, cursor int64, limit int) (*ListResult, error) {
+	if limit < 1 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	// Fetch one extra row to know whether another page exists.
+	items, err := s.repo.List(ctx, filter, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		next := result.Items[limit-1].ID
+		result.NextCursor = &next
+	}
+	return result, nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*AuditLog, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Export streams every matching entry in chronological order to fn.
+func (s *Service) Export(ctx context.Context, filter ListFilter, fn func(*AuditLog) error) error {
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+var errChainBroken = errors.New("audit chain broken")
+
+// VerifyChain walks the hash chain of one election (nil for entries without
+// an election) and reports the first broken link, if any.
+func (s *Service) VerifyChain(ctx context.Context, electionID *int64) (*ChainVerification, error) {
+	verifier := NewChainVerifier()
+	var broken *BrokenLink
+
+	err := s.repo.StreamChain(ctx, electionID, func(row ChainRow) error {
+		if link := verifier.Next(row); link != nil {
+			broken = link
+			return errChainBroken
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return nil, err
+	}
+
+	result := &ChainVerification{
+		ElectionID: electionID,
+		Checked:    verifier.Checked(),
+		Valid:      broken == nil,
+		BrokenLink: broken,
+	}
+	if broken == nil && verifier.Checked() > 0 {
+		result.HeadHash = verifier.Head()
+	}
+	return result, nil
+}
+
+// VerifyAllChains verifies every chain present in audit_logs.
+func (s *Service) VerifyAllChains(ctx context.Context) ([]*ChainVerification, error) {
+	keys, err := s.repo.ListChains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ChainVerification, 0, len(keys))
+	for _, electionID := range keys {
+		result, err := s.VerifyChain(ctx, electionID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
@@ -19,15 +19,23 @@ func FromContext(ctx context.Context) (AuthUser, bool) {
 		return AuthUser{}, false
 	}
 
+	// Read the voter claim directly: GetVoterID falls back to the user ID,
+	// which would give non-voter accounts a bogus voter identity.
 	var voterID *int64
-	if id, ok := ctxkeys.GetVoterID(ctx); ok {
+	if id, ok := ctx.Value(ctxkeys.VoterIDKey).(int64); ok {
 		voterID = &id
 	}
 
+	var tpsID *int64
+	if id, ok := ctxkeys.GetTPSID(ctx); ok {
+		tpsID = &id
+	}
+
 	authUser := AuthUser{
 		ID:      userID,
 		Role:    constants.Role(roleStr),
 		VoterID: voterID,
+		TPSID:   tpsID,
 	}
 
 	return authUser, true
@@ -21,6 +21,7 @@ import (
 
 // AdminCreateCandidateRequest represents request to create a new candidate
 type AdminCreateCandidateRequest struct {
+	RaceID           *int64          `json:"race_id"`
 	Number           int             `json:"number"`
 	Name             string          `json:"name"`
 	PhotoURL         string          `json:"photo_url"`
@@ -40,6 +41,7 @@ type AdminCreateCandidateRequest struct {
 
 // AdminUpdateCandidateRequest represents request to update a candidate
 type AdminUpdateCandidateRequest struct {
+	RaceID           *int64           `json:"race_id,omitempty"`
 	Number           *int             `json:"number,omitempty"`
 	Name             *string          `json:"name,omitempty"`
 	PhotoURL         *string          `json:"photo_url,omitempty"`
@@ -78,6 +80,7 @@ type AdminCandidateService interface {
 var (
 	ErrElectionNotFound       = errors.New("election not found")
 	ErrCandidateNumberTaken   = errors.New("candidate number already used")
+	ErrCandidateRaceInvalid   = errors.New("race not found in this election")
 	ErrCandidateStatusInvalid = errors.New("candidate status invalid for this action")
 )
 
@@ -397,7 +400,7 @@ func (h *AdminHandler) GetProfileMedia(w http.ResponseWriter, r *http.Request) {
 		if resp.ContentLength > 0 {
 			w.Header().Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
 		}
-		
+
 		// Stream blob to client
 		w.WriteHeader(http.StatusOK)
 		io.Copy(w, resp.Body)
@@ -642,6 +645,9 @@ func (h *AdminHandler) handleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, ErrCandidateStatusInvalid):
 		response.BadRequest(w, "INVALID_REQUEST", "Perubahan status kandidat tidak diizinkan.")
 
+	case errors.Is(err, ErrCandidateRaceInvalid):
+		response.BadRequest(w, "INVALID_RACE", "Posisi (race_id) tidak ditemukan di pemilu ini.")
+
 	case errors.Is(err, ErrCandidateMediaNotFound):
 		response.NotFound(w, "MEDIA_NOT_FOUND", "Media kandidat tidak ditemukan.")
 
@@ -5,6 +5,7 @@ import "time"
 type Candidate struct {
 	ID               int64                `json:"id"`
 	ElectionID       int64                `json:"election_id"`
+	RaceID           *int64               `json:"race_id,omitempty"`
 	Number           int                  `json:"number"`
 	Name             string               `json:"name"`
 	PhotoURL         string               `json:"photo_url"`
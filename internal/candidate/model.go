@@ -76,4 +76,8 @@ type SocialLink struct {
 type CandidateStats struct {
 	TotalVotes int64   `json:"total_votes"`
 	Percentage float64 `json:"percentage"`
+	// WeightedPercentage is the combined share after per-voter-type weighting
+	// (equal to Percentage when the election has no weighting rules).
+	WeightedPercentage float64          `json:"weighted_percentage"`
+	VotesByVoterType   map[string]int64 `json:"votes_by_voter_type,omitempty"`
 }
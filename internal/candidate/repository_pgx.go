@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	storage_go "github.com/supabase-community/storage-go"
 )
@@ -27,6 +29,7 @@ const qListCandidatesBase = `
 SELECT
 id,
 election_id,
+race_id,
 number,
 name,
 photo_url,
@@ -123,6 +126,7 @@ const qGetCandidateByID = `
 SELECT
 id,
 election_id,
+race_id,
 number,
 name,
 photo_url,
@@ -149,6 +153,7 @@ const qGetCandidateByCandidateID = `
 SELECT
 id,
 election_id,
+race_id,
 number,
 name,
 photo_url,
@@ -212,6 +217,7 @@ func scanCandidate(rows pgx.Rows) (Candidate, error) {
 	if err := rows.Scan(
 		&c.ID,
 		&c.ElectionID,
+		&c.RaceID,
 		&c.Number,
 		&c.Name,
 		&c.PhotoURL,
@@ -258,6 +264,7 @@ func scanCandidateRow(row pgx.Row) (Candidate, error) {
 	err := row.Scan(
 		&c.ID,
 		&c.ElectionID,
+		&c.RaceID,
 		&c.Number,
 		&c.Name,
 		&c.PhotoURL,
@@ -297,6 +304,15 @@ func scanCandidateRow(row pgx.Row) (Candidate, error) {
 	return c, nil
 }
 
+// mapRaceFKError reports a race_id that does not belong to the candidate's election.
+func mapRaceFKError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "fk_candidates_race" {
+		return ErrCandidateRaceInvalid
+	}
+	return err
+}
+
 // scanJSON scans JSONB data into a Go type
 func scanJSON[T any](src any, dest *T) error {
 	if src == nil {
@@ -344,11 +360,11 @@ const qCreateCandidate = `
 INSERT INTO candidates (
 election_id, number, name, photo_url, photo_media_id, short_bio, long_bio, tagline,
 faculty_name, study_program_name, cohort_year, vision, missions,
-main_programs, media, social_links, status, created_at, updated_at, updated_by_admin_id
+main_programs, media, social_links, status, race_id, created_at, updated_at, updated_by_admin_id
 ) VALUES (
-$1, $2, $3, $4, NULL, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW(), NULL
+$1, $2, $3, $4, NULL, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW(), NOW(), NULL
 )
-RETURNING id, election_id, number, name, photo_url, photo_media_id, short_bio, long_bio, tagline,
+RETURNING id, election_id, race_id, number, name, photo_url, photo_media_id, short_bio, long_bio, tagline,
 faculty_name, study_program_name, cohort_year, vision, missions, main_programs,
 media, social_links, status, created_at, updated_at
 `
@@ -377,11 +393,12 @@ func (r *PgCandidateRepository) Create(ctx context.Context, candidate *Candidate
 		mediaJSON,
 		socialLinksJSON,
 		candidate.Status,
+		candidate.RaceID,
 	)
 
 	c, err := scanCandidateRow(row)
 	if err != nil {
-		return nil, err
+		return nil, mapRaceFKError(err)
 	}
 
 	return &c, nil
@@ -404,9 +421,10 @@ main_programs = $14,
 media = $15,
 social_links = $16,
 status = $17,
+race_id = $18,
 updated_at = NOW()
 WHERE election_id = $1 AND id = $2
-RETURNING id, election_id, number, name, photo_url, photo_media_id, short_bio, long_bio, tagline,
+RETURNING id, election_id, race_id, number, name, photo_url, photo_media_id, short_bio, long_bio, tagline,
 faculty_name, study_program_name, cohort_year, vision, missions, main_programs,
 media, social_links, status, created_at, updated_at
 `
@@ -436,6 +454,7 @@ func (r *PgCandidateRepository) Update(ctx context.Context, electionID, candidat
 		mediaJSON,
 		socialLinksJSON,
 		candidate.Status,
+		candidate.RaceID,
 	)
 
 	c, err := scanCandidateRow(row)
@@ -443,7 +462,7 @@ func (r *PgCandidateRepository) Update(ctx context.Context, electionID, candidat
 		if err == pgx.ErrNoRows {
 			return nil, ErrCandidateNotFound
 		}
-		return nil, err
+		return nil, mapRaceFKError(err)
 	}
 
 	return &c, nil
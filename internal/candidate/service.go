@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math"
 	"os"
+
+	"pemira-api/internal/election"
 )
 
 // CandidateStatsMap maps candidate ID to their voting statistics
@@ -21,6 +23,7 @@ type StatsProvider interface {
 type Service struct {
 	repo  CandidateRepository
 	stats StatsProvider
+	seal  election.ResultSeal
 }
 
 // NewService creates a new candidate service
@@ -31,10 +34,33 @@ func NewService(repo CandidateRepository, stats StatsProvider) *Service {
 	}
 }
 
+// SetResultSeal sets the check that withholds vote stats while an election's
+// results are sealed.
+func (s *Service) SetResultSeal(seal election.ResultSeal) {
+	s.seal = seal
+}
+
+// candidateStats returns the vote stats of an election's candidates. When
+// the results are sealed for the caller the stats are withheld and sealed is
+// true; a failing seal check withholds them too.
+func (s *Service) candidateStats(ctx context.Context, electionID int64) (statsMap CandidateStatsMap, sealed bool) {
+	sealed, err := election.SealedFor(ctx, s.seal, electionID)
+	if err != nil || sealed {
+		return CandidateStatsMap{}, true
+	}
+	statsMap, err = s.stats.GetCandidateStats(ctx, electionID)
+	if err != nil {
+		// Fallback to empty stats if stats service fails
+		statsMap = CandidateStatsMap{}
+	}
+	return statsMap, false
+}
+
 // CandidateListItemDTO represents a candidate in list view
 type CandidateListItemDTO struct {
 	ID               int64          `json:"id"`
 	ElectionID       int64          `json:"election_id"`
+	RaceID           *int64         `json:"race_id,omitempty"`
 	Number           int            `json:"number"`
 	Name             string         `json:"name"`
 	PhotoURL         string         `json:"photo_url"`
@@ -45,12 +71,14 @@ type CandidateListItemDTO struct {
 	StudyProgramName string         `json:"study_program_name"`
 	Status           string         `json:"status"`
 	Stats            CandidateStats `json:"stats"`
+	ResultsSealed    bool           `json:"results_sealed,omitempty"`
 }
 
 // CandidateDetailDTO represents a candidate in detail view
 type CandidateDetailDTO struct {
 	ID               int64                `json:"id"`
 	ElectionID       int64                `json:"election_id"`
+	RaceID           *int64               `json:"race_id,omitempty"`
 	Number           int                  `json:"number"`
 	Name             string               `json:"name"`
 	PhotoURL         string               `json:"photo_url"`
@@ -69,6 +97,7 @@ type CandidateDetailDTO struct {
 	SocialLinks      []SocialLink         `json:"social_links"`
 	Status           string               `json:"status"`
 	Stats            CandidateStats       `json:"stats"`
+	ResultsSealed    bool                 `json:"results_sealed,omitempty"`
 }
 
 // Pagination represents pagination metadata
@@ -108,11 +137,7 @@ func (s *Service) ListPublicCandidates(
 		return nil, Pagination{}, err
 	}
 
-	statsMap, err := s.stats.GetCandidateStats(ctx, electionID)
-	if err != nil {
-		// Fallback to empty stats if stats service fails
-		statsMap = CandidateStatsMap{}
-	}
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 
 	dtos := make([]CandidateListItemDTO, 0, len(candidates))
 	for _, c := range candidates {
@@ -120,6 +145,7 @@ func (s *Service) ListPublicCandidates(
 		dtos = append(dtos, CandidateListItemDTO{
 			ID:               c.ID,
 			ElectionID:       c.ElectionID,
+			RaceID:           c.RaceID,
 			Number:           c.Number,
 			Name:             c.Name,
 			PhotoURL:         c.PhotoURL,
@@ -130,6 +156,7 @@ func (s *Service) ListPublicCandidates(
 			StudyProgramName: c.StudyProgramName,
 			Status:           string(c.Status),
 			Stats:            stats,
+			ResultsSealed:    sealed,
 		})
 	}
 
@@ -164,15 +191,13 @@ func (s *Service) GetPublicCandidateDetail(
 	}
 
 	// Get stats for this candidate
-	statsMap, err := s.stats.GetCandidateStats(ctx, electionID)
-	if err != nil {
-		statsMap = CandidateStatsMap{}
-	}
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 	stats := statsMap[c.ID]
 
 	dto := &CandidateDetailDTO{
 		ID:               c.ID,
 		ElectionID:       c.ElectionID,
+		RaceID:           c.RaceID,
 		Number:           c.Number,
 		Name:             c.Name,
 		PhotoURL:         c.PhotoURL,
@@ -191,6 +216,7 @@ func (s *Service) GetPublicCandidateDetail(
 		SocialLinks:      c.SocialLinks,
 		Status:           string(c.Status),
 		Stats:            stats,
+		ResultsSealed:    sealed,
 	}
 
 	return dto, nil
@@ -237,10 +263,7 @@ func (s *Service) AdminListCandidates(
 	}
 
 	// Get stats for all candidates
-	statsMap, err := s.stats.GetCandidateStats(ctx, electionID)
-	if err != nil {
-		statsMap = CandidateStatsMap{}
-	}
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 
 	dtos := make([]CandidateDetailDTO, 0, len(candidates))
 	for _, c := range candidates {
@@ -248,6 +271,7 @@ func (s *Service) AdminListCandidates(
 		dtos = append(dtos, CandidateDetailDTO{
 			ID:               c.ID,
 			ElectionID:       c.ElectionID,
+			RaceID:           c.RaceID,
 			Number:           c.Number,
 			Name:             c.Name,
 			PhotoURL:         c.PhotoURL,
@@ -266,6 +290,7 @@ func (s *Service) AdminListCandidates(
 			SocialLinks:      c.SocialLinks,
 			Status:           string(c.Status),
 			Stats:            stats,
+			ResultsSealed:    sealed,
 		})
 	}
 
@@ -310,6 +335,7 @@ func (s *Service) AdminCreateCandidate(
 	// Create candidate entity
 	candidate := &Candidate{
 		ElectionID:       electionID,
+		RaceID:           req.RaceID,
 		Number:           req.Number,
 		Name:             req.Name,
 		PhotoURL:         req.PhotoURL,
@@ -333,12 +359,13 @@ func (s *Service) AdminCreateCandidate(
 	}
 
 	// Get stats
-	statsMap, _ := s.stats.GetCandidateStats(ctx, electionID)
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 	stats := statsMap[created.ID]
 
 	return &CandidateDetailDTO{
 		ID:               created.ID,
 		ElectionID:       created.ElectionID,
+		RaceID:           created.RaceID,
 		Number:           created.Number,
 		Name:             created.Name,
 		PhotoURL:         created.PhotoURL,
@@ -357,6 +384,7 @@ func (s *Service) AdminCreateCandidate(
 		SocialLinks:      created.SocialLinks,
 		Status:           string(created.Status),
 		Stats:            stats,
+		ResultsSealed:    sealed,
 	}, nil
 }
 
@@ -374,12 +402,13 @@ func (s *Service) AdminGetCandidate(
 		c.MediaFiles = mediaFiles
 	}
 
-	statsMap, _ := s.stats.GetCandidateStats(ctx, electionID)
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 	stats := statsMap[c.ID]
 
 	return &CandidateDetailDTO{
 		ID:               c.ID,
 		ElectionID:       c.ElectionID,
+		RaceID:           c.RaceID,
 		Number:           c.Number,
 		Name:             c.Name,
 		PhotoURL:         c.PhotoURL,
@@ -398,6 +427,7 @@ func (s *Service) AdminGetCandidate(
 		SocialLinks:      c.SocialLinks,
 		Status:           string(c.Status),
 		Stats:            stats,
+		ResultsSealed:    sealed,
 	}, nil
 }
 
@@ -425,6 +455,9 @@ func (s *Service) AdminUpdateCandidate(
 	}
 
 	// Apply updates
+	if req.RaceID != nil {
+		existing.RaceID = req.RaceID
+	}
 	if req.Number != nil {
 		existing.Number = *req.Number
 	}
@@ -480,12 +513,13 @@ func (s *Service) AdminUpdateCandidate(
 		return nil, err
 	}
 
-	statsMap, _ := s.stats.GetCandidateStats(ctx, electionID)
+	statsMap, sealed := s.candidateStats(ctx, electionID)
 	stats := statsMap[updated.ID]
 
 	return &CandidateDetailDTO{
 		ID:               updated.ID,
 		ElectionID:       updated.ElectionID,
+		RaceID:           updated.RaceID,
 		Number:           updated.Number,
 		Name:             updated.Name,
 		PhotoURL:         updated.PhotoURL,
@@ -504,6 +538,7 @@ func (s *Service) AdminUpdateCandidate(
 		SocialLinks:      updated.SocialLinks,
 		Status:           string(updated.Status),
 		Stats:            stats,
+		ResultsSealed:    sealed,
 	}, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"pemira-api/internal/election"
 )
 
 const qCandidateStats = `
@@ -11,7 +13,10 @@ SELECT
     c.id AS candidate_id,
     COALESCE(COUNT(v.id), 0) AS total_votes,
     COALESCE(
-        COUNT(v.id)::FLOAT / NULLIF((SELECT COUNT(*) FROM votes WHERE election_id = $1), 0) * 100,
+        COUNT(v.id)::FLOAT / NULLIF((
+            SELECT COUNT(*) FROM votes
+            WHERE election_id = $1 AND race_id IS NOT DISTINCT FROM c.race_id AND NOT is_abstain
+        ), 0) * 100,
         0
     ) AS percentage
 FROM candidates c
@@ -21,14 +26,23 @@ GROUP BY c.id
 ORDER BY total_votes DESC, c.number ASC
 `
 
+// WeightingRepository supplies the per-voter-type counts and weights behind
+// weighted tallies (implemented by monitoring.PgRepository), so candidate
+// stats and election results are computed from the same queries.
+type WeightingRepository interface {
+	GetVoteCountsByVoterType(ctx context.Context, electionID int64) ([]election.VoterTypeVoteCount, error)
+	GetVoterTypeWeights(ctx context.Context, electionID int64) ([]election.VoterTypeWeight, error)
+}
+
 // PgStatsProvider implements StatsProvider using PostgreSQL
 type PgStatsProvider struct {
-	db *pgxpool.Pool
+	db        *pgxpool.Pool
+	weighting WeightingRepository
 }
 
 // NewPgStatsProvider creates a new PostgreSQL stats provider
-func NewPgStatsProvider(db *pgxpool.Pool) *PgStatsProvider {
-	return &PgStatsProvider{db: db}
+func NewPgStatsProvider(db *pgxpool.Pool, weighting WeightingRepository) *PgStatsProvider {
+	return &PgStatsProvider{db: db, weighting: weighting}
 }
 
 // GetCandidateStats returns voting statistics for all candidates in an election
@@ -63,5 +77,38 @@ func (p *PgStatsProvider) GetCandidateStats(
 		return nil, err
 	}
 
+	if err := p.applyWeighting(ctx, electionID, statsMap); err != nil {
+		return nil, err
+	}
+
 	return statsMap, nil
 }
+
+// applyWeighting fills per-voter-type counts and the weighted share.
+func (p *PgStatsProvider) applyWeighting(ctx context.Context, electionID int64, statsMap CandidateStatsMap) error {
+	counts, err := p.weighting.GetVoteCountsByVoterType(ctx, electionID)
+	if err != nil {
+		return err
+	}
+	weights, err := p.weighting.GetVoterTypeWeights(ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	// A candidate runs in exactly one race, so its numbers come from that race's tally.
+	tallies := election.ComputeWeightedTallyByRace(counts, weights)
+	for candidateID, stats := range statsMap {
+		stats.VotesByVoterType = make(map[string]int64)
+		for _, tally := range tallies {
+			if _, ok := tally.CandidatePercentages[candidateID]; !ok {
+				continue
+			}
+			stats.WeightedPercentage = tally.CandidatePercentages[candidateID]
+			for _, pool := range tally.Constituencies {
+				stats.VotesByVoterType[pool.VoterType] = pool.CandidateVotes[candidateID]
+			}
+		}
+		statsMap[candidateID] = stats
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -18,6 +21,59 @@ type Config struct {
 	LogLevel string `envconfig:"LOG_LEVEL" default:"info"`
 
 	CORSAllowedOrigins string `envconfig:"CORS_ALLOWED_ORIGINS" default:"http://localhost:5173,http://localhost:3000"`
+
+	// ElectionSchedulerInterval is how often election statuses are advanced
+	// along their phase schedule; 0 disables the scheduler.
+	ElectionSchedulerInterval time.Duration `envconfig:"ELECTION_SCHEDULER_INTERVAL" default:"30s"`
+
+	// AuditChainInterval is how often committed audit entries are linked into
+	// their election's hash chain; 0 disables linking on this instance.
+	AuditChainInterval time.Duration `envconfig:"AUDIT_CHAIN_INTERVAL" default:"2s"`
+
+	// ResultSignoffsRequired is how many committee accounts must sign off
+	// computed results before they are certified.
+	ResultSignoffsRequired int `envconfig:"RESULT_SIGNOFFS_REQUIRED" default:"3"`
+
+	// AnalyticsCacheTTL is how long analytics dashboard results are reused
+	// before the queries run again; 0 disables the cache.
+	AnalyticsCacheTTL time.Duration `envconfig:"ANALYTICS_CACHE_TTL" default:"15s"`
+
+	// LiveCountInterval is how often live count changes are pushed to
+	// websocket subscribers.
+	LiveCountInterval time.Duration `envconfig:"LIVE_COUNT_INTERVAL" default:"1s"`
+
+	// RealtimeBackend carries websocket events between API instances:
+	// "postgres" (LISTEN/NOTIFY) or "memory" (this instance only).
+	RealtimeBackend string `envconfig:"REALTIME_BACKEND" default:"postgres"`
+
+	// TPSQRPeriod is how often the rotating TPS check-in QR changes.
+	TPSQRPeriod time.Duration `envconfig:"TPS_QR_PERIOD" default:"30s"`
+	// TPSQRSkewWindows is how many periods either side of the current one a
+	// rotating code is still accepted, allowing for clock skew and slow scans.
+	TPSQRSkewWindows int `envconfig:"TPS_QR_SKEW_WINDOWS" default:"1"`
+	// TPSQRStaticAllowed keeps accepting the printed static TPS QR.
+	TPSQRStaticAllowed bool `envconfig:"TPS_QR_STATIC_ALLOWED" default:"true"`
+
+	// BallotQRSecret is the server secret the signing keys of printed ballot
+	// QR codes are derived from; ballot QR voting is unavailable when unset.
+	// Changing it invalidates every printed code.
+	BallotQRSecret string `envconfig:"BALLOT_QR_SECRET"`
+
+	// IdempotencySecret keys the hashes that identify vote casts retried
+	// under an Idempotency-Key; JWTSecret when unset.
+	IdempotencySecret string `envconfig:"IDEMPOTENCY_SECRET"`
+
+	// IdempotencyKeyTTL is how long responses stored under an Idempotency-Key
+	// are replayed before the key may be used again.
+	IdempotencyKeyTTL time.Duration `envconfig:"IDEMPOTENCY_KEY_TTL" default:"24h"`
+
+	// CheckinSweepInterval is how often stale TPS check-ins are expired; 0
+	// disables the sweeper. Pending check-ins expire CheckinPendingTTL after
+	// the scan, approved ones at their expires_at (or CheckinApprovedTTL after
+	// approval when unset).
+	CheckinSweepInterval time.Duration `envconfig:"CHECKIN_SWEEP_INTERVAL" default:"1m"`
+	CheckinPendingTTL    time.Duration `envconfig:"CHECKIN_PENDING_TTL" default:"30m"`
+	CheckinApprovedTTL   time.Duration `envconfig:"CHECKIN_APPROVED_TTL" default:"15m"`
 }
 
 func Load() (*Config, error) {
@@ -25,5 +81,13 @@ func Load() (*Config, error) {
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, err
 	}
+	switch cfg.RealtimeBackend {
+	case "postgres", "memory":
+	default:
+		return nil, fmt.Errorf("REALTIME_BACKEND must be \"postgres\" or \"memory\", got %q", cfg.RealtimeBackend)
+	}
+	if cfg.IdempotencySecret == "" {
+		cfg.IdempotencySecret = cfg.JWTSecret
+	}
 	return &cfg, nil
 }
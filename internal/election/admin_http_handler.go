@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -220,11 +221,22 @@ func (h *AdminHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	dto, err := h.svc.Update(ctx, id, req)
 	if err != nil {
-		if errors.Is(err, ErrElectionNotFound) {
+		switch {
+		case errors.Is(err, ErrElectionNotFound):
 			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
-			return
+		case errors.Is(err, ErrElectionArchived):
+			response.BadRequest(w, "ELECTION_ARCHIVED", "Pemilu yang sudah diarsipkan tidak bisa diubah.")
+		case errors.Is(err, ErrVotingPhaseLocked):
+			response.BadRequest(w, "VOTING_PHASE_LOCKED", "Jadwal voting tidak bisa diubah setelah voting dibuka.")
+		case errors.Is(err, ErrElectionAlreadyStarted):
+			response.BadRequest(w, "ELECTION_ALREADY_STARTED", "Mode tidak bisa diubah karena pemilu sudah berjalan.")
+		case errors.Is(err, ErrInvalidModeCombination):
+			response.BadRequest(w, "INVALID_MODE_COMBINATION", "online_enabled dan tps_enabled tidak boleh keduanya false.")
+		case errors.Is(err, ErrPhaseTimeConflict):
+			response.BadRequest(w, "PHASE_TIME_CONFLICT", "voting_start_at harus sebelum voting_end_at.")
+		default:
+			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengubah pemilu.")
 		}
-		response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengubah pemilu.")
 		return
 	}
 
@@ -258,7 +270,7 @@ func (h *AdminHandler) PatchGeneralInfo(w http.ResponseWriter, r *http.Request)
 			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
 			return
 		case errors.Is(err, ErrElectionArchived):
-			response.BadRequest(w, "ELECTION_ARCHIVED", "Pemilu sudah diarsipkan.")
+			response.BadRequest(w, "ELECTION_ALREADY_ARCHIVED", "Pemilu sudah diarsipkan.")
 			return
 		default:
 			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengubah informasi pemilu.")
@@ -289,7 +301,13 @@ func (h *AdminHandler) OpenVoting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dto, err := h.svc.OpenVoting(ctx, id)
+	actor, ok := statusActor(r)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	dto, err := h.svc.OpenVoting(ctx, id, actor)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrElectionNotFound):
@@ -304,6 +322,12 @@ func (h *AdminHandler) OpenVoting(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, ErrElectionNotInVotingPhase):
 			response.BadRequest(w, "ELECTION_NOT_IN_VOTING_PHASE", "Pemilu belum memasuki jadwal voting.")
 			return
+		case errors.Is(err, ErrNoPublishedCandidates):
+			response.UnprocessableEntity(w, "NO_PUBLISHED_CANDIDATE", "Voting tidak bisa dibuka sebelum ada kandidat yang dipublikasikan.")
+			return
+		case errors.Is(err, ErrEmptyDPT):
+			response.UnprocessableEntity(w, "EMPTY_DPT", "Voting tidak bisa dibuka karena DPT masih kosong.")
+			return
 		default:
 			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal membuka voting.")
 			return
@@ -336,7 +360,13 @@ func (h *AdminHandler) CloseVoting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dto, err := h.svc.CloseVoting(ctx, id)
+	actor, ok := statusActor(r)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	dto, err := h.svc.CloseVoting(ctx, id, actor)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrElectionNotFound):
@@ -348,6 +378,9 @@ func (h *AdminHandler) CloseVoting(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, ErrElectionAlreadyClosed):
 			response.BadRequest(w, "ELECTION_ALREADY_CLOSED", "Pemilu sudah ditutup.")
 			return
+		case errors.Is(err, ErrInvalidStatusChange):
+			response.Conflict(w, "INVALID_STATUS_CHANGE", "Status pemilu berubah, muat ulang lalu coba lagi.")
+			return
 		default:
 			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal menutup voting.")
 			return
@@ -376,7 +409,13 @@ func (h *AdminHandler) Archive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dto, err := h.svc.Archive(ctx, id)
+	actor, ok := statusActor(r)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	dto, err := h.svc.Archive(ctx, id, actor)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrElectionNotFound):
@@ -388,6 +427,9 @@ func (h *AdminHandler) Archive(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, ErrElectionNotClosable):
 			response.BadRequest(w, "ELECTION_NOT_CLOSABLE", "Pemilu belum bisa diarsipkan.")
 			return
+		case errors.Is(err, ErrInvalidStatusChange):
+			response.Conflict(w, "INVALID_STATUS_CHANGE", "Status pemilu berubah, muat ulang lalu coba lagi.")
+			return
 		default:
 			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengarsipkan pemilu.")
 			return
@@ -402,6 +444,113 @@ func (h *AdminHandler) Archive(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, successPayload(resp))
 }
 
+// statusActor builds the actor of a status action from the admin in the
+// request context and the optional {"reason": "..."} body.
+func statusActor(r *http.Request) (StatusActor, bool) {
+	adminID, ok := ctxkeys.GetUserID(r.Context())
+	if !ok {
+		return StatusActor{}, false
+	}
+	var req StatusChangeRequest
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+	}
+	return AdminActor(adminID, strings.TrimSpace(req.Reason)), true
+}
+
+func (h *AdminHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	history, err := h.svc.GetStatusHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrElectionNotFound) {
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+			return
+		}
+		response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengambil riwayat status pemilu.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(history))
+}
+
+func (h *AdminHandler) ExtendVoting(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	adminID, ok := ctxkeys.GetUserID(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	var req VotingExtensionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	result, err := h.svc.ExtendVoting(ctx, id, req, AdminActor(adminID, req.Reason))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrElectionNotFound):
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+		case errors.Is(err, ErrExtensionReasonRequired):
+			response.BadRequest(w, "REASON_REQUIRED", "Alasan perpanjangan voting wajib diisi.")
+		case errors.Is(err, ErrInvalidExtensionEnd):
+			response.BadRequest(w, "INVALID_VOTING_END", "voting_end_at harus di masa depan dan setelah akhir voting saat ini.")
+		case errors.Is(err, ErrResultsPublished):
+			response.Conflict(w, "RESULTS_PUBLISHED", "Hasil pemilu sudah dipublikasikan, voting tidak bisa diperpanjang.")
+		case errors.Is(err, ErrElectionNotInVotingPhase):
+			response.BadRequest(w, "ELECTION_NOT_IN_VOTING_PHASE", "Voting belum pernah dibuka untuk pemilu ini.")
+		case errors.Is(err, ErrTPSModeDisabled):
+			response.BadRequest(w, "TPS_DISABLED", "Mode TPS tidak aktif untuk pemilu ini.")
+		case errors.Is(err, ErrTPSNotInElection):
+			response.BadRequest(w, "TPS_NOT_IN_ELECTION", "Ada TPS yang bukan milik pemilu ini.")
+		case errors.Is(err, ErrInvalidStatusChange):
+			response.Conflict(w, "INVALID_STATUS_CHANGE", "Status pemilu berubah, muat ulang lalu coba lagi.")
+		default:
+			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal memperpanjang voting.")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(result))
+}
+
+func (h *AdminHandler) ListVotingExtensions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	extensions, err := h.svc.ListVotingExtensions(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrElectionNotFound) {
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+			return
+		}
+		response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengambil riwayat perpanjangan voting.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(extensions))
+}
+
 func (h *AdminHandler) GetPhases(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -509,6 +658,9 @@ func (h *AdminHandler) UpdateModeSettings(w http.ResponseWriter, r *http.Request
 		case errors.Is(err, ErrInvalidModeCombination):
 			response.BadRequest(w, "INVALID_MODE_COMBINATION", "online_enabled dan tps_enabled tidak boleh keduanya false.")
 			return
+		case errors.Is(err, ErrInvalidVotingSystem):
+			response.BadRequest(w, "INVALID_VOTING_SYSTEM", "voting_system harus PLURALITY atau IRV.")
+			return
 		case errors.Is(err, ErrElectionAlreadyStarted):
 			response.BadRequest(w, "ELECTION_ALREADY_STARTED", "Mode tidak bisa diubah karena pemilu sudah berjalan.")
 			return
@@ -521,6 +673,333 @@ func (h *AdminHandler) UpdateModeSettings(w http.ResponseWriter, r *http.Request
 	response.JSON(w, http.StatusOK, successPayload(dto))
 }
 
+func (h *AdminHandler) GetVoterTypeWeights(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	dto, err := h.svc.GetVoterTypeWeights(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrElectionNotFound) {
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+			return
+		}
+		response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengambil bobot suara.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(dto))
+}
+
+func (h *AdminHandler) UpdateVoterTypeWeights(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	var req VoterTypeWeightsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	dto, err := h.svc.UpdateVoterTypeWeights(ctx, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrElectionNotFound):
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+			return
+		case errors.Is(err, ErrInvalidVoterTypeWeight):
+			response.BadRequest(w, "INVALID_WEIGHT", "voter_type harus STUDENT, LECTURER atau STAFF dengan bobot >= 0, minimal satu bobot > 0.")
+			return
+		case errors.Is(err, ErrDuplicateVoterTypeWeight):
+			response.BadRequest(w, "DUPLICATE_VOTER_TYPE", "voter_type tidak boleh duplikat.")
+			return
+		case errors.Is(err, ErrElectionAlreadyStarted):
+			response.BadRequest(w, "ELECTION_ALREADY_STARTED", "Bobot suara tidak bisa diubah karena pemilu sudah berjalan.")
+			return
+		default:
+			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal memperbarui bobot suara.")
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(dto))
+}
+
+func (h *AdminHandler) ListRaces(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	races, err := h.svc.ListRaces(ctx, id)
+	if err != nil {
+		writeRaceError(w, err, "Gagal mengambil daftar posisi.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(races))
+}
+
+func (h *AdminHandler) CreateRace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	var req ElectionRaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	race, err := h.svc.CreateRace(ctx, id, req)
+	if err != nil {
+		writeRaceError(w, err, "Gagal membuat posisi.")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, successPayload(race))
+}
+
+func (h *AdminHandler) UpdateRace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+	raceID, err := parseIDParam(r, "raceID")
+	if err != nil || raceID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "raceID tidak valid.")
+		return
+	}
+
+	var req ElectionRaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	race, err := h.svc.UpdateRace(ctx, id, raceID, req)
+	if err != nil {
+		writeRaceError(w, err, "Gagal memperbarui posisi.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(race))
+}
+
+func (h *AdminHandler) DeleteRace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+	raceID, err := parseIDParam(r, "raceID")
+	if err != nil || raceID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "raceID tidak valid.")
+		return
+	}
+
+	if err := h.svc.DeleteRace(ctx, id, raceID); err != nil {
+		writeRaceError(w, err, "Gagal menghapus posisi.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(map[string]any{"id": raceID, "deleted": true}))
+}
+
+func (h *AdminHandler) GetIRVReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	report, err := h.svc.GetIRVReport(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrElectionNotFound):
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+		case errors.Is(err, ErrNotRankedElection):
+			response.BadRequest(w, "NOT_RANKED_ELECTION", "Pemilu tidak menggunakan sistem ranked-choice (IRV).")
+		case errors.Is(err, ErrResultsSealed):
+			response.Forbidden(w, "RESULTS_SEALED", "Perolehan suara kandidat disegel sampai voting ditutup.")
+		default:
+			response.InternalServerError(w, "INTERNAL_ERROR", "Gagal menghitung hasil IRV.")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(report))
+}
+
+func (h *AdminHandler) ComputeResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	adminID, ok := ctxkeys.GetUserID(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	result, err := h.svc.ComputeResults(ctx, id, AdminActor(adminID, ""))
+	if err != nil {
+		writeResultError(w, err, "Gagal menghitung hasil pemilu.")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, successPayload(result))
+}
+
+func (h *AdminHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	result, err := h.svc.GetResults(ctx, id)
+	if err != nil {
+		writeResultError(w, err, "Gagal mengambil hasil pemilu.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(result))
+}
+
+func (h *AdminHandler) SignOffResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	adminID, ok := ctxkeys.GetUserID(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	var req ResultSignoffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.TallyHash) == "" {
+		response.BadRequest(w, "VALIDATION_ERROR", "tally_hash wajib diisi.")
+		return
+	}
+
+	result, err := h.svc.SignOffResults(ctx, id, AdminActor(adminID, ""), req)
+	if err != nil {
+		writeResultError(w, err, "Gagal menandatangani hasil pemilu.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(result))
+}
+
+func (h *AdminHandler) PublishResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "electionID")
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	adminID, ok := ctxkeys.GetUserID(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "User tidak valid.")
+		return
+	}
+
+	result, err := h.svc.PublishResults(ctx, id, AdminActor(adminID, ""))
+	if err != nil {
+		writeResultError(w, err, "Gagal mempublikasikan hasil pemilu.")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, successPayload(result))
+}
+
+func writeResultError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrElectionNotFound):
+		response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+	case errors.Is(err, ErrResultNotFound):
+		response.NotFound(w, "RESULT_NOT_FOUND", "Hasil pemilu belum dihitung.")
+	case errors.Is(err, ErrElectionArchived):
+		response.BadRequest(w, "ELECTION_ALREADY_ARCHIVED", "Pemilu sudah diarsipkan.")
+	case errors.Is(err, ErrVotingNotClosed):
+		response.BadRequest(w, "VOTING_NOT_CLOSED", "Hasil hanya bisa dihitung setelah voting ditutup.")
+	case errors.Is(err, ErrResultAlreadyCertified):
+		response.Conflict(w, "RESULT_ALREADY_CERTIFIED", "Hasil pemilu sudah disahkan dan tidak bisa diubah.")
+	case errors.Is(err, ErrResultHashMismatch):
+		response.Conflict(w, "RESULT_HASH_MISMATCH", "Hash hasil tidak cocok, muat ulang hasil lalu periksa kembali.")
+	case errors.Is(err, ErrAlreadySignedOff):
+		response.Conflict(w, "ALREADY_SIGNED_OFF", "Akun ini sudah menandatangani hasil pemilu.")
+	case errors.Is(err, ErrSignerRequired):
+		response.Forbidden(w, "FORBIDDEN", "Hasil pemilu hanya bisa ditandatangani oleh akun panitia.")
+	case errors.Is(err, ErrResultNotCertified):
+		response.Conflict(w, "RESULT_NOT_CERTIFIED", "Hasil pemilu belum disahkan oleh panitia.")
+	case errors.Is(err, ErrResultAlreadyPublished):
+		response.Conflict(w, "RESULT_ALREADY_PUBLISHED", "Hasil pemilu sudah dipublikasikan.")
+	case errors.Is(err, ErrResultTampered):
+		response.InternalServerError(w, "RESULT_TAMPERED", "Data hasil pemilu tidak sesuai dengan hash-nya.")
+	default:
+		response.InternalServerError(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
+func writeRaceError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrElectionNotFound):
+		response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+	case errors.Is(err, ErrRaceNotFound):
+		response.NotFound(w, "RACE_NOT_FOUND", "Posisi tidak ditemukan.")
+	case errors.Is(err, ErrInvalidRace):
+		response.BadRequest(w, "VALIDATION_ERROR", "code dan name wajib diisi, id fakultas/prodi harus valid.")
+	case errors.Is(err, ErrInvalidRaceMaster):
+		response.BadRequest(w, "INVALID_MASTER_DATA", "Fakultas atau program studi tidak ditemukan.")
+	case errors.Is(err, ErrRaceCodeTaken):
+		response.Conflict(w, "RACE_CODE_TAKEN", "Kode posisi sudah digunakan pada pemilu ini.")
+	case errors.Is(err, ErrRaceHasCandidates):
+		response.Conflict(w, "RACE_HAS_CANDIDATES", "Posisi masih memiliki kandidat atau suara.")
+	case errors.Is(err, ErrRacesLocked):
+		response.BadRequest(w, "ELECTION_ALREADY_STARTED", "Posisi tidak bisa diubah karena pemilu sudah berjalan.")
+	default:
+		response.InternalServerError(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
 func (h *AdminHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -769,6 +1248,13 @@ func (h *AdminHandler) GetAllSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Get voter type weights
+	weights, err := h.svc.GetVoterTypeWeights(ctx, electionID)
+	if err != nil {
+		response.InternalServerError(w, "INTERNAL_ERROR", "Gagal mengambil bobot suara.")
+		return
+	}
+
 	// Get branding
 	branding, err := h.svc.GetBranding(ctx, electionID)
 	if err != nil {
@@ -778,10 +1264,11 @@ func (h *AdminHandler) GetAllSettings(w http.ResponseWriter, r *http.Request) {
 
 	// Combine all settings
 	settings := map[string]interface{}{
-		"election":      buildGeneralInfoResponse(election),
-		"phases":        phases,
-		"mode_settings": modeSettings,
-		"branding":      branding,
+		"election":           buildGeneralInfoResponse(election),
+		"phases":             phases,
+		"mode_settings":      modeSettings,
+		"voter_type_weights": weights,
+		"branding":           branding,
 	}
 
 	response.JSON(w, http.StatusOK, settings)
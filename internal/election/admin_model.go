@@ -126,6 +126,9 @@ type ModeSettingsDTO struct {
 	ElectionID     int64             `json:"election_id"`
 	OnlineEnabled  bool              `json:"online_enabled"`
 	TPSEnabled     bool              `json:"tps_enabled"`
+	AbstainEnabled bool              `json:"abstain_enabled"`
+	VotingSystem   string            `json:"voting_system"`
+	ResultsSealed  bool              `json:"results_sealed"`
 	OnlineSettings OnlineSettingsDTO `json:"online_settings"`
 	TPSSettings    TPSSettingsDTO    `json:"tps_settings"`
 	UpdatedAt      time.Time         `json:"updated_at"`
@@ -134,6 +137,9 @@ type ModeSettingsDTO struct {
 type ModeSettingsRequest struct {
 	OnlineEnabled  *bool               `json:"online_enabled,omitempty"`
 	TPSEnabled     *bool               `json:"tps_enabled,omitempty"`
+	AbstainEnabled *bool               `json:"abstain_enabled,omitempty"`
+	VotingSystem   *string             `json:"voting_system,omitempty"`
+	ResultsSealed  *bool               `json:"results_sealed,omitempty"`
 	OnlineSettings *OnlineSettingsBody `json:"online_settings,omitempty"`
 	TPSSettings    *TPSSettingsBody    `json:"tps_settings,omitempty"`
 }
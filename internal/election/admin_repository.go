@@ -1,9 +1,6 @@
 package election
 
-import (
-	"context"
-	"time"
-)
+import "context"
 
 type AdminRepository interface {
 	ListElections(ctx context.Context, filter AdminElectionListFilter) ([]AdminElectionDTO, int64, error)
@@ -11,12 +8,34 @@ type AdminRepository interface {
 	CreateElection(ctx context.Context, req AdminElectionCreateRequest) (*AdminElectionDTO, error)
 	UpdateElection(ctx context.Context, id int64, req AdminElectionUpdateRequest) (*AdminElectionDTO, error)
 	UpdateGeneralInfo(ctx context.Context, id int64, req AdminElectionGeneralUpdateRequest) (*AdminElectionDTO, error)
-	SetVotingStatus(ctx context.Context, id int64, status ElectionStatus, currentPhase *string, votingStartAt, votingEndAt *time.Time) (*AdminElectionDTO, error)
+	TransitionStatus(ctx context.Context, t StatusTransition) (*AdminElectionDTO, error)
+	GetTransitionPrerequisites(ctx context.Context, id int64) (*TransitionPrerequisites, error)
+	ListStatusHistory(ctx context.Context, id int64) ([]StatusHistoryEntry, error)
+	ExtendVoting(ctx context.Context, w VotingExtensionWrite) (*VotingExtensionResult, error)
+	ListVotingExtensions(ctx context.Context, electionID int64) ([]VotingExtension, error)
+	HasOpenVotingExtension(ctx context.Context, electionID int64) (bool, error)
+	ResultsSealed(ctx context.Context, electionID int64) (bool, error)
+	ListResultCandidates(ctx context.Context, electionID int64) ([]ResultCandidateRow, error)
+	ListResultVoteRows(ctx context.Context, electionID int64) ([]ResultVoteRow, error)
+	CreateResult(ctx context.Context, electionID int64, tally []byte, tallyHash string, signoffsRequired int, computedBy *int64) (*ElectionResult, error)
+	GetCurrentResult(ctx context.Context, electionID int64) (*ElectionResult, error)
+	AddResultSignoff(ctx context.Context, resultID, userID int64, tallyHash string, note *string) (*ElectionResult, error)
+	PublishResult(ctx context.Context, resultID int64, userID *int64) (*ElectionResult, error)
+	ListSchedulableElections(ctx context.Context) ([]AdminElectionDTO, error)
 	GetPhases(ctx context.Context, id int64) (*AdminElectionDTO, error)
 	UpdatePhases(ctx context.Context, id int64, phases []ElectionPhaseInput) (*AdminElectionDTO, error)
 	GetModeSettings(ctx context.Context, id int64) (*ModeSettingsDTO, error)
 	UpdateModeSettings(ctx context.Context, id int64, req ModeSettingsRequest) (*ModeSettingsDTO, error)
 	GetSummary(ctx context.Context, id int64) (*ElectionSummaryDTO, error)
+	GetVoterTypeWeights(ctx context.Context, id int64) ([]VoterTypeWeight, error)
+	ReplaceVoterTypeWeights(ctx context.Context, id int64, weights []VoterTypeWeight) ([]VoterTypeWeight, error)
+	ListRaces(ctx context.Context, electionID int64) ([]ElectionRace, error)
+	GetRace(ctx context.Context, electionID, raceID int64) (*ElectionRace, error)
+	CreateRace(ctx context.Context, electionID int64, req ElectionRaceRequest) (*ElectionRace, error)
+	UpdateRace(ctx context.Context, electionID, raceID int64, req ElectionRaceRequest) (*ElectionRace, error)
+	DeleteRace(ctx context.Context, electionID, raceID int64) error
+	ListRankedCandidates(ctx context.Context, electionID int64) (map[int64][]int64, error)
+	ListRankedBallots(ctx context.Context, electionID int64) ([]RankedBallot, error)
 	GetBranding(ctx context.Context, electionID int64) (*BrandingSettings, error)
 	GetBrandingFile(ctx context.Context, electionID int64, slot BrandingSlot) (*BrandingFile, error)
 	SaveBrandingFile(ctx context.Context, electionID int64, slot BrandingSlot, file BrandingFileCreate) (*BrandingFile, error)
@@ -3,6 +3,7 @@ package election
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -12,7 +13,10 @@ import (
 	storage_go "github.com/supabase-community/storage-go"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"pemira-api/internal/shared/ctxkeys"
 )
 
 type PgAdminRepository struct {
@@ -365,31 +369,263 @@ RETURNING %s
 	return scanAdminElection(r.db.QueryRow(ctx, query, args...))
 }
 
-func (r *PgAdminRepository) SetVotingStatus(
-	ctx context.Context,
-	id int64,
-	status ElectionStatus,
-	currentPhase *string,
-	votingStartAt, votingEndAt *time.Time,
-) (*AdminElectionDTO, error) {
+// TransitionStatus applies a status change only if the election is still in
+// t.From, so concurrent changes are not overwritten, and records it in
+// election_status_history in the same transaction.
+func (r *PgAdminRepository) TransitionStatus(ctx context.Context, t StatusTransition) (*AdminElectionDTO, error) {
 	const q = `
 UPDATE myschema.elections
 SET
-    status = $2,
-    current_phase = COALESCE($3, current_phase),
-    voting_start_at = COALESCE($4, voting_start_at),
-    voting_end_at   = COALESCE($5, voting_end_at),
+    status = $3,
+    current_phase = COALESCE($4, current_phase),
+    voting_start_at = COALESCE($5, voting_start_at),
+    voting_end_at   = COALESCE($6, voting_end_at),
     updated_at      = NOW()
-WHERE id = $1
+WHERE id = $1 AND status = $2
 RETURNING %s
 `
-	return scanAdminElection(r.db.QueryRow(ctx, fmt.Sprintf(q, adminElectionColumns),
-		id,
-		status,
-		currentPhase,
-		votingStartAt,
-		votingEndAt,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	dto, err := scanAdminElection(tx.QueryRow(ctx, fmt.Sprintf(q, adminElectionColumns),
+		t.ElectionID,
+		t.From,
+		t.To,
+		t.CurrentPhase,
+		t.VotingStartAt,
+		t.VotingEndAt,
 	))
+	if err != nil {
+		if errors.Is(err, ErrElectionNotFound) {
+			return nil, ErrInvalidStatusChange
+		}
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_status_history (election_id, from_status, to_status, actor_type, actor_user_id, reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, t.ElectionID, t.From, t.To, t.ActorType, t.ActorUserID, t.Reason); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// GetTransitionPrerequisites counts what the transition guards check:
+// published candidates and voters in the DPT (not rejected or blocked).
+func (r *PgAdminRepository) GetTransitionPrerequisites(ctx context.Context, id int64) (*TransitionPrerequisites, error) {
+	const q = `
+SELECT
+    (SELECT COUNT(*) FROM myschema.candidates c WHERE c.election_id = $1 AND c.status = 'APPROVED'),
+    (SELECT COUNT(*) FROM myschema.election_voters ev WHERE ev.election_id = $1 AND ev.status NOT IN ('REJECTED', 'BLOCKED'))
+`
+	var pre TransitionPrerequisites
+	if err := r.db.QueryRow(ctx, q, id).Scan(&pre.PublishedCandidates, &pre.DPTVoters); err != nil {
+		return nil, err
+	}
+	return &pre, nil
+}
+
+func (r *PgAdminRepository) ListStatusHistory(ctx context.Context, id int64) ([]StatusHistoryEntry, error) {
+	const q = `
+SELECT id, election_id, from_status, to_status, actor_type, actor_user_id, reason, created_at
+FROM myschema.election_status_history
+WHERE election_id = $1
+ORDER BY created_at DESC, id DESC
+`
+	rows, err := r.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]StatusHistoryEntry, 0)
+	for rows.Next() {
+		var h StatusHistoryEntry
+		if err := rows.Scan(&h.ID, &h.ElectionID, &h.FromStatus, &h.ToStatus, &h.ActorType, &h.ActorUserID, &h.Reason, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// ExtendVoting records a voting extension together with its audit entry.
+// An election-wide extension moves voting_end_at, reopening voting when
+// w.Reopen is set; a per-TPS extension leaves the election row untouched.
+func (r *PgAdminRepository) ExtendVoting(ctx context.Context, w VotingExtensionWrite) (*VotingExtensionResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var dto *AdminElectionDTO
+	if len(w.TPSIDs) == 0 {
+		dto, err = scanAdminElection(tx.QueryRow(ctx, fmt.Sprintf(`
+UPDATE myschema.elections
+SET
+    status = CASE WHEN $3 THEN 'VOTING_OPEN' ELSE status END,
+    current_phase = CASE WHEN $3 THEN 'VOTING' ELSE current_phase END,
+    voting_end_at = $4,
+    updated_at = NOW()
+WHERE id = $1 AND status = $2
+RETURNING %s
+`, adminElectionColumns), w.ElectionID, w.From, w.Reopen, w.VotingEndAt))
+	} else {
+		dto, err = scanAdminElection(tx.QueryRow(ctx, fmt.Sprintf(`
+SELECT %s
+FROM myschema.elections
+WHERE id = $1 AND status = $2
+FOR UPDATE
+`, adminElectionColumns), w.ElectionID, w.From))
+	}
+	if err != nil {
+		if errors.Is(err, ErrElectionNotFound) {
+			return nil, ErrInvalidStatusChange
+		}
+		return nil, err
+	}
+
+	if w.Reopen {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_status_history (election_id, from_status, to_status, actor_type, actor_user_id, reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, w.ElectionID, w.From, ElectionStatusVotingOpen, StatusActorAdmin, w.ActorUserID, w.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	tpsIDs := []*int64{nil}
+	if len(w.TPSIDs) > 0 {
+		var found int
+		if err := tx.QueryRow(ctx, `
+SELECT COUNT(*) FROM myschema.tps WHERE election_id = $1 AND id = ANY($2)
+`, w.ElectionID, w.TPSIDs).Scan(&found); err != nil {
+			return nil, err
+		}
+		if found != len(w.TPSIDs) {
+			return nil, ErrTPSNotInElection
+		}
+		tpsIDs = make([]*int64, len(w.TPSIDs))
+		for i := range w.TPSIDs {
+			tpsIDs[i] = &w.TPSIDs[i]
+		}
+	}
+
+	extensions := make([]VotingExtension, 0, len(tpsIDs))
+	for _, tpsID := range tpsIDs {
+		var ext VotingExtension
+		if err := tx.QueryRow(ctx, `
+INSERT INTO myschema.voting_extensions (election_id, tps_id, previous_end_at, voting_end_at, reason, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, election_id, tps_id, previous_end_at, voting_end_at, reason, created_by, created_at
+`, w.ElectionID, tpsID, w.PreviousEndAt, w.VotingEndAt, w.Reason, w.ActorUserID).Scan(
+			&ext.ID, &ext.ElectionID, &ext.TPSID, &ext.PreviousEndAt, &ext.VotingEndAt, &ext.Reason, &ext.CreatedBy, &ext.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, ext)
+	}
+
+	// A pending results snapshot no longer covers every vote.
+	if _, err := tx.Exec(ctx, `
+UPDATE myschema.election_results SET status = 'SUPERSEDED'
+WHERE election_id = $1 AND status = 'PENDING_SIGNOFF'
+`, w.ElectionID); err != nil {
+		return nil, err
+	}
+
+	if err := insertAuditLog(ctx, tx, w.ElectionID, w.ActorUserID, "VOTING_EXTENDED", "ELECTION", w.ElectionID, map[string]any{
+		"voting_end_at":   w.VotingEndAt,
+		"previous_end_at": w.PreviousEndAt,
+		"tps_ids":         w.TPSIDs,
+		"reopened":        w.Reopen,
+		"reason":          w.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &VotingExtensionResult{Election: dto, Reopened: w.Reopen, Extensions: extensions}, nil
+}
+
+// insertAuditLog writes an admin action to audit_logs within tx, with the
+// client address taken from the request context.
+func insertAuditLog(ctx context.Context, tx pgx.Tx, electionID int64, actorUserID *int64, action, entityType string, entityID int64, metadata map[string]any) error {
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	var ipAddress, userAgent *string
+	if ip, ok := ctxkeys.GetIPAddress(ctx); ok && ip != "" {
+		ipAddress = &ip
+	}
+	if ua, ok := ctxkeys.GetUserAgent(ctx); ok && ua != "" {
+		userAgent = &ua
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO myschema.audit_logs (election_id, actor_user_id, action, entity_type, entity_id, metadata, ip_address, user_agent)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`, electionID, actorUserID, action, entityType, entityID, metaJSON, ipAddress, userAgent)
+	return err
+}
+
+func (r *PgAdminRepository) ListVotingExtensions(ctx context.Context, electionID int64) ([]VotingExtension, error) {
+	const q = `
+SELECT id, election_id, tps_id, previous_end_at, voting_end_at, reason, created_by, created_at
+FROM myschema.voting_extensions
+WHERE election_id = $1
+ORDER BY created_at DESC, id DESC
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	extensions := make([]VotingExtension, 0)
+	for rows.Next() {
+		var ext VotingExtension
+		if err := rows.Scan(&ext.ID, &ext.ElectionID, &ext.TPSID, &ext.PreviousEndAt, &ext.VotingEndAt, &ext.Reason, &ext.CreatedBy, &ext.CreatedAt); err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions, rows.Err()
+}
+
+// ListSchedulableElections returns the elections the scheduler may advance.
+func (r *PgAdminRepository) ListSchedulableElections(ctx context.Context) ([]AdminElectionDTO, error) {
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+SELECT %s
+FROM myschema.elections
+WHERE status <> 'ARCHIVED'
+ORDER BY id
+`, adminElectionColumns))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	elections := make([]AdminElectionDTO, 0)
+	for rows.Next() {
+		dto, err := scanAdminElection(rows)
+		if err != nil {
+			return nil, err
+		}
+		elections = append(elections, *dto)
+	}
+	return elections, rows.Err()
 }
 
 func (r *PgAdminRepository) UpdateGeneralInfo(ctx context.Context, id int64, req AdminElectionGeneralUpdateRequest) (*AdminElectionDTO, error) {
@@ -488,6 +724,9 @@ func (r *PgAdminRepository) GetModeSettings(ctx context.Context, id int64) (*Mod
 SELECT
     online_enabled,
     tps_enabled,
+    abstain_enabled,
+    voting_system,
+    results_sealed,
     online_login_url,
     online_max_sessions_per_voter,
     tps_require_checkin,
@@ -503,6 +742,9 @@ WHERE id = $1
 	err := r.db.QueryRow(ctx, q, id).Scan(
 		&dto.OnlineEnabled,
 		&dto.TPSEnabled,
+		&dto.AbstainEnabled,
+		&dto.VotingSystem,
+		&dto.ResultsSealed,
 		&dto.OnlineSettings.LoginURL,
 		&dto.OnlineSettings.MaxSessionsPerVoter,
 		&dto.TPSSettings.RequireCheckin,
@@ -530,11 +772,17 @@ SET
     tps_require_checkin = COALESCE($6, tps_require_checkin),
     tps_require_ballot_qr = COALESCE($7, tps_require_ballot_qr),
     tps_max = COALESCE($8, tps_max),
+    abstain_enabled = COALESCE($9, abstain_enabled),
+    voting_system = COALESCE($10, voting_system),
+    results_sealed = COALESCE($11, results_sealed),
     updated_at = NOW()
 WHERE id = $1
 RETURNING
     online_enabled,
     tps_enabled,
+    abstain_enabled,
+    voting_system,
+    results_sealed,
     online_login_url,
     online_max_sessions_per_voter,
     tps_require_checkin,
@@ -564,9 +812,15 @@ RETURNING
 		nullableBool(tpsSettings, func(s *TPSSettingsBody) *bool { return s.RequireCheckin }),
 		nullableBool(tpsSettings, func(s *TPSSettingsBody) *bool { return s.RequireBallotQR }),
 		nullableInt(tpsSettings, func(s *TPSSettingsBody) *int { return s.MaxTPS }),
+		req.AbstainEnabled,
+		req.VotingSystem,
+		req.ResultsSealed,
 	).Scan(
 		&dto.OnlineEnabled,
 		&dto.TPSEnabled,
+		&dto.AbstainEnabled,
+		&dto.VotingSystem,
+		&dto.ResultsSealed,
 		&dto.OnlineSettings.LoginURL,
 		&dto.OnlineSettings.MaxSessionsPerVoter,
 		&dto.TPSSettings.RequireCheckin,
@@ -635,6 +889,294 @@ WHERE e.id = $1
 	return &dto, nil
 }
 
+func (r *PgAdminRepository) GetVoterTypeWeights(ctx context.Context, id int64) ([]VoterTypeWeight, error) {
+	const q = `
+SELECT voter_type, weight::float8
+FROM myschema.election_voter_type_weights
+WHERE election_id = $1
+ORDER BY CASE voter_type WHEN 'STUDENT' THEN 0 WHEN 'LECTURER' THEN 1 ELSE 2 END
+`
+	rows, err := r.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weights := make([]VoterTypeWeight, 0, 3)
+	for rows.Next() {
+		var w VoterTypeWeight
+		if err := rows.Scan(&w.VoterType, &w.Weight); err != nil {
+			return nil, err
+		}
+		weights = append(weights, w)
+	}
+	return weights, rows.Err()
+}
+
+func (r *PgAdminRepository) ReplaceVoterTypeWeights(ctx context.Context, id int64, weights []VoterTypeWeight) ([]VoterTypeWeight, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM myschema.election_voter_type_weights WHERE election_id = $1`, id); err != nil {
+		return nil, err
+	}
+	for _, w := range weights {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_voter_type_weights (election_id, voter_type, weight)
+VALUES ($1, $2, $3)
+`, id, w.VoterType, w.Weight); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetVoterTypeWeights(ctx, id)
+}
+
+const qSelectRaces = `
+SELECT
+    r.id,
+    r.election_id,
+    r.code,
+    r.name,
+    r.description,
+    r.display_order,
+    COALESCE(ARRAY_AGG(e.faculty_id ORDER BY e.faculty_id) FILTER (WHERE e.faculty_id IS NOT NULL), '{}'::bigint[]),
+    COALESCE(ARRAY_AGG(e.study_program_id ORDER BY e.study_program_id) FILTER (WHERE e.study_program_id IS NOT NULL), '{}'::bigint[]),
+    r.created_at,
+    r.updated_at
+FROM myschema.election_races r
+LEFT JOIN myschema.election_race_eligibility e ON e.race_id = r.id
+`
+
+func scanRace(row pgx.Row) (*ElectionRace, error) {
+	var race ElectionRace
+	if err := row.Scan(
+		&race.ID,
+		&race.ElectionID,
+		&race.Code,
+		&race.Name,
+		&race.Description,
+		&race.DisplayOrder,
+		&race.FacultyIDs,
+		&race.StudyProgramIDs,
+		&race.CreatedAt,
+		&race.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &race, nil
+}
+
+func (r *PgAdminRepository) ListRaces(ctx context.Context, electionID int64) ([]ElectionRace, error) {
+	rows, err := r.db.Query(ctx, qSelectRaces+`
+WHERE r.election_id = $1
+GROUP BY r.id
+ORDER BY r.display_order, r.id
+`, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	races := make([]ElectionRace, 0)
+	for rows.Next() {
+		race, err := scanRace(rows)
+		if err != nil {
+			return nil, err
+		}
+		races = append(races, *race)
+	}
+	return races, rows.Err()
+}
+
+// ListRankedCandidates returns the candidate IDs standing in each race
+// (race 0 for elections without races): published candidates plus any
+// candidate ranked on a ballot.
+func (r *PgAdminRepository) ListRankedCandidates(ctx context.Context, electionID int64) (map[int64][]int64, error) {
+	const q = `
+SELECT COALESCE(c.race_id, 0), c.id
+FROM myschema.candidates c
+WHERE c.election_id = $1
+  AND (c.status = 'APPROVED' OR EXISTS (
+        SELECT 1 FROM myschema.ranked_ballot_preferences p WHERE p.candidate_id = c.id
+  ))
+ORDER BY c.race_id, c.number
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make(map[int64][]int64)
+	for rows.Next() {
+		var raceID, candidateID int64
+		if err := rows.Scan(&raceID, &candidateID); err != nil {
+			return nil, err
+		}
+		candidates[raceID] = append(candidates[raceID], candidateID)
+	}
+	return candidates, rows.Err()
+}
+
+// ListRankedBallots returns every ranked ballot of the election with its
+// preferences in rank order.
+func (r *PgAdminRepository) ListRankedBallots(ctx context.Context, electionID int64) ([]RankedBallot, error) {
+	const q = `
+SELECT COALESCE(b.race_id, 0), array_agg(p.candidate_id ORDER BY p.rank)
+FROM myschema.ranked_ballots b
+JOIN myschema.ranked_ballot_preferences p ON p.ballot_id = b.id
+WHERE b.election_id = $1
+GROUP BY b.id
+ORDER BY b.id
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ballots := make([]RankedBallot, 0)
+	for rows.Next() {
+		var b RankedBallot
+		if err := rows.Scan(&b.RaceID, &b.Ranking); err != nil {
+			return nil, err
+		}
+		ballots = append(ballots, b)
+	}
+	return ballots, rows.Err()
+}
+
+func (r *PgAdminRepository) GetRace(ctx context.Context, electionID, raceID int64) (*ElectionRace, error) {
+	race, err := scanRace(r.db.QueryRow(ctx, qSelectRaces+`
+WHERE r.election_id = $1 AND r.id = $2
+GROUP BY r.id
+`, electionID, raceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRaceNotFound
+		}
+		return nil, err
+	}
+	return race, nil
+}
+
+func (r *PgAdminRepository) CreateRace(ctx context.Context, electionID int64, req ElectionRaceRequest) (*ElectionRace, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var raceID int64
+	err = tx.QueryRow(ctx, `
+INSERT INTO myschema.election_races (election_id, code, name, description, display_order)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id
+`, electionID, req.Code, req.Name, req.Description, req.DisplayOrder).Scan(&raceID)
+	if err != nil {
+		return nil, mapRaceError(err)
+	}
+
+	if err := insertRaceEligibility(ctx, tx, raceID, req); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetRace(ctx, electionID, raceID)
+}
+
+func (r *PgAdminRepository) UpdateRace(ctx context.Context, electionID, raceID int64, req ElectionRaceRequest) (*ElectionRace, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+UPDATE myschema.election_races
+SET code = $3, name = $4, description = $5, display_order = $6, updated_at = NOW()
+WHERE election_id = $1 AND id = $2
+`, electionID, raceID, req.Code, req.Name, req.Description, req.DisplayOrder)
+	if err != nil {
+		return nil, mapRaceError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrRaceNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM myschema.election_race_eligibility WHERE race_id = $1`, raceID); err != nil {
+		return nil, err
+	}
+	if err := insertRaceEligibility(ctx, tx, raceID, req); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.GetRace(ctx, electionID, raceID)
+}
+
+func (r *PgAdminRepository) DeleteRace(ctx context.Context, electionID, raceID int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM myschema.election_races WHERE election_id = $1 AND id = $2`, electionID, raceID)
+	if err != nil {
+		return mapRaceError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRaceNotFound
+	}
+	return nil
+}
+
+func insertRaceEligibility(ctx context.Context, tx pgx.Tx, raceID int64, req ElectionRaceRequest) error {
+	for _, facultyID := range req.FacultyIDs {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_race_eligibility (race_id, faculty_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`, raceID, facultyID); err != nil {
+			return mapRaceError(err)
+		}
+	}
+	for _, programID := range req.StudyProgramIDs {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_race_eligibility (race_id, study_program_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`, raceID, programID); err != nil {
+			return mapRaceError(err)
+		}
+	}
+	return nil
+}
+
+// mapRaceError translates constraint violations on races into domain errors.
+func mapRaceError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23505" && pgErr.ConstraintName == "ux_election_races_election_code":
+		return ErrRaceCodeTaken
+	case pgErr.Code == "23503" && (pgErr.ConstraintName == "fk_candidates_race" || pgErr.ConstraintName == "fk_votes_race" ||
+		pgErr.ConstraintName == "fk_ranked_ballots_race"):
+		return ErrRaceHasCandidates
+	case pgErr.Code == "23503":
+		return ErrInvalidRaceMaster
+	}
+	return err
+}
+
 func brandingColumn(slot BrandingSlot) (string, error) {
 	switch slot {
 	case BrandingSlotPrimary:
@@ -992,3 +1534,357 @@ RETURNING primary_logo_id, secondary_logo_id, updated_at, updated_by_admin_id,
 
 	return branding, nil
 }
+
+// HasOpenVotingExtension reports whether any TPS of the election still holds
+// a voting extension, i.e. votes may still come in after the election closed.
+func (r *PgAdminRepository) HasOpenVotingExtension(ctx context.Context, electionID int64) (bool, error) {
+	var open bool
+	err := r.db.QueryRow(ctx, `
+SELECT EXISTS (
+    SELECT 1 FROM myschema.voting_extensions
+    WHERE election_id = $1 AND tps_id IS NOT NULL AND voting_end_at > NOW()
+)
+`, electionID).Scan(&open)
+	return open, err
+}
+
+// ResultsSealed reports whether the election's per-candidate numbers are
+// sealed right now. Unknown elections are never sealed.
+func (r *PgAdminRepository) ResultsSealed(ctx context.Context, electionID int64) (bool, error) {
+	var (
+		sealed        bool
+		status        ElectionStatus
+		extensionOpen bool
+	)
+	err := r.db.QueryRow(ctx, `
+SELECT
+    e.results_sealed,
+    e.status,
+    EXISTS (
+        SELECT 1 FROM myschema.voting_extensions x
+        WHERE x.election_id = e.id AND x.tps_id IS NOT NULL AND x.voting_end_at > NOW()
+    )
+FROM myschema.elections e
+WHERE e.id = $1
+`, electionID).Scan(&sealed, &status, &extensionOpen)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return resultsSealed(sealed, status, extensionOpen), nil
+}
+
+// ListResultCandidates returns the candidates counted in the results:
+// approved ones and any other candidate that received votes.
+func (r *PgAdminRepository) ListResultCandidates(ctx context.Context, electionID int64) ([]ResultCandidateRow, error) {
+	const q = `
+SELECT c.id, c.race_id, c.number, c.name
+FROM myschema.candidates c
+WHERE c.election_id = $1
+  AND (c.status = 'APPROVED'
+       OR EXISTS (SELECT 1 FROM myschema.votes v WHERE v.election_id = $1 AND v.candidate_id = c.id)
+       OR EXISTS (SELECT 1 FROM myschema.ranked_ballot_preferences p WHERE p.candidate_id = c.id))
+ORDER BY c.race_id NULLS FIRST, c.number, c.id
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make([]ResultCandidateRow, 0)
+	for rows.Next() {
+		var c ResultCandidateRow
+		if err := rows.Scan(&c.CandidateID, &c.RaceID, &c.Number, &c.Name); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// ListResultVoteRows counts votes by candidate, race, channel, TPS and the
+// voter's faculty. Ranked ballots count for their first preference.
+func (r *PgAdminRepository) ListResultVoteRows(ctx context.Context, electionID int64) ([]ResultVoteRow, error) {
+	const q = `
+WITH ballots AS (
+    SELECT v.election_id, v.candidate_id, v.race_id, v.channel::text AS channel, v.tps_id, v.token_hash
+    FROM myschema.votes v
+    WHERE v.election_id = $1
+    UNION ALL
+    SELECT rb.election_id, p.candidate_id, rb.race_id, rb.channel::text, rb.tps_id, rb.token_hash
+    FROM myschema.ranked_ballots rb
+    JOIN myschema.ranked_ballot_preferences p ON p.ballot_id = rb.id AND p.rank = 1
+    WHERE rb.election_id = $1
+)
+SELECT
+    b.candidate_id,
+    b.race_id,
+    b.channel,
+    b.tps_id,
+    COALESCE(t.code, ''),
+    COALESCE(t.name, ''),
+    COALESCE(vr.faculty_code, ''),
+    COALESCE(vr.faculty_name, ''),
+    COUNT(*)
+FROM ballots b
+LEFT JOIN myschema.tps t ON t.id = b.tps_id
+LEFT JOIN myschema.voter_status vs ON vs.election_id = b.election_id AND vs.vote_token_hash = b.token_hash
+LEFT JOIN myschema.voters vr ON vr.id = vs.voter_id
+GROUP BY 1, 2, 3, 4, 5, 6, 7, 8
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]ResultVoteRow, 0)
+	for rows.Next() {
+		var row ResultVoteRow
+		if err := rows.Scan(
+			&row.CandidateID,
+			&row.RaceID,
+			&row.Channel,
+			&row.TPSID,
+			&row.TPSCode,
+			&row.TPSName,
+			&row.FacultyCode,
+			&row.FacultyName,
+			&row.Votes,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+const electionResultColumns = `
+    id, election_id, status, tally, tally_hash, signoffs_required,
+    computed_by, computed_at, certified_at, published_at
+`
+
+func scanElectionResult(row rowScanner) (*ElectionResult, error) {
+	var res ElectionResult
+	var tally []byte
+	if err := row.Scan(
+		&res.ID,
+		&res.ElectionID,
+		&res.Status,
+		&tally,
+		&res.TallyHash,
+		&res.SignoffsRequired,
+		&res.ComputedBy,
+		&res.ComputedAt,
+		&res.CertifiedAt,
+		&res.PublishedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResultNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(tally, &res.Tally); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateResult freezes a new results snapshot, superseding a pending one.
+// A certified or published snapshot is final.
+func (r *PgAdminRepository) CreateResult(ctx context.Context, electionID int64, tally []byte, tallyHash string, signoffsRequired int, computedBy *int64) (*ElectionResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `
+SELECT status FROM myschema.election_results
+WHERE election_id = $1 AND status <> 'SUPERSEDED'
+FOR UPDATE
+`, electionID).Scan(&current)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, err
+	case current != ResultStatusPendingSignoff:
+		return nil, ErrResultAlreadyCertified
+	default:
+		if _, err := tx.Exec(ctx, `
+UPDATE myschema.election_results SET status = 'SUPERSEDED'
+WHERE election_id = $1 AND status = 'PENDING_SIGNOFF'
+`, electionID); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := scanElectionResult(tx.QueryRow(ctx, fmt.Sprintf(`
+INSERT INTO myschema.election_results (election_id, tally, tally_hash, signoffs_required, computed_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING %s
+`, electionResultColumns), electionID, tally, tallyHash, signoffsRequired, computedBy))
+	if err != nil {
+		return nil, err
+	}
+	res.Signoffs = make([]ResultSignoff, 0)
+
+	if err := insertAuditLog(ctx, tx, electionID, computedBy, "RESULT_COMPUTED", "ELECTION_RESULT", res.ID, map[string]any{
+		"tally_hash":        tallyHash,
+		"signoffs_required": signoffsRequired,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetCurrentResult returns the live (not superseded) snapshot with its sign-offs.
+func (r *PgAdminRepository) GetCurrentResult(ctx context.Context, electionID int64) (*ElectionResult, error) {
+	res, err := scanElectionResult(r.db.QueryRow(ctx, fmt.Sprintf(`
+SELECT %s
+FROM myschema.election_results
+WHERE election_id = $1 AND status <> 'SUPERSEDED'
+`, electionResultColumns), electionID))
+	if err != nil {
+		return nil, err
+	}
+	res.Signoffs, err = listResultSignoffs(ctx, r.db, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func listResultSignoffs(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, resultID int64) ([]ResultSignoff, error) {
+	rows, err := q.Query(ctx, `
+SELECT s.user_id, COALESCE(ua.username, ''), s.tally_hash, s.note, s.signed_at
+FROM myschema.election_result_signoffs s
+LEFT JOIN user_accounts ua ON ua.id = s.user_id
+WHERE s.result_id = $1
+ORDER BY s.signed_at, s.id
+`, resultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	signoffs := make([]ResultSignoff, 0)
+	for rows.Next() {
+		var so ResultSignoff
+		if err := rows.Scan(&so.UserID, &so.Username, &so.TallyHash, &so.Note, &so.SignedAt); err != nil {
+			return nil, err
+		}
+		signoffs = append(signoffs, so)
+	}
+	return signoffs, rows.Err()
+}
+
+// AddResultSignoff records a committee sign-off on the snapshot's hash and
+// certifies the snapshot once the required number of accounts signed.
+func (r *PgAdminRepository) AddResultSignoff(ctx context.Context, resultID, userID int64, tallyHash string, note *string) (*ElectionResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := scanElectionResult(tx.QueryRow(ctx, fmt.Sprintf(`
+SELECT %s FROM myschema.election_results WHERE id = $1 FOR UPDATE
+`, electionResultColumns), resultID))
+	if err != nil {
+		return nil, err
+	}
+	if res.Status != ResultStatusPendingSignoff {
+		return nil, ErrResultAlreadyCertified
+	}
+	if !strings.EqualFold(res.TallyHash, tallyHash) {
+		return nil, ErrResultHashMismatch
+	}
+
+	if _, err := tx.Exec(ctx, `
+INSERT INTO myschema.election_result_signoffs (result_id, user_id, tally_hash, note)
+VALUES ($1, $2, $3, $4)
+`, resultID, userID, res.TallyHash, note); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "ux_election_result_signoffs_user" {
+			return nil, ErrAlreadySignedOff
+		}
+		return nil, err
+	}
+
+	res.Signoffs, err = listResultSignoffs(ctx, tx, resultID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "RESULT_SIGNED_OFF"
+	if len(res.Signoffs) >= res.SignoffsRequired {
+		if err := tx.QueryRow(ctx, `
+UPDATE myschema.election_results SET status = 'CERTIFIED', certified_at = NOW()
+WHERE id = $1
+RETURNING status, certified_at
+`, resultID).Scan(&res.Status, &res.CertifiedAt); err != nil {
+			return nil, err
+		}
+		action = "RESULT_CERTIFIED"
+	}
+
+	if err := insertAuditLog(ctx, tx, res.ElectionID, &userID, action, "ELECTION_RESULT", resultID, map[string]any{
+		"tally_hash": res.TallyHash,
+		"signoffs":   len(res.Signoffs),
+		"required":   res.SignoffsRequired,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PublishResult publishes a certified snapshot.
+func (r *PgAdminRepository) PublishResult(ctx context.Context, resultID int64, userID *int64) (*ElectionResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := scanElectionResult(tx.QueryRow(ctx, fmt.Sprintf(`
+UPDATE myschema.election_results SET status = 'PUBLISHED', published_at = NOW()
+WHERE id = $1 AND status = 'CERTIFIED'
+RETURNING %s
+`, electionResultColumns), resultID))
+	if err != nil {
+		if errors.Is(err, ErrResultNotFound) {
+			return nil, ErrResultNotCertified
+		}
+		return nil, err
+	}
+	res.Signoffs, err = listResultSignoffs(ctx, tx, resultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertAuditLog(ctx, tx, res.ElectionID, userID, "RESULT_PUBLISHED", "ELECTION_RESULT", resultID, map[string]any{
+		"tally_hash": res.TallyHash,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
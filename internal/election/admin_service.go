@@ -5,15 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
 type AdminService struct {
-	repo AdminRepository
+	repo                   AdminRepository
+	notifier               Notifier
+	resultSignoffsRequired int
 }
 
 func NewAdminService(repo AdminRepository) *AdminService {
-	return &AdminService{repo: repo}
+	return &AdminService{repo: repo, resultSignoffsRequired: DefaultResultSignoffsRequired}
+}
+
+// SetResultSignoffsRequired sets how many committee accounts must sign off a
+// results snapshot before it is certified.
+func (s *AdminService) SetResultSignoffsRequired(n int) {
+	if n > 0 {
+		s.resultSignoffsRequired = n
+	}
+}
+
+// SetNotifier enables real-time broadcasts of admin actions.
+func (s *AdminService) SetNotifier(n Notifier) {
+	s.notifier = n
 }
 
 var (
@@ -87,7 +103,17 @@ func (s *AdminService) Get(ctx context.Context, id int64) (*AdminElectionDTO, er
 	return dto, nil
 }
 
+// Update edits election data. Status only changes through the transition
+// actions, so Update rejects edits that would contradict the current status.
 func (s *AdminService) Update(ctx context.Context, id int64, req AdminElectionUpdateRequest) (*AdminElectionDTO, error) {
+	current, err := s.repo.GetElectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateElectionUpdate(current, req); err != nil {
+		return nil, err
+	}
+
 	dto, err := s.repo.UpdateElection(ctx, id, req)
 	if err != nil {
 		return nil, err
@@ -175,6 +201,9 @@ func (s *AdminService) UpdateModeSettings(ctx context.Context, id int64, req Mod
 	if !currentOnline && !currentTPS {
 		return nil, ErrInvalidModeCombination
 	}
+	if req.VotingSystem != nil && !ValidVotingSystem(*req.VotingSystem) {
+		return nil, ErrInvalidVotingSystem
+	}
 
 	switch e.Status {
 	case ElectionStatusVotingOpen, ElectionStatusVotingClosed, ElectionStatusClosed, ElectionStatusArchived:
@@ -184,6 +213,148 @@ func (s *AdminService) UpdateModeSettings(ctx context.Context, id int64, req Mod
 	return s.repo.UpdateModeSettings(ctx, id, req)
 }
 
+func (s *AdminService) GetVoterTypeWeights(ctx context.Context, id int64) (*VoterTypeWeightsDTO, error) {
+	if _, err := s.repo.GetElectionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	weights, err := s.repo.GetVoterTypeWeights(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &VoterTypeWeightsDTO{ElectionID: id, Weighted: len(weights) > 0, Weights: weights}, nil
+}
+
+// UpdateVoterTypeWeights replaces the weighting rules. Like mode settings,
+// weights are frozen once voting has started.
+func (s *AdminService) UpdateVoterTypeWeights(ctx context.Context, id int64, req VoterTypeWeightsRequest) (*VoterTypeWeightsDTO, error) {
+	e, err := s.repo.GetElectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch e.Status {
+	case ElectionStatusVotingOpen, ElectionStatusVotingClosed, ElectionStatusClosed, ElectionStatusArchived:
+		return nil, ErrElectionAlreadyStarted
+	}
+
+	weights, err := s.repo.ReplaceVoterTypeWeights(ctx, id, req.Weights)
+	if err != nil {
+		return nil, err
+	}
+	return &VoterTypeWeightsDTO{ElectionID: id, Weighted: len(weights) > 0, Weights: weights}, nil
+}
+
+// GetIRVReport tabulates the ranked ballots of an IRV election and returns
+// the round-by-round elimination report of every race.
+func (s *AdminService) GetIRVReport(ctx context.Context, electionID int64) (*IRVReport, error) {
+	if _, err := s.repo.GetElectionByID(ctx, electionID); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.GetModeSettings(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.VotingSystem != VotingSystemIRV {
+		return nil, ErrNotRankedElection
+	}
+	sealed, err := SealedFor(ctx, s.repo, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		return nil, ErrResultsSealed
+	}
+
+	races, err := s.repo.ListRaces(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.ListRankedCandidates(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	ballots, err := s.repo.ListRankedBallots(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	rankings := make(map[int64][][]int64)
+	for _, b := range ballots {
+		rankings[b.RaceID] = append(rankings[b.RaceID], b.Ranking)
+	}
+
+	report := &IRVReport{ElectionID: electionID, Races: make([]IRVResult, 0, len(races)+1)}
+	if len(races) == 0 {
+		report.Races = append(report.Races, *TabulateIRV(candidates[0], rankings[0]))
+		return report, nil
+	}
+	for _, race := range races {
+		result := TabulateIRV(candidates[race.ID], rankings[race.ID])
+		raceID := race.ID
+		result.RaceID = &raceID
+		result.Code = race.Code
+		result.Name = race.Name
+		report.Races = append(report.Races, *result)
+	}
+	return report, nil
+}
+
+func (s *AdminService) ListRaces(ctx context.Context, electionID int64) ([]ElectionRace, error) {
+	if _, err := s.repo.GetElectionByID(ctx, electionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRaces(ctx, electionID)
+}
+
+func (s *AdminService) GetRace(ctx context.Context, electionID, raceID int64) (*ElectionRace, error) {
+	return s.repo.GetRace(ctx, electionID, raceID)
+}
+
+func (s *AdminService) CreateRace(ctx context.Context, electionID int64, req ElectionRaceRequest) (*ElectionRace, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRacesEditable(ctx, electionID); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateRace(ctx, electionID, req)
+}
+
+func (s *AdminService) UpdateRace(ctx context.Context, electionID, raceID int64, req ElectionRaceRequest) (*ElectionRace, error) {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureRacesEditable(ctx, electionID); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateRace(ctx, electionID, raceID, req)
+}
+
+func (s *AdminService) DeleteRace(ctx context.Context, electionID, raceID int64) error {
+	if err := s.ensureRacesEditable(ctx, electionID); err != nil {
+		return err
+	}
+	return s.repo.DeleteRace(ctx, electionID, raceID)
+}
+
+// ensureRacesEditable rejects race changes once ballots may have been cast.
+func (s *AdminService) ensureRacesEditable(ctx context.Context, electionID int64) error {
+	e, err := s.repo.GetElectionByID(ctx, electionID)
+	if err != nil {
+		return err
+	}
+	switch e.Status {
+	case ElectionStatusVotingOpen, ElectionStatusVotingClosed, ElectionStatusClosed, ElectionStatusArchived:
+		return ErrRacesLocked
+	}
+	return nil
+}
+
 func (s *AdminService) GetSummary(ctx context.Context, id int64) (*ElectionSummaryDTO, error) {
 	election, err := s.repo.GetElectionByID(ctx, id)
 	if err != nil {
@@ -199,7 +370,47 @@ func (s *AdminService) GetSummary(ctx context.Context, id int64) (*ElectionSumma
 	return summary, nil
 }
 
-func (s *AdminService) OpenVoting(ctx context.Context, id int64) (*AdminElectionDTO, error) {
+// StatusActor identifies who requested a status change and why.
+type StatusActor struct {
+	Type   string
+	UserID *int64
+	Reason string
+}
+
+// AdminActor is a status change requested by an admin user.
+func AdminActor(userID int64, reason string) StatusActor {
+	return StatusActor{Type: StatusActorAdmin, UserID: &userID, Reason: reason}
+}
+
+// transition checks t against the transition table and the preconditions of
+// the target status, then applies it and records it in the status history.
+func (s *AdminService) transition(ctx context.Context, e *AdminElectionDTO, t StatusTransition, actor StatusActor) (*AdminElectionDTO, error) {
+	pre, err := s.repo.GetTransitionPrerequisites(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckTransition(e.Status, t.To, *pre); err != nil {
+		return nil, err
+	}
+
+	t.ElectionID = e.ID
+	t.From = e.Status
+	t.ActorType = actor.Type
+	t.ActorUserID = actor.UserID
+	if actor.Reason != "" {
+		reason := actor.Reason
+		t.Reason = &reason
+	}
+
+	dto, err := s.repo.TransitionStatus(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	s.enrichElection(dto)
+	return dto, nil
+}
+
+func (s *AdminService) OpenVoting(ctx context.Context, id int64, actor StatusActor) (*AdminElectionDTO, error) {
 	e, err := s.repo.GetElectionByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -210,7 +421,7 @@ func (s *AdminService) OpenVoting(ctx context.Context, id int64) (*AdminElection
 	if e.Status == ElectionStatusVotingOpen {
 		return nil, ErrElectionAlreadyOpened
 	}
-	if e.Status == ElectionStatusArchived {
+	if !CanTransition(e.Status, ElectionStatusVotingOpen) {
 		return nil, ErrInvalidStatusChange
 	}
 
@@ -227,16 +438,14 @@ func (s *AdminService) OpenVoting(ctx context.Context, id int64) (*AdminElection
 	}
 
 	currentPhase := string(PhaseKeyVoting)
-
-	dto, err := s.repo.SetVotingStatus(ctx, id, ElectionStatusVotingOpen, &currentPhase, startAt, e.VotingEndAt)
-	if err != nil {
-		return nil, err
-	}
-	s.enrichElection(dto)
-	return dto, nil
+	return s.transition(ctx, e, StatusTransition{
+		To:            ElectionStatusVotingOpen,
+		CurrentPhase:  &currentPhase,
+		VotingStartAt: startAt,
+	}, actor)
 }
 
-func (s *AdminService) CloseVoting(ctx context.Context, id int64) (*AdminElectionDTO, error) {
+func (s *AdminService) CloseVoting(ctx context.Context, id int64, actor StatusActor) (*AdminElectionDTO, error) {
 	e, err := s.repo.GetElectionByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -252,35 +461,207 @@ func (s *AdminService) CloseVoting(ctx context.Context, id int64) (*AdminElectio
 
 	now := time.Now().UTC()
 	currentPhase := string(PhaseKeyRecap)
-	dto, err := s.repo.SetVotingStatus(ctx, id, ElectionStatusVotingClosed, &currentPhase, nil, &now)
+	return s.transition(ctx, e, StatusTransition{
+		To:           ElectionStatusVotingClosed,
+		CurrentPhase: &currentPhase,
+		VotingEndAt:  &now,
+	}, actor)
+}
+
+func (s *AdminService) Archive(ctx context.Context, id int64, actor StatusActor) (*AdminElectionDTO, error) {
+	e, err := s.repo.GetElectionByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	s.enrichElection(dto)
-	return dto, nil
+
+	if e.Status == ElectionStatusArchived {
+		return nil, ErrElectionArchived
+	}
+
+	if !CanTransition(e.Status, ElectionStatusArchived) {
+		return nil, ErrElectionNotClosable
+	}
+
+	currentPhase := string(PhaseKeyRecap)
+	return s.transition(ctx, e, StatusTransition{
+		To:           ElectionStatusArchived,
+		CurrentPhase: &currentPhase,
+	}, actor)
 }
 
-func (s *AdminService) Archive(ctx context.Context, id int64) (*AdminElectionDTO, error) {
+// AdvanceBySchedule applies a scheduled step on behalf of the scheduler.
+func (s *AdminService) AdvanceBySchedule(ctx context.Context, e *AdminElectionDTO, step ScheduleStep) (*AdminElectionDTO, error) {
+	phase := string(step.Phase)
+	return s.transition(ctx, e, StatusTransition{To: step.Status, CurrentPhase: &phase}, StatusActor{
+		Type:   StatusActorSystem,
+		Reason: "scheduled " + string(step.Phase) + " at " + step.At.UTC().Format(time.RFC3339),
+	})
+}
+
+// ExtendVoting extends the voting window until req.VotingEndAt, for the whole
+// election or for req.TPSIDs only. An election-wide extension of closed voting
+// reopens it. Every extension needs a reason, is audited and broadcast, and is
+// refused once results are published.
+func (s *AdminService) ExtendVoting(ctx context.Context, id int64, req VotingExtensionRequest, actor StatusActor) (*VotingExtensionResult, error) {
 	e, err := s.repo.GetElectionByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateVotingExtension(e, &req, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if current, err := s.repo.GetCurrentResult(ctx, id); err == nil {
+		if current.Status != ResultStatusPendingSignoff {
+			return nil, ErrResultsPublished
+		}
+	} else if !errors.Is(err, ErrResultNotFound) {
+		return nil, err
+	}
+
+	result, err := s.repo.ExtendVoting(ctx, VotingExtensionWrite{
+		ElectionID:    id,
+		From:          e.Status,
+		Reopen:        len(req.TPSIDs) == 0 && e.Status != ElectionStatusVotingOpen,
+		PreviousEndAt: e.VotingEndAt,
+		VotingEndAt:   req.VotingEndAt.UTC(),
+		TPSIDs:        req.TPSIDs,
+		Reason:        req.Reason,
+		ActorUserID:   actor.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.enrichElection(result.Election)
 
+	if s.notifier != nil {
+		event := map[string]any{
+			"election_id":   id,
+			"voting_end_at": result.Extensions[0].VotingEndAt,
+			"tps_ids":       req.TPSIDs,
+			"reopened":      result.Reopened,
+			"reason":        req.Reason,
+		}
+		s.notifier.Publish(ElectionChannel(id), EventVotingExtended, event)
+		for _, tpsID := range req.TPSIDs {
+			s.notifier.Publish(TPSChannel(tpsID), EventVotingExtended, event)
+		}
+	}
+	return result, nil
+}
+
+func (s *AdminService) ListVotingExtensions(ctx context.Context, id int64) ([]VotingExtension, error) {
+	if _, err := s.repo.GetElectionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVotingExtensions(ctx, id)
+}
+
+// ComputeResults counts the votes of a closed election and freezes them into
+// a results snapshot awaiting committee sign-off. Recounting replaces a
+// snapshot only while it has not been certified.
+func (s *AdminService) ComputeResults(ctx context.Context, id int64, actor StatusActor) (*ElectionResult, error) {
+	e, err := s.repo.GetElectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 	if e.Status == ElectionStatusArchived {
 		return nil, ErrElectionArchived
 	}
+	if !votingClosed(e.Status) {
+		return nil, ErrVotingNotClosed
+	}
+	open, err := s.repo.HasOpenVotingExtension(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrVotingNotClosed
+	}
 
-	if e.Status != ElectionStatusVotingClosed && e.Status != ElectionStatusRecap && e.Status != ElectionStatusClosed {
-		return nil, ErrElectionNotClosable
+	settings, err := s.repo.GetModeSettings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.ListResultCandidates(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListResultVoteRows(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	currentPhase := string(PhaseKeyRecap)
-	dto, err := s.repo.SetVotingStatus(ctx, id, ElectionStatusArchived, &currentPhase, nil, nil)
+	tally := BuildResultTally(id, settings.VotingSystem, candidates, rows)
+	if settings.VotingSystem == VotingSystemIRV {
+		if tally.IRV, err = s.GetIRVReport(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	data, hash, err := EncodeResultTally(tally)
 	if err != nil {
 		return nil, err
 	}
-	s.enrichElection(dto)
-	return dto, nil
+	return s.repo.CreateResult(ctx, id, data, hash, s.resultSignoffsRequired, actor.UserID)
+}
+
+// GetResults returns the current results snapshot after checking its hash.
+func (s *AdminService) GetResults(ctx context.Context, id int64) (*ElectionResult, error) {
+	if _, err := s.repo.GetElectionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.currentResult(ctx, id)
+}
+
+// SignOffResults records the actor's sign-off on the current snapshot. The
+// signer confirms the tally hash they reviewed, so a recount in between is
+// never signed by accident.
+func (s *AdminService) SignOffResults(ctx context.Context, id int64, actor StatusActor, req ResultSignoffRequest) (*ElectionResult, error) {
+	if actor.UserID == nil {
+		return nil, ErrSignerRequired
+	}
+	result, err := s.currentResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status != ResultStatusPendingSignoff {
+		return nil, ErrResultAlreadyCertified
+	}
+	return s.repo.AddResultSignoff(ctx, result.ID, *actor.UserID, strings.TrimSpace(req.TallyHash), req.Note)
+}
+
+// PublishResults publishes the certified snapshot. It becomes public once
+// the election's announcement time has come.
+func (s *AdminService) PublishResults(ctx context.Context, id int64, actor StatusActor) (*ElectionResult, error) {
+	result, err := s.currentResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch result.Status {
+	case ResultStatusPublished:
+		return nil, ErrResultAlreadyPublished
+	case ResultStatusPendingSignoff:
+		return nil, ErrResultNotCertified
+	}
+	return s.repo.PublishResult(ctx, result.ID, actor.UserID)
+}
+
+func (s *AdminService) currentResult(ctx context.Context, id int64) (*ElectionResult, error) {
+	result, err := s.repo.GetCurrentResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyResultTally(&result.Tally, result.TallyHash); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *AdminService) GetStatusHistory(ctx context.Context, id int64) ([]StatusHistoryEntry, error) {
+	if _, err := s.repo.GetElectionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusHistory(ctx, id)
 }
 
 func (s *AdminService) GetBranding(ctx context.Context, electionID int64) (*BrandingSettings, error) {
@@ -22,6 +22,7 @@ type Election struct {
 	ID            int64          `json:"id"`
 	Year          int            `json:"year"`
 	Name          string         `json:"name"`
+	Code          string         `json:"code"`
 	Slug          string         `json:"slug"`
 	Status        ElectionStatus `json:"status"`
 	VotingStartAt *time.Time     `json:"voting_start_at,omitempty"`
@@ -18,6 +18,7 @@ type ElectionService interface {
 	GetPublicPhases(ctx context.Context, electionID int64) (*ElectionPhasesResponse, error)
 	GetMeStatus(ctx context.Context, authUser auth.AuthUser, electionID int64) (*MeStatusDTO, error)
 	GetMeHistory(ctx context.Context, authUser auth.AuthUser, electionID int64) (*MeHistoryDTO, error)
+	GetPublishedResults(ctx context.Context, electionID int64) (*PublicElectionResult, error)
 }
 
 type Handler struct {
@@ -34,6 +35,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/elections", h.ListPublic)
 	r.Get("/elections/{electionID}/phases", h.GetPublicPhases)
 	r.Get("/elections/{electionID}/timeline", h.GetPublicPhases) // alias
+	r.Get("/elections/{electionID}/results", h.GetPublicResults)
 	r.Get("/elections/{electionID}/me/status", h.GetMeStatus)
 	r.Get("/elections/{electionID}/me/history", h.GetMeHistory)
 }
@@ -175,3 +177,30 @@ func (h *Handler) GetPublicPhases(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, http.StatusOK, phases)
 }
+
+// GetPublicResults handles GET /elections/{id}/results. Results stay hidden
+// until they are published and the announcement time has come.
+func (h *Handler) GetPublicResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	electionID, err := parseInt64Param(r, "electionID")
+	if err != nil || electionID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	result, err := h.svc.GetPublishedResults(ctx, electionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrElectionNotFound):
+			response.NotFound(w, "ELECTION_NOT_FOUND", "Pemilu tidak ditemukan.")
+		case errors.Is(err, ErrResultsNotAvailable):
+			response.NotFound(w, "RESULTS_NOT_AVAILABLE", "Hasil pemilu belum diumumkan.")
+		default:
+			response.InternalServerError(w, "INTERNAL_ERROR", "Terjadi kesalahan pada sistem.")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
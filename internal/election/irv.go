@@ -0,0 +1,180 @@
+package election
+
+import (
+	"errors"
+	"sort"
+)
+
+// Voting systems of an election. Plurality is the default; with IRV a ballot
+// ranks the candidates of a race and is counted by instant runoff.
+const (
+	VotingSystemPlurality = "PLURALITY"
+	VotingSystemIRV       = "IRV"
+)
+
+var (
+	ErrInvalidVotingSystem = errors.New("invalid voting system")
+	ErrNotRankedElection   = errors.New("election does not use ranked-choice voting")
+)
+
+// ValidVotingSystem reports whether s is a supported voting system.
+func ValidVotingSystem(s string) bool {
+	return s == VotingSystemPlurality || s == VotingSystemIRV
+}
+
+// RankedBallot is one stored ranking: candidate IDs in order of preference.
+// RaceID is 0 for elections without races.
+type RankedBallot struct {
+	RaceID  int64
+	Ranking []int64
+}
+
+// IRVRound is one counting round. Tallies hold the votes of every candidate
+// still in the count; a ballot whose ranked candidates are all eliminated is
+// exhausted and no longer counts towards the majority.
+type IRVRound struct {
+	Round      int             `json:"round"`
+	Tallies    map[int64]int64 `json:"tallies"`
+	Continuing int64           `json:"continuing_ballots"`
+	Exhausted  int64           `json:"exhausted_ballots"`
+	// Threshold is the number of votes needed for a majority in this round.
+	Threshold  int64  `json:"threshold"`
+	Eliminated *int64 `json:"eliminated_candidate_id,omitempty"`
+	// TieBreak is set when the eliminated candidate was chosen among several
+	// with the lowest tally.
+	TieBreak bool   `json:"tie_break"`
+	Winner   *int64 `json:"winner_candidate_id,omitempty"`
+}
+
+// IRVResult is the round-by-round report of one race.
+type IRVResult struct {
+	RaceID            *int64     `json:"race_id,omitempty"`
+	Code              string     `json:"code,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	CandidateIDs      []int64    `json:"candidate_ids"`
+	TotalBallots      int64      `json:"total_ballots"`
+	Rounds            []IRVRound `json:"rounds"`
+	WinnerCandidateID *int64     `json:"winner_candidate_id,omitempty"`
+}
+
+// IRVReport is the instant-runoff result of an election, one entry per race.
+type IRVReport struct {
+	ElectionID int64       `json:"election_id"`
+	Races      []IRVResult `json:"races"`
+}
+
+// TabulateIRV counts ranked ballots by instant runoff.
+//
+// Each round a ballot counts for its highest-ranked candidate still in the
+// count. A candidate with more than half of the continuing ballots wins;
+// otherwise the candidate with the fewest votes is eliminated and the next
+// round starts. Ties for last place are broken by the tallies of earlier
+// rounds (fewest votes in the latest round where they differ), and failing
+// that the candidate with the highest ID (the latest registered) is eliminated.
+// Preferences for candidates not in candidateIDs are ignored.
+func TabulateIRV(candidateIDs []int64, ballots [][]int64) *IRVResult {
+	result := &IRVResult{
+		CandidateIDs: append([]int64(nil), candidateIDs...),
+		TotalBallots: int64(len(ballots)),
+		Rounds:       make([]IRVRound, 0),
+	}
+	sort.Slice(result.CandidateIDs, func(i, j int) bool { return result.CandidateIDs[i] < result.CandidateIDs[j] })
+
+	continuing := make(map[int64]bool, len(candidateIDs))
+	for _, id := range candidateIDs {
+		continuing[id] = true
+	}
+	if len(continuing) == 0 {
+		return result
+	}
+
+	for round := 1; ; round++ {
+		r := IRVRound{Round: round, Tallies: make(map[int64]int64, len(continuing))}
+		for id := range continuing {
+			r.Tallies[id] = 0
+		}
+		for _, ranking := range ballots {
+			counted := false
+			for _, id := range ranking {
+				if continuing[id] {
+					r.Tallies[id]++
+					counted = true
+					break
+				}
+			}
+			if counted {
+				r.Continuing++
+			} else {
+				r.Exhausted++
+			}
+		}
+		r.Threshold = r.Continuing/2 + 1
+
+		if winner, ok := majorityWinner(r.Tallies, r.Threshold, len(continuing)); ok {
+			r.Winner = &winner
+			result.WinnerCandidateID = &winner
+			result.Rounds = append(result.Rounds, r)
+			return result
+		}
+		if r.Continuing == 0 {
+			// No ballots left to count: no winner.
+			result.Rounds = append(result.Rounds, r)
+			return result
+		}
+
+		eliminated, tieBreak := lowestCandidate(r.Tallies, result.Rounds)
+		r.Eliminated = &eliminated
+		r.TieBreak = tieBreak
+		delete(continuing, eliminated)
+		result.Rounds = append(result.Rounds, r)
+	}
+}
+
+// majorityWinner returns the candidate reaching the threshold, or the last
+// candidate standing when it has any votes.
+func majorityWinner(tallies map[int64]int64, threshold int64, remaining int) (int64, bool) {
+	for id, votes := range tallies {
+		if votes > 0 && (votes >= threshold || remaining == 1) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// lowestCandidate picks the candidate to eliminate from the current tallies,
+// breaking ties with earlier rounds and finally by highest candidate ID.
+func lowestCandidate(tallies map[int64]int64, previous []IRVRound) (int64, bool) {
+	var min int64 = -1
+	var tied []int64
+	for id, votes := range tallies {
+		switch {
+		case min < 0 || votes < min:
+			min = votes
+			tied = []int64{id}
+		case votes == min:
+			tied = append(tied, id)
+		}
+	}
+	if len(tied) == 1 {
+		return tied[0], false
+	}
+
+	for i := len(previous) - 1; i >= 0 && len(tied) > 1; i-- {
+		var lowest int64 = -1
+		var next []int64
+		for _, id := range tied {
+			votes := previous[i].Tallies[id]
+			switch {
+			case lowest < 0 || votes < lowest:
+				lowest = votes
+				next = []int64{id}
+			case votes == lowest:
+				next = append(next, id)
+			}
+		}
+		tied = next
+	}
+
+	sort.Slice(tied, func(i, j int) bool { return tied[i] > tied[j] })
+	return tied[0], true
+}
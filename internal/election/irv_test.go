@@ -0,0 +1,77 @@
+package election
+
+import "testing"
+
+func repeatBallot(n int, ranking ...int64) [][]int64 {
+	ballots := make([][]int64, n)
+	for i := range ballots {
+		ballots[i] = ranking
+	}
+	return ballots
+}
+
+func TestTabulateIRV_FirstRoundMajority(t *testing.T) {
+	ballots := append(repeatBallot(6, 1, 2), repeatBallot(4, 2, 1)...)
+
+	result := TabulateIRV([]int64{1, 2}, ballots)
+
+	if result.WinnerCandidateID == nil || *result.WinnerCandidateID != 1 {
+		t.Fatalf("expected candidate 1 to win, got %+v", result.WinnerCandidateID)
+	}
+	if len(result.Rounds) != 1 || result.Rounds[0].Threshold != 6 {
+		t.Fatalf("expected a single round with threshold 6, got %+v", result.Rounds)
+	}
+}
+
+func TestTabulateIRV_TransfersAfterElimination(t *testing.T) {
+	var ballots [][]int64
+	ballots = append(ballots, repeatBallot(8, 1)...)
+	ballots = append(ballots, repeatBallot(7, 2, 3)...)
+	ballots = append(ballots, repeatBallot(5, 3, 2)...)
+	ballots = append(ballots, repeatBallot(1, 3)...)
+
+	result := TabulateIRV([]int64{1, 2, 3}, ballots)
+
+	if len(result.Rounds) != 2 {
+		t.Fatalf("expected 2 rounds, got %d", len(result.Rounds))
+	}
+	first := result.Rounds[0]
+	if first.Eliminated == nil || *first.Eliminated != 3 || first.TieBreak {
+		t.Fatalf("expected candidate 3 eliminated in round 1, got %+v", first)
+	}
+	second := result.Rounds[1]
+	// 5 ballots transfer to candidate 2, the ballot ranking only 3 is exhausted.
+	if second.Tallies[2] != 12 || second.Tallies[1] != 8 || second.Exhausted != 1 || second.Continuing != 20 {
+		t.Fatalf("unexpected round 2 tallies: %+v", second)
+	}
+	if result.WinnerCandidateID == nil || *result.WinnerCandidateID != 2 {
+		t.Fatalf("expected candidate 2 to win, got %+v", result.WinnerCandidateID)
+	}
+}
+
+func TestTabulateIRV_TieBreakUsesEarlierRounds(t *testing.T) {
+	var ballots [][]int64
+	ballots = append(ballots, repeatBallot(5, 1)...)
+	ballots = append(ballots, repeatBallot(4, 2)...)
+	ballots = append(ballots, repeatBallot(3, 3)...)
+	ballots = append(ballots, repeatBallot(1, 4, 3)...)
+
+	result := TabulateIRV([]int64{1, 2, 3, 4}, ballots)
+
+	// Round 2: candidates 2 and 3 tie at 4 votes; 3 had fewer in round 1.
+	second := result.Rounds[1]
+	if second.Eliminated == nil || *second.Eliminated != 3 || !second.TieBreak {
+		t.Fatalf("expected candidate 3 eliminated by tie-break, got %+v", second)
+	}
+}
+
+func TestTabulateIRV_NoBallots(t *testing.T) {
+	result := TabulateIRV([]int64{1, 2}, nil)
+
+	if result.WinnerCandidateID != nil {
+		t.Fatalf("expected no winner without ballots")
+	}
+	if len(result.Rounds) != 1 || result.Rounds[0].Continuing != 0 {
+		t.Fatalf("expected a single empty round, got %+v", result.Rounds)
+	}
+}
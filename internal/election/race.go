@@ -0,0 +1,68 @@
+package election
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRaceNotFound      = errors.New("race not found")
+	ErrRaceCodeTaken     = errors.New("race code already used")
+	ErrInvalidRace       = errors.New("invalid race")
+	ErrInvalidRaceMaster = errors.New("race eligibility references unknown faculty or study program")
+	ErrRaceHasCandidates = errors.New("race still has candidates")
+	ErrRacesLocked       = errors.New("races locked after voting started")
+)
+
+// ElectionRace is one position contested in an election (e.g. Presiden BEM,
+// DPM Fakultas Teknik). Empty FacultyIDs and StudyProgramIDs mean every voter
+// in the election may vote in the race.
+type ElectionRace struct {
+	ID              int64     `json:"id"`
+	ElectionID      int64     `json:"election_id"`
+	Code            string    `json:"code"`
+	Name            string    `json:"name"`
+	Description     *string   `json:"description,omitempty"`
+	DisplayOrder    int       `json:"display_order"`
+	FacultyIDs      []int64   `json:"faculty_ids"`
+	StudyProgramIDs []int64   `json:"study_program_ids"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ElectionRaceRequest struct {
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	Description     *string `json:"description,omitempty"`
+	DisplayOrder    int     `json:"display_order"`
+	FacultyIDs      []int64 `json:"faculty_ids"`
+	StudyProgramIDs []int64 `json:"study_program_ids"`
+}
+
+// Normalize trims text fields and upper-cases the code.
+func (r *ElectionRaceRequest) Normalize() {
+	r.Code = strings.ToUpper(strings.TrimSpace(r.Code))
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Description != nil {
+		d := strings.TrimSpace(*r.Description)
+		r.Description = &d
+	}
+}
+
+func (r ElectionRaceRequest) Validate() error {
+	if r.Code == "" || r.Name == "" {
+		return ErrInvalidRace
+	}
+	for _, id := range r.FacultyIDs {
+		if id <= 0 {
+			return ErrInvalidRace
+		}
+	}
+	for _, id := range r.StudyProgramIDs {
+		if id <= 0 {
+			return ErrInvalidRace
+		}
+	}
+	return nil
+}
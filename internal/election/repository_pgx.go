@@ -36,6 +36,7 @@ SELECT
     id,
     year,
     name,
+    code,
     slug,
     status,
     voting_start_at,
@@ -60,6 +61,7 @@ LIMIT 1
 		&e.ID,
 		&e.Year,
 		&e.Name,
+		&e.Code,
 		&e.Slug,
 		&e.Status,
 		&e.VotingStartAt,
@@ -86,6 +88,7 @@ SELECT
     id,
     year,
     name,
+    code,
     slug,
     status,
     voting_start_at,
@@ -109,6 +112,7 @@ LIMIT 1
 		&e.ID,
 		&e.Year,
 		&e.Name,
+		&e.Code,
 		&e.Slug,
 		&e.Status,
 		&e.VotingStartAt,
@@ -135,6 +139,7 @@ SELECT
     id,
     year,
     name,
+    code,
     slug,
     status,
     voting_start_at,
@@ -151,6 +156,7 @@ WHERE id = $1
 		&e.ID,
 		&e.Year,
 		&e.Name,
+		&e.Code,
 		&e.Slug,
 		&e.Status,
 		&e.VotingStartAt,
@@ -206,6 +212,7 @@ SELECT
     id,
     year,
     name,
+    code,
     slug,
     status,
     voting_start_at,
@@ -232,6 +239,7 @@ LIMIT 10
 			&e.ID,
 			&e.Year,
 			&e.Name,
+			&e.Code,
 			&e.Slug,
 			&e.Status,
 			&e.VotingStartAt,
@@ -260,6 +268,7 @@ SELECT
     id,
     year,
     name,
+    code,
     slug,
     status,
     voting_start_at,
@@ -276,6 +285,7 @@ WHERE id = $1
 		&e.ID,
 		&e.Year,
 		&e.Name,
+		&e.Code,
 		&e.Slug,
 		&e.Status,
 		&e.VotingStartAt,
@@ -0,0 +1,291 @@
+package election
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrResultNotFound         = errors.New("election result not found")
+	ErrResultsNotAvailable    = errors.New("election results not available yet")
+	ErrVotingNotClosed        = errors.New("voting has not closed")
+	ErrResultAlreadyCertified = errors.New("election result already certified")
+	ErrResultNotCertified     = errors.New("election result not certified")
+	ErrResultAlreadyPublished = errors.New("election result already published")
+	ErrResultHashMismatch     = errors.New("signed tally hash does not match the result")
+	ErrAlreadySignedOff       = errors.New("result already signed off by this account")
+	ErrSignerRequired         = errors.New("result sign-off needs a committee account")
+	ErrResultTampered         = errors.New("stored tally does not match its hash")
+)
+
+// DefaultResultSignoffsRequired is the number of committee sign-offs a
+// results snapshot needs unless configured otherwise.
+const DefaultResultSignoffsRequired = 3
+
+// Statuses of a results snapshot.
+const (
+	ResultStatusPendingSignoff = "PENDING_SIGNOFF"
+	ResultStatusCertified      = "CERTIFIED"
+	ResultStatusPublished      = "PUBLISHED"
+	ResultStatusSuperseded     = "SUPERSEDED"
+)
+
+// CandidateVotes is the vote count of one candidate within a breakdown.
+type CandidateVotes struct {
+	CandidateID int64 `json:"candidate_id"`
+	Votes       int64 `json:"votes"`
+}
+
+// CandidateResult is the final count of one candidate. Percentage is the share
+// of the non-abstain votes of the candidate's race.
+type CandidateResult struct {
+	CandidateID int64   `json:"candidate_id"`
+	RaceID      *int64  `json:"race_id,omitempty"`
+	Number      int     `json:"number"`
+	Name        string  `json:"name"`
+	Votes       int64   `json:"votes"`
+	Percentage  float64 `json:"percentage"`
+}
+
+// ResultBreakdown is the count of one channel, TPS or faculty.
+type ResultBreakdown struct {
+	Key          string           `json:"key"`
+	Name         string           `json:"name,omitempty"`
+	TPSID        *int64           `json:"tps_id,omitempty"`
+	TotalVotes   int64            `json:"total_votes"`
+	AbstainVotes int64            `json:"abstain_votes"`
+	Candidates   []CandidateVotes `json:"candidates"`
+}
+
+// ResultTally is the frozen content of a results snapshot. In ranked-choice
+// elections candidate counts and breakdowns are first preferences; the
+// round-by-round outcome is in IRV.
+type ResultTally struct {
+	ElectionID   int64             `json:"election_id"`
+	VotingSystem string            `json:"voting_system"`
+	TotalVotes   int64             `json:"total_votes"`
+	AbstainVotes int64             `json:"abstain_votes"`
+	Candidates   []CandidateResult `json:"candidates"`
+	Channels     []ResultBreakdown `json:"channels"`
+	TPS          []ResultBreakdown `json:"tps"`
+	Faculties    []ResultBreakdown `json:"faculties"`
+	IRV          *IRVReport        `json:"irv,omitempty"`
+}
+
+// ResultCandidateRow is a candidate included in the count.
+type ResultCandidateRow struct {
+	CandidateID int64
+	RaceID      *int64
+	Number      int
+	Name        string
+}
+
+// ResultVoteRow is the number of votes sharing one candidate (nil for
+// abstain), race, channel, TPS and faculty.
+type ResultVoteRow struct {
+	CandidateID *int64
+	RaceID      *int64
+	Channel     string
+	TPSID       *int64
+	TPSCode     string
+	TPSName     string
+	FacultyCode string
+	FacultyName string
+	Votes       int64
+}
+
+// ResultSignoff is one committee sign-off of a snapshot.
+type ResultSignoff struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	TallyHash string    `json:"tally_hash"`
+	Note      *string   `json:"note,omitempty"`
+	SignedAt  time.Time `json:"signed_at"`
+}
+
+// ElectionResult is a results snapshot with its sign-offs.
+type ElectionResult struct {
+	ID               int64           `json:"id"`
+	ElectionID       int64           `json:"election_id"`
+	Status           string          `json:"status"`
+	Tally            ResultTally     `json:"tally"`
+	TallyHash        string          `json:"tally_hash"`
+	SignoffsRequired int             `json:"signoffs_required"`
+	Signoffs         []ResultSignoff `json:"signoffs"`
+	ComputedBy       *int64          `json:"computed_by,omitempty"`
+	ComputedAt       time.Time       `json:"computed_at"`
+	CertifiedAt      *time.Time      `json:"certified_at,omitempty"`
+	PublishedAt      *time.Time      `json:"published_at,omitempty"`
+}
+
+// PublicElectionResult is the published result shown to everyone.
+type PublicElectionResult struct {
+	ElectionID  int64       `json:"election_id"`
+	Tally       ResultTally `json:"tally"`
+	TallyHash   string      `json:"tally_hash"`
+	CertifiedAt *time.Time  `json:"certified_at,omitempty"`
+	PublishedAt *time.Time  `json:"published_at,omitempty"`
+}
+
+// ResultSignoffRequest confirms the hash of the tally the signer reviewed.
+type ResultSignoffRequest struct {
+	TallyHash string  `json:"tally_hash"`
+	Note      *string `json:"note,omitempty"`
+}
+
+// BuildResultTally aggregates vote rows into a tally. Candidates keep the
+// order given; breakdowns are ordered by key.
+func BuildResultTally(electionID int64, votingSystem string, candidates []ResultCandidateRow, rows []ResultVoteRow) *ResultTally {
+	tally := &ResultTally{
+		ElectionID:   electionID,
+		VotingSystem: votingSystem,
+		Candidates:   make([]CandidateResult, 0, len(candidates)),
+	}
+
+	candidateVotes := make(map[int64]int64)
+	raceTotals := make(map[int64]int64)
+	channels := newBreakdowns()
+	tpsBreakdowns := newBreakdowns()
+	faculties := newBreakdowns()
+
+	for _, row := range rows {
+		if row.CandidateID == nil {
+			tally.AbstainVotes += row.Votes
+		} else {
+			tally.TotalVotes += row.Votes
+			candidateVotes[*row.CandidateID] += row.Votes
+			raceTotals[raceKey(row.RaceID)] += row.Votes
+		}
+
+		channels.add(row.Channel, "", nil, row)
+		if row.TPSID != nil {
+			tpsBreakdowns.add(row.TPSCode, row.TPSName, row.TPSID, row)
+		}
+		facultyKey := row.FacultyCode
+		if facultyKey == "" {
+			facultyKey = "UNKNOWN"
+		}
+		faculties.add(facultyKey, row.FacultyName, nil, row)
+	}
+
+	for _, c := range candidates {
+		result := CandidateResult{
+			CandidateID: c.CandidateID,
+			RaceID:      c.RaceID,
+			Number:      c.Number,
+			Name:        c.Name,
+			Votes:       candidateVotes[c.CandidateID],
+		}
+		if total := raceTotals[raceKey(c.RaceID)]; total > 0 {
+			result.Percentage = float64(result.Votes) * 100 / float64(total)
+		}
+		tally.Candidates = append(tally.Candidates, result)
+	}
+
+	tally.Channels = channels.list()
+	tally.TPS = tpsBreakdowns.list()
+	tally.Faculties = faculties.list()
+	return tally
+}
+
+func raceKey(raceID *int64) int64 {
+	if raceID == nil {
+		return 0
+	}
+	return *raceID
+}
+
+type breakdowns struct {
+	byKey  map[string]*ResultBreakdown
+	counts map[string]map[int64]int64
+}
+
+func newBreakdowns() *breakdowns {
+	return &breakdowns{
+		byKey:  make(map[string]*ResultBreakdown),
+		counts: make(map[string]map[int64]int64),
+	}
+}
+
+func (b *breakdowns) add(key, name string, tpsID *int64, row ResultVoteRow) {
+	entry, ok := b.byKey[key]
+	if !ok {
+		entry = &ResultBreakdown{Key: key, Name: name, TPSID: tpsID}
+		b.byKey[key] = entry
+		b.counts[key] = make(map[int64]int64)
+	}
+	if row.CandidateID == nil {
+		entry.AbstainVotes += row.Votes
+		return
+	}
+	entry.TotalVotes += row.Votes
+	b.counts[key][*row.CandidateID] += row.Votes
+}
+
+func (b *breakdowns) list() []ResultBreakdown {
+	keys := make([]string, 0, len(b.byKey))
+	for key := range b.byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]ResultBreakdown, 0, len(keys))
+	for _, key := range keys {
+		entry := *b.byKey[key]
+		entry.Candidates = make([]CandidateVotes, 0, len(b.counts[key]))
+		for id, votes := range b.counts[key] {
+			entry.Candidates = append(entry.Candidates, CandidateVotes{CandidateID: id, Votes: votes})
+		}
+		sort.Slice(entry.Candidates, func(i, j int) bool {
+			return entry.Candidates[i].CandidateID < entry.Candidates[j].CandidateID
+		})
+		list = append(list, entry)
+	}
+	return list
+}
+
+// EncodeResultTally returns the canonical JSON of a tally and its sha256 in
+// hex. The encoding is stable, so a stored tally can be re-hashed to verify it.
+func EncodeResultTally(t *ResultTally) ([]byte, string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyResultTally re-hashes a stored tally and compares it to its hash.
+func VerifyResultTally(t *ResultTally, hash string) error {
+	_, sum, err := EncodeResultTally(t)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sum, hash) {
+		return ErrResultTampered
+	}
+	return nil
+}
+
+// votingClosed reports whether voting has ended for good, so votes can be counted.
+func votingClosed(status ElectionStatus) bool {
+	switch status {
+	case ElectionStatusVotingClosed, ElectionStatusClosed, ElectionStatusRecap:
+		return true
+	}
+	return false
+}
+
+// resultsVisible reports whether published results may be shown publicly:
+// the snapshot is published and the announcement time has come.
+func resultsVisible(result *ElectionResult, announcementAt *time.Time, now time.Time) bool {
+	if result == nil || result.Status != ResultStatusPublished {
+		return false
+	}
+	return announcementAt != nil && !now.Before(*announcementAt)
+}
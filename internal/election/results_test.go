@@ -0,0 +1,108 @@
+package election
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildResultTally(t *testing.T) {
+	c1, c2 := int64(1), int64(2)
+	tps := int64(7)
+	candidates := []ResultCandidateRow{
+		{CandidateID: 1, Number: 1, Name: "A"},
+		{CandidateID: 2, Number: 2, Name: "B"},
+	}
+	rows := []ResultVoteRow{
+		{CandidateID: &c1, Channel: "ONLINE", FacultyCode: "FT", FacultyName: "Teknik", Votes: 3},
+		{CandidateID: &c2, Channel: "TPS", TPSID: &tps, TPSCode: "TPS01", TPSName: "Aula", FacultyCode: "FT", Votes: 1},
+		{CandidateID: nil, Channel: "TPS", TPSID: &tps, TPSCode: "TPS01", TPSName: "Aula", Votes: 2},
+	}
+
+	tally := BuildResultTally(10, VotingSystemPlurality, candidates, rows)
+
+	if tally.TotalVotes != 4 || tally.AbstainVotes != 2 {
+		t.Fatalf("totals = %d/%d, want 4/2", tally.TotalVotes, tally.AbstainVotes)
+	}
+	if got := tally.Candidates[0]; got.Votes != 3 || got.Percentage != 75 {
+		t.Errorf("candidate A = %+v, want 3 votes at 75%%", got)
+	}
+	if got := tally.Candidates[1]; got.Votes != 1 || got.Percentage != 25 {
+		t.Errorf("candidate B = %+v, want 1 vote at 25%%", got)
+	}
+
+	if len(tally.Channels) != 2 || tally.Channels[0].Key != "ONLINE" || tally.Channels[1].Key != "TPS" {
+		t.Fatalf("channels = %+v", tally.Channels)
+	}
+	if tps := tally.Channels[1]; tps.TotalVotes != 1 || tps.AbstainVotes != 2 {
+		t.Errorf("TPS channel = %+v, want 1 vote and 2 abstain", tps)
+	}
+	if len(tally.TPS) != 1 || tally.TPS[0].Key != "TPS01" || *tally.TPS[0].TPSID != 7 {
+		t.Errorf("tps breakdown = %+v", tally.TPS)
+	}
+	if len(tally.Faculties) != 2 || tally.Faculties[0].Key != "FT" || tally.Faculties[1].Key != "UNKNOWN" {
+		t.Errorf("faculty breakdown = %+v", tally.Faculties)
+	}
+}
+
+func TestResultTallyHashRoundTrip(t *testing.T) {
+	c1 := int64(1)
+	tally := BuildResultTally(10, VotingSystemPlurality,
+		[]ResultCandidateRow{{CandidateID: 1, Number: 1, Name: "A"}},
+		[]ResultVoteRow{{CandidateID: &c1, Channel: "ONLINE", Votes: 5}},
+	)
+
+	data, hash, err := EncodeResultTally(tally)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tally is stored as JSONB and read back before it is verified.
+	var stored ResultTally
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyResultTally(&stored, hash); err != nil {
+		t.Fatalf("verify stored tally: %v", err)
+	}
+
+	stored.Candidates[0].Votes++
+	if err := VerifyResultTally(&stored, hash); !errors.Is(err, ErrResultTampered) {
+		t.Fatalf("verify modified tally = %v, want ErrResultTampered", err)
+	}
+}
+
+func TestResultsVisible(t *testing.T) {
+	now := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Minute), now.Add(time.Minute)
+
+	tests := []struct {
+		name         string
+		status       string
+		announcement *time.Time
+		want         bool
+	}{
+		{"published after announcement", ResultStatusPublished, &before, true},
+		{"published before announcement", ResultStatusPublished, &after, false},
+		{"published without announcement", ResultStatusPublished, nil, false},
+		{"certified only", ResultStatusCertified, &before, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resultsVisible(&ElectionResult{Status: tt.status}, tt.announcement, now)
+			if got != tt.want {
+				t.Errorf("resultsVisible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignOffResultsNeedsSigner(t *testing.T) {
+	s := &AdminService{}
+	_, err := s.SignOffResults(context.Background(), 1, StatusActor{Type: "SYSTEM"}, ResultSignoffRequest{TallyHash: "abc"})
+	if !errors.Is(err, ErrSignerRequired) {
+		t.Errorf("got %v, want ErrSignerRequired", err)
+	}
+}
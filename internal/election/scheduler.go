@@ -0,0 +1,159 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// schedulerLockKey is the advisory lock key held by the replica running a
+// scheduler tick, so only one API instance advances statuses at a time.
+const schedulerLockKey int64 = 0x50454d495241 // "PEMIRA"
+
+// ScheduleStep is the status an election enters when its schedule reaches At.
+type ScheduleStep struct {
+	Status ElectionStatus
+	Phase  ElectionPhaseKey
+	At     *time.Time
+}
+
+// statusRank orders statuses along the schedule. Statuses without a rank
+// (ARCHIVED) are never advanced automatically.
+var statusRank = map[ElectionStatus]int{
+	ElectionStatusDraft:            0,
+	ElectionStatusRegistration:     1,
+	ElectionStatusRegistrationOpen: 1,
+	ElectionStatusVerification:     2,
+	ElectionStatusCampaign:         3,
+	ElectionStatusQuietPeriod:      4,
+	ElectionStatusVotingOpen:       5,
+	ElectionStatusVotingClosed:     6,
+	ElectionStatusClosed:           6,
+	ElectionStatusRecap:            7,
+}
+
+// scheduleSteps lists the schedule boundaries of an election in order.
+// Voting closes at VotingEndAt; the other statuses start with their phase.
+func scheduleSteps(e *AdminElectionDTO) []ScheduleStep {
+	return []ScheduleStep{
+		{ElectionStatusRegistration, PhaseKeyRegistration, e.RegistrationStartAt},
+		{ElectionStatusVerification, PhaseKeyVerification, e.VerificationStartAt},
+		{ElectionStatusCampaign, PhaseKeyCampaign, e.CampaignStartAt},
+		{ElectionStatusQuietPeriod, PhaseKeyQuietPeriod, e.QuietStartAt},
+		{ElectionStatusVotingOpen, PhaseKeyVoting, e.VotingStartAt},
+		{ElectionStatusVotingClosed, PhaseKeyRecap, e.VotingEndAt},
+		{ElectionStatusRecap, PhaseKeyRecap, e.RecapStartAt},
+	}
+}
+
+// NextScheduledStep returns the step the election should be in at now when
+// that is ahead of its current status. Statuses only move forward: a phase
+// an admin already moved past (e.g. voting closed early) is never re-entered,
+// and phases whose window was missed entirely are skipped.
+func NextScheduledStep(e *AdminElectionDTO, now time.Time) (ScheduleStep, bool) {
+	current, ok := statusRank[e.Status]
+	if !ok {
+		return ScheduleStep{}, false
+	}
+
+	var target ScheduleStep
+	found := false
+	for _, step := range scheduleSteps(e) {
+		if step.At != nil && !now.Before(*step.At) {
+			target = step
+			found = true
+		}
+	}
+	if !found || statusRank[target.Status] <= current {
+		return ScheduleStep{}, false
+	}
+	return target, true
+}
+
+// Scheduler advances election statuses along their phase schedule. Every
+// replica may run one; an advisory lock makes sure a tick runs on one only.
+type Scheduler struct {
+	db       *pgxpool.Pool
+	svc      *AdminService
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewScheduler advances statuses through svc, so scheduled transitions use the
+// same notifier and sign-off settings as the admin API.
+func NewScheduler(db *pgxpool.Pool, svc *AdminService, interval time.Duration, logger *slog.Logger) *Scheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{db: db, svc: svc, interval: interval, logger: logger}
+}
+
+// Run ticks until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("election scheduler started", "interval", s.interval.String())
+	for {
+		if err := s.Tick(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Error("election scheduler tick failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("election scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick advances every election whose schedule has moved ahead of its status.
+// It does nothing when another replica holds the scheduler lock.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, schedulerLockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	elections, err := s.svc.repo.ListSchedulableElections(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range elections {
+		e := &elections[i]
+		step, ok := NextScheduledStep(e, now)
+		if !ok {
+			continue
+		}
+
+		if _, err := s.svc.AdvanceBySchedule(ctx, e, step); err != nil {
+			// A guard failure (e.g. empty DPT) or a move the transition table
+			// does not allow needs an admin; it is retried every tick.
+			s.logger.Warn("election scheduler transition rejected",
+				"election_id", e.ID, "from", e.Status, "to", step.Status, "error", err)
+			continue
+		}
+		s.logger.Info("election status advanced by schedule",
+			"election_id", e.ID,
+			"from", e.Status,
+			"to", step.Status,
+			"scheduled_at", step.At.UTC(),
+		)
+	}
+
+	return tx.Commit(ctx)
+}
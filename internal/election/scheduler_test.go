@@ -0,0 +1,58 @@
+package election
+
+import (
+	"testing"
+	"time"
+)
+
+func scheduledElection(status ElectionStatus) *AdminElectionDTO {
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 2, day, 8, 0, 0, 0, time.UTC)
+		return &t
+	}
+	return &AdminElectionDTO{
+		Status:              status,
+		RegistrationStartAt: at(1),
+		VerificationStartAt: at(5),
+		CampaignStartAt:     at(8),
+		QuietStartAt:        at(12),
+		VotingStartAt:       at(14),
+		VotingEndAt:         at(15),
+		RecapStartAt:        at(16),
+	}
+}
+
+func TestNextScheduledStep_AdvancesToCurrentPhase(t *testing.T) {
+	e := scheduledElection(ElectionStatusCampaign)
+	now := time.Date(2025, 2, 14, 8, 0, 0, 0, time.UTC)
+
+	step, ok := NextScheduledStep(e, now)
+	if !ok || step.Status != ElectionStatusVotingOpen || step.Phase != PhaseKeyVoting {
+		t.Fatalf("expected VOTING_OPEN (skipping QUIET_PERIOD), got %+v %v", step, ok)
+	}
+}
+
+func TestNextScheduledStep_ClosesVotingAtEnd(t *testing.T) {
+	e := scheduledElection(ElectionStatusVotingOpen)
+
+	if _, ok := NextScheduledStep(e, time.Date(2025, 2, 15, 7, 59, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected no transition before voting end")
+	}
+	step, ok := NextScheduledStep(e, time.Date(2025, 2, 15, 8, 0, 0, 0, time.UTC))
+	if !ok || step.Status != ElectionStatusVotingClosed {
+		t.Fatalf("expected VOTING_CLOSED at voting end, got %+v %v", step, ok)
+	}
+}
+
+func TestNextScheduledStep_NeverMovesBackwards(t *testing.T) {
+	// Closed early by an admin while the voting window is still open.
+	e := scheduledElection(ElectionStatusVotingClosed)
+	if _, ok := NextScheduledStep(e, time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected closed election not to be reopened")
+	}
+
+	archived := scheduledElection(ElectionStatusArchived)
+	if _, ok := NextScheduledStep(archived, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected archived election to be left alone")
+	}
+}
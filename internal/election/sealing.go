@@ -0,0 +1,50 @@
+package election
+
+import (
+	"context"
+	"errors"
+
+	"pemira-api/internal/shared/constants"
+	"pemira-api/internal/shared/ctxkeys"
+)
+
+// ErrResultsSealed is returned for per-candidate numbers requested while the
+// election's results are sealed.
+var ErrResultsSealed = errors.New("election results are sealed until voting closes")
+
+// ResultSeal reports whether the per-candidate numbers of an election are
+// sealed right now (implemented by PgAdminRepository).
+type ResultSeal interface {
+	ResultsSealed(ctx context.Context, electionID int64) (bool, error)
+}
+
+// resultsSealed reports whether a sealed election still withholds its
+// per-candidate numbers: until voting has closed, including any TPS whose
+// voting was extended past the election's close.
+func resultsSealed(sealed bool, status ElectionStatus, extensionOpen bool) bool {
+	if !sealed {
+		return false
+	}
+	if status == ElectionStatusArchived {
+		return false
+	}
+	return !votingClosed(status) || extensionOpen
+}
+
+// CanViewSealedResults reports whether role is the break-glass role that may
+// see per-candidate numbers while they are sealed.
+func CanViewSealedResults(role string) bool {
+	return role == string(constants.RoleSuperAdmin)
+}
+
+// SealedFor reports whether per-candidate numbers of the election must be
+// withheld from the caller in ctx. A nil seal never seals.
+func SealedFor(ctx context.Context, seal ResultSeal, electionID int64) (bool, error) {
+	if seal == nil {
+		return false, nil
+	}
+	if role, ok := ctxkeys.GetUserRole(ctx); ok && CanViewSealedResults(role) {
+		return false, nil
+	}
+	return seal.ResultsSealed(ctx, electionID)
+}
@@ -0,0 +1,55 @@
+package election
+
+import (
+	"context"
+	"testing"
+
+	"pemira-api/internal/shared/ctxkeys"
+)
+
+func TestResultsSealed(t *testing.T) {
+	tests := []struct {
+		name          string
+		sealed        bool
+		status        ElectionStatus
+		extensionOpen bool
+		want          bool
+	}{
+		{"not sealed", false, ElectionStatusVotingOpen, false, false},
+		{"sealed while voting", true, ElectionStatusVotingOpen, false, true},
+		{"sealed before voting", true, ElectionStatusCampaign, false, true},
+		{"sealed after close", true, ElectionStatusVotingClosed, false, false},
+		{"sealed with TPS extension", true, ElectionStatusVotingClosed, true, true},
+		{"sealed in recap", true, ElectionStatusRecap, false, false},
+		{"sealed archived", true, ElectionStatusArchived, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultsSealed(tt.sealed, tt.status, tt.extensionOpen); got != tt.want {
+				t.Errorf("resultsSealed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fixedSeal bool
+
+func (s fixedSeal) ResultsSealed(context.Context, int64) (bool, error) { return bool(s), nil }
+
+func TestSealedFor(t *testing.T) {
+	admin := context.WithValue(context.Background(), ctxkeys.UserRoleKey, "ADMIN")
+	superAdmin := context.WithValue(context.Background(), ctxkeys.UserRoleKey, "SUPER_ADMIN")
+
+	if sealed, _ := SealedFor(admin, fixedSeal(true), 1); !sealed {
+		t.Error("admin sees sealed results")
+	}
+	if sealed, _ := SealedFor(context.Background(), fixedSeal(true), 1); !sealed {
+		t.Error("anonymous caller sees sealed results")
+	}
+	if sealed, _ := SealedFor(superAdmin, fixedSeal(true), 1); sealed {
+		t.Error("break-glass role is sealed out")
+	}
+	if sealed, _ := SealedFor(admin, nil, 1); sealed {
+		t.Error("nil seal seals results")
+	}
+}
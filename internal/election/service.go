@@ -305,3 +305,36 @@ func (s *Service) GetMeHistory(ctx context.Context, authUser auth.AuthUser, elec
 
 	return s.repo.GetHistory(ctx, electionID, *authUser.VoterID, authUser.ID)
 }
+
+// GetPublishedResults returns the certified results of an election once they
+// are published and the announcement time has come.
+func (s *Service) GetPublishedResults(ctx context.Context, electionID int64) (*PublicElectionResult, error) {
+	if s.adminRepo == nil {
+		return nil, ErrResultsNotAvailable
+	}
+	e, err := s.adminRepo.GetElectionByID(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.adminRepo.GetCurrentResult(ctx, electionID)
+	if err != nil {
+		if errors.Is(err, ErrResultNotFound) {
+			return nil, ErrResultsNotAvailable
+		}
+		return nil, err
+	}
+	if !resultsVisible(result, e.AnnouncementAt, time.Now().UTC()) {
+		return nil, ErrResultsNotAvailable
+	}
+	if err := VerifyResultTally(&result.Tally, result.TallyHash); err != nil {
+		return nil, err
+	}
+
+	return &PublicElectionResult{
+		ElectionID:  electionID,
+		Tally:       result.Tally,
+		TallyHash:   result.TallyHash,
+		CertifiedAt: result.CertifiedAt,
+		PublishedAt: result.PublishedAt,
+	}, nil
+}
@@ -0,0 +1,166 @@
+package election
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoPublishedCandidates = errors.New("election has no published candidate")
+	ErrEmptyDPT              = errors.New("election has no voters in DPT")
+)
+
+// Actor types recorded in the status history.
+const (
+	StatusActorAdmin  = "ADMIN"
+	StatusActorSystem = "SYSTEM"
+)
+
+// statusTransitions is the transition table: the statuses an election may
+// move to from each status. Preparation phases may be skipped; once voting
+// opens an election only moves on to closing, recap and archive.
+var statusTransitions = map[ElectionStatus][]ElectionStatus{
+	ElectionStatusDraft: {
+		ElectionStatusRegistration, ElectionStatusRegistrationOpen, ElectionStatusVerification,
+		ElectionStatusCampaign, ElectionStatusQuietPeriod, ElectionStatusVotingOpen,
+	},
+	ElectionStatusRegistration: {
+		ElectionStatusRegistrationOpen, ElectionStatusVerification, ElectionStatusCampaign,
+		ElectionStatusQuietPeriod, ElectionStatusVotingOpen,
+	},
+	ElectionStatusRegistrationOpen: {
+		ElectionStatusVerification, ElectionStatusCampaign, ElectionStatusQuietPeriod, ElectionStatusVotingOpen,
+	},
+	ElectionStatusVerification: {ElectionStatusCampaign, ElectionStatusQuietPeriod, ElectionStatusVotingOpen},
+	ElectionStatusCampaign:     {ElectionStatusQuietPeriod, ElectionStatusVotingOpen},
+	ElectionStatusQuietPeriod:  {ElectionStatusVotingOpen},
+	ElectionStatusVotingOpen:   {ElectionStatusVotingClosed},
+	ElectionStatusVotingClosed: {ElectionStatusRecap, ElectionStatusArchived},
+	ElectionStatusClosed:       {ElectionStatusRecap, ElectionStatusArchived},
+	ElectionStatusRecap:        {ElectionStatusArchived},
+	ElectionStatusArchived:     {},
+}
+
+// CanTransition reports whether the transition table allows from -> to.
+func CanTransition(from, to ElectionStatus) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionPrerequisites is the election state checked by transition guards.
+type TransitionPrerequisites struct {
+	PublishedCandidates int64
+	DPTVoters           int64
+}
+
+// CheckTransition validates a transition against the table and the
+// preconditions of the target status.
+func CheckTransition(from, to ElectionStatus, pre TransitionPrerequisites) error {
+	if !CanTransition(from, to) {
+		return ErrInvalidStatusChange
+	}
+	if to == ElectionStatusVotingOpen {
+		if pre.PublishedCandidates == 0 {
+			return ErrNoPublishedCandidates
+		}
+		if pre.DPTVoters == 0 {
+			return ErrEmptyDPT
+		}
+	}
+	return nil
+}
+
+// StatusTransition is one status change as applied by AdminRepository.TransitionStatus.
+// The update only applies while the election is still in From.
+type StatusTransition struct {
+	ElectionID    int64
+	From          ElectionStatus
+	To            ElectionStatus
+	CurrentPhase  *string
+	VotingStartAt *time.Time
+	VotingEndAt   *time.Time
+	ActorType     string
+	ActorUserID   *int64
+	Reason        *string
+}
+
+// StatusHistoryEntry is one row of election_status_history.
+type StatusHistoryEntry struct {
+	ID          int64          `json:"id"`
+	ElectionID  int64          `json:"election_id"`
+	FromStatus  ElectionStatus `json:"from_status"`
+	ToStatus    ElectionStatus `json:"to_status"`
+	ActorType   string         `json:"actor_type"`
+	ActorUserID *int64         `json:"actor_user_id,omitempty"`
+	Reason      *string        `json:"reason,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// StatusChangeRequest is the optional body of the open/close/archive actions.
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// votingStarted reports whether voting has opened at some point.
+func votingStarted(status ElectionStatus) bool {
+	switch status {
+	case ElectionStatusVotingOpen, ElectionStatusVotingClosed, ElectionStatusClosed,
+		ElectionStatusRecap, ElectionStatusArchived:
+		return true
+	}
+	return false
+}
+
+// validateElectionUpdate rejects updates that contradict the election status:
+// archived elections are read-only, and the voting window and channels are
+// frozen once voting has started.
+func validateElectionUpdate(current *AdminElectionDTO, req AdminElectionUpdateRequest) error {
+	if current.Status == ElectionStatusArchived {
+		return ErrElectionArchived
+	}
+
+	if votingStarted(current.Status) {
+		if timeChanged(current.VotingStartAt, req.VotingStartAt) || timeChanged(current.VotingEndAt, req.VotingEndAt) {
+			return ErrVotingPhaseLocked
+		}
+		if (req.OnlineEnabled != nil && *req.OnlineEnabled != current.OnlineEnabled) ||
+			(req.TPSEnabled != nil && *req.TPSEnabled != current.TPSEnabled) {
+			return ErrElectionAlreadyStarted
+		}
+	}
+
+	online, tps := current.OnlineEnabled, current.TPSEnabled
+	if req.OnlineEnabled != nil {
+		online = *req.OnlineEnabled
+	}
+	if req.TPSEnabled != nil {
+		tps = *req.TPSEnabled
+	}
+	if !online && !tps {
+		return ErrInvalidModeCombination
+	}
+
+	start, end := current.VotingStartAt, current.VotingEndAt
+	if req.VotingStartAt != nil {
+		start = req.VotingStartAt
+	}
+	if req.VotingEndAt != nil {
+		end = req.VotingEndAt
+	}
+	if start != nil && end != nil && !start.Before(*end) {
+		return ErrPhaseTimeConflict
+	}
+	return nil
+}
+
+// timeChanged reports whether an optional update value differs from the current one.
+func timeChanged(current, update *time.Time) bool {
+	if update == nil {
+		return false
+	}
+	return current == nil || !current.Equal(*update)
+}
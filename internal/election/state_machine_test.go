@@ -0,0 +1,87 @@
+package election
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckTransition(t *testing.T) {
+	ready := TransitionPrerequisites{PublishedCandidates: 2, DPTVoters: 100}
+
+	tests := []struct {
+		name string
+		from ElectionStatus
+		to   ElectionStatus
+		pre  TransitionPrerequisites
+		want error
+	}{
+		{"skip phases to voting", ElectionStatusDraft, ElectionStatusVotingOpen, ready, nil},
+		{"campaign to quiet period", ElectionStatusCampaign, ElectionStatusQuietPeriod, ready, nil},
+		{"backwards", ElectionStatusCampaign, ElectionStatusRegistration, ready, ErrInvalidStatusChange},
+		{"reopen closed voting", ElectionStatusVotingClosed, ElectionStatusVotingOpen, ready, ErrInvalidStatusChange},
+		{"archive before voting", ElectionStatusCampaign, ElectionStatusArchived, ready, ErrInvalidStatusChange},
+		{"leave archive", ElectionStatusArchived, ElectionStatusRecap, ready, ErrInvalidStatusChange},
+		{"no published candidate", ElectionStatusQuietPeriod, ElectionStatusVotingOpen, TransitionPrerequisites{DPTVoters: 100}, ErrNoPublishedCandidates},
+		{"empty DPT", ElectionStatusQuietPeriod, ElectionStatusVotingOpen, TransitionPrerequisites{PublishedCandidates: 2}, ErrEmptyDPT},
+		{"guards only apply to voting open", ElectionStatusDraft, ElectionStatusCampaign, TransitionPrerequisites{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckTransition(tt.from, tt.to, tt.pre); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateElectionUpdate(t *testing.T) {
+	start := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	later := end.Add(time.Hour)
+	disabled := false
+
+	open := &AdminElectionDTO{
+		Status:        ElectionStatusVotingOpen,
+		VotingStartAt: &start,
+		VotingEndAt:   &end,
+		OnlineEnabled: true,
+		TPSEnabled:    true,
+	}
+	draft := *open
+	draft.Status = ElectionStatusDraft
+
+	tests := []struct {
+		name    string
+		current *AdminElectionDTO
+		req     AdminElectionUpdateRequest
+		want    error
+	}{
+		{"draft may move voting window", &draft, AdminElectionUpdateRequest{VotingEndAt: &later}, nil},
+		{"voting window locked once open", open, AdminElectionUpdateRequest{VotingEndAt: &later}, ErrVotingPhaseLocked},
+		{"unchanged window is accepted", open, AdminElectionUpdateRequest{VotingStartAt: &start}, nil},
+		{"channels locked once open", open, AdminElectionUpdateRequest{TPSEnabled: &disabled}, ErrElectionAlreadyStarted},
+		{"start after end", &draft, AdminElectionUpdateRequest{VotingStartAt: &later}, ErrPhaseTimeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateElectionUpdate(tt.current, tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	noChannels := draft
+	noChannels.TPSEnabled = false
+	if err := validateElectionUpdate(&noChannels, AdminElectionUpdateRequest{OnlineEnabled: &disabled}); !errors.Is(err, ErrInvalidModeCombination) {
+		t.Fatalf("expected ErrInvalidModeCombination, got %v", err)
+	}
+
+	archived := draft
+	archived.Status = ElectionStatusArchived
+	if err := validateElectionUpdate(&archived, AdminElectionUpdateRequest{}); !errors.Is(err, ErrElectionArchived) {
+		t.Fatalf("expected ErrElectionArchived, got %v", err)
+	}
+}
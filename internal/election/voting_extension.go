@@ -0,0 +1,139 @@
+package election
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrExtensionReasonRequired = errors.New("voting extension requires a reason")
+	ErrInvalidExtensionEnd     = errors.New("voting extension must end in the future, after the current end and before recap")
+	ErrResultsPublished        = errors.New("election results already published")
+	ErrTPSNotInElection        = errors.New("tps does not belong to election")
+	ErrTPSModeDisabled         = errors.New("tps voting disabled for election")
+)
+
+// EventVotingExtended is broadcast when the voting window is extended or reopened.
+const EventVotingExtended = "voting.extended"
+
+// Notifier pushes real-time events to subscribed clients.
+type Notifier interface {
+	Publish(channel, eventType string, data any)
+}
+
+// ElectionChannel is the real-time channel of election-wide events.
+func ElectionChannel(electionID int64) string {
+	return fmt.Sprintf("election:%d", electionID)
+}
+
+// TPSChannel is the real-time channel of one TPS.
+func TPSChannel(tpsID int64) string {
+	return fmt.Sprintf("tps:%d", tpsID)
+}
+
+// VotingExtensionRequest extends voting until VotingEndAt, for the whole
+// election or, when TPSIDs is set, for those TPS only.
+type VotingExtensionRequest struct {
+	VotingEndAt *time.Time `json:"voting_end_at"`
+	TPSIDs      []int64    `json:"tps_ids,omitempty"`
+	Reason      string     `json:"reason"`
+}
+
+// VotingExtension is one row of voting_extensions. TPSID is nil for an
+// election-wide extension.
+type VotingExtension struct {
+	ID            int64      `json:"id"`
+	ElectionID    int64      `json:"election_id"`
+	TPSID         *int64     `json:"tps_id,omitempty"`
+	PreviousEndAt *time.Time `json:"previous_end_at,omitempty"`
+	VotingEndAt   time.Time  `json:"voting_end_at"`
+	Reason        string     `json:"reason"`
+	CreatedBy     *int64     `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// VotingExtensionResult is the election after an extension and the rows recorded for it.
+type VotingExtensionResult struct {
+	Election   *AdminElectionDTO `json:"election"`
+	Reopened   bool              `json:"reopened"`
+	Extensions []VotingExtension `json:"extensions"`
+}
+
+// resultsPublished reports whether results are out, after which the voting
+// window can no longer change.
+func resultsPublished(status ElectionStatus) bool {
+	return status == ElectionStatusRecap || status == ElectionStatusArchived
+}
+
+// canExtendVoting reports whether the voting window of an election in status
+// may be extended: while voting is open, or reopened after it closed.
+func canExtendVoting(status ElectionStatus) bool {
+	switch status {
+	case ElectionStatusVotingOpen, ElectionStatusVotingClosed, ElectionStatusClosed:
+		return true
+	}
+	return false
+}
+
+// validateVotingExtension checks an extension request against the election
+// and normalizes its reason and TPS list.
+func validateVotingExtension(e *AdminElectionDTO, req *VotingExtensionRequest, now time.Time) error {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return ErrExtensionReasonRequired
+	}
+	if resultsPublished(e.Status) {
+		return ErrResultsPublished
+	}
+	if !canExtendVoting(e.Status) {
+		return ErrElectionNotInVotingPhase
+	}
+	if req.VotingEndAt == nil || !req.VotingEndAt.After(now) {
+		return ErrInvalidExtensionEnd
+	}
+	// While voting is open an extension must push the end back, for a single
+	// TPS too: it only matters once the rest of the election has closed.
+	if e.Status == ElectionStatusVotingOpen && e.VotingEndAt != nil && !req.VotingEndAt.After(*e.VotingEndAt) {
+		return ErrInvalidExtensionEnd
+	}
+	// The schedule would otherwise move the election to recap mid-extension.
+	if e.RecapStartAt != nil && !req.VotingEndAt.Before(*e.RecapStartAt) {
+		return ErrInvalidExtensionEnd
+	}
+
+	if len(req.TPSIDs) > 0 {
+		if !e.TPSEnabled {
+			return ErrTPSModeDisabled
+		}
+		seen := make(map[int64]struct{}, len(req.TPSIDs))
+		ids := make([]int64, 0, len(req.TPSIDs))
+		for _, id := range req.TPSIDs {
+			if id <= 0 {
+				return ErrTPSNotInElection
+			}
+			if _, dup := seen[id]; dup {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+		req.TPSIDs = ids
+	}
+	return nil
+}
+
+// VotingExtensionWrite is an extension as applied by AdminRepository.ExtendVoting.
+// It only applies while the election is still in From. Reopen moves a closed
+// election back to VOTING_OPEN and records it in the status history.
+type VotingExtensionWrite struct {
+	ElectionID    int64
+	From          ElectionStatus
+	Reopen        bool
+	PreviousEndAt *time.Time
+	VotingEndAt   time.Time
+	TPSIDs        []int64
+	Reason        string
+	ActorUserID   *int64
+}
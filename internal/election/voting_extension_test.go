@@ -0,0 +1,78 @@
+package election
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateVotingExtension(t *testing.T) {
+	now := time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC)
+	end := now.Add(-time.Hour)
+	recap := now.Add(24 * time.Hour)
+	extendTo := now.Add(2 * time.Hour)
+	past := now.Add(-time.Minute)
+	afterRecap := recap.Add(time.Hour)
+
+	closed := &AdminElectionDTO{
+		Status:       ElectionStatusVotingClosed,
+		VotingEndAt:  &end,
+		RecapStartAt: &recap,
+		TPSEnabled:   true,
+	}
+	open := *closed
+	open.Status = ElectionStatusVotingOpen
+	openLater := open
+	laterEnd := now.Add(3 * time.Hour)
+	openLater.VotingEndAt = &laterEnd
+	recapped := *closed
+	recapped.Status = ElectionStatusRecap
+	campaign := *closed
+	campaign.Status = ElectionStatusCampaign
+	onlineOnly := *closed
+	onlineOnly.TPSEnabled = false
+
+	tests := []struct {
+		name     string
+		election *AdminElectionDTO
+		req      VotingExtensionRequest
+		want     error
+	}{
+		{"reopen closed voting", closed, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "gangguan jaringan"}, nil},
+		{"extend open voting", &open, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "gangguan jaringan"}, nil},
+		{"reason required", closed, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "   "}, ErrExtensionReasonRequired},
+		{"results published", &recapped, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "x"}, ErrResultsPublished},
+		{"voting never opened", &campaign, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "x"}, ErrElectionNotInVotingPhase},
+		{"end in the past", closed, VotingExtensionRequest{VotingEndAt: &past, Reason: "x"}, ErrInvalidExtensionEnd},
+		{"end before current end", &openLater, VotingExtensionRequest{VotingEndAt: &extendTo, Reason: "x"}, ErrInvalidExtensionEnd},
+		{"end after recap starts", closed, VotingExtensionRequest{VotingEndAt: &afterRecap, Reason: "x"}, ErrInvalidExtensionEnd},
+		{"tps without tps mode", &onlineOnly, VotingExtensionRequest{VotingEndAt: &extendTo, TPSIDs: []int64{1}, Reason: "x"}, ErrTPSModeDisabled},
+		{"invalid tps id", closed, VotingExtensionRequest{VotingEndAt: &extendTo, TPSIDs: []int64{0}, Reason: "x"}, ErrTPSNotInElection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if err := validateVotingExtension(tt.election, &req, now); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateVotingExtension_NormalizesRequest(t *testing.T) {
+	now := time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC)
+	extendTo := now.Add(time.Hour)
+	e := &AdminElectionDTO{Status: ElectionStatusVotingClosed, TPSEnabled: true}
+	req := VotingExtensionRequest{VotingEndAt: &extendTo, TPSIDs: []int64{3, 1, 3}, Reason: "  listrik padam  "}
+
+	if err := validateVotingExtension(e, &req, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Reason != "listrik padam" {
+		t.Fatalf("expected trimmed reason, got %q", req.Reason)
+	}
+	if len(req.TPSIDs) != 2 || req.TPSIDs[0] != 3 || req.TPSIDs[1] != 1 {
+		t.Fatalf("expected deduplicated TPS IDs, got %v", req.TPSIDs)
+	}
+}
@@ -0,0 +1,197 @@
+package election
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidVoterTypeWeight   = errors.New("invalid voter type weight")
+	ErrDuplicateVoterTypeWeight = errors.New("duplicate voter type weight")
+)
+
+// voterTypeOrder is the display order of constituencies in tallies.
+var voterTypeOrder = map[string]int{
+	"STUDENT":  0,
+	"LECTURER": 1,
+	"STAFF":    2,
+}
+
+// UnknownVoterType labels votes whose voter_type could not be resolved
+// (votes cast before weighting was introduced and not backfilled).
+const UnknownVoterType = "UNKNOWN"
+
+// VoterTypeWeight is the weight of one constituency (voter_type) in an election.
+type VoterTypeWeight struct {
+	VoterType string  `json:"voter_type"`
+	Weight    float64 `json:"weight"`
+}
+
+type VoterTypeWeightsDTO struct {
+	ElectionID int64             `json:"election_id"`
+	Weighted   bool              `json:"weighted"`
+	Weights    []VoterTypeWeight `json:"weights"`
+}
+
+// VoterTypeWeightsRequest replaces all weighting rules of an election.
+// An empty list switches the election back to unweighted counting.
+type VoterTypeWeightsRequest struct {
+	Weights []VoterTypeWeight `json:"weights"`
+}
+
+// VoterTypeVoteCount is the raw vote count of one candidate within one constituency.
+// RaceID is 0 for votes outside any race (single-race elections).
+type VoterTypeVoteCount struct {
+	RaceID      int64
+	VoterType   string
+	CandidateID int64
+	Votes       int64
+}
+
+// ConstituencyTally holds the raw counts of one voter_type pool.
+type ConstituencyTally struct {
+	VoterType      string          `json:"voter_type"`
+	Weight         float64         `json:"weight"`
+	TotalVotes     int64           `json:"total_votes"`
+	CandidateVotes map[int64]int64 `json:"candidate_votes"`
+}
+
+// WeightedTally is the per-constituency breakdown and the combined result.
+type WeightedTally struct {
+	Weighted       bool                `json:"weighted"`
+	Constituencies []ConstituencyTally `json:"constituencies"`
+	// CandidatePercentages is each candidate's combined share in percent.
+	CandidatePercentages map[int64]float64 `json:"candidate_percentages"`
+}
+
+// Validate checks voter types, duplicates and that at least one weight is positive.
+func (r VoterTypeWeightsRequest) Validate() error {
+	if len(r.Weights) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(r.Weights))
+	var positive bool
+	for _, w := range r.Weights {
+		if _, ok := voterTypeOrder[w.VoterType]; !ok || w.Weight < 0 {
+			return ErrInvalidVoterTypeWeight
+		}
+		if _, dup := seen[w.VoterType]; dup {
+			return ErrDuplicateVoterTypeWeight
+		}
+		seen[w.VoterType] = struct{}{}
+		if w.Weight > 0 {
+			positive = true
+		}
+	}
+	if !positive {
+		return ErrInvalidVoterTypeWeight
+	}
+	return nil
+}
+
+// ComputeWeightedTally groups raw counts per constituency and combines them.
+//
+// Without weights every ballot counts once and the combined share is the plain
+// vote share. With weights each constituency is counted as a separate pool: a
+// candidate's share within the pool is multiplied by the pool weight and the
+// results are normalised by the total weight of pools that received votes.
+// Voter types without a rule get weight 0 and are reported raw only.
+func ComputeWeightedTally(counts []VoterTypeVoteCount, weights []VoterTypeWeight) *WeightedTally {
+	weighted := len(weights) > 0
+	weightByType := make(map[string]float64, len(weights))
+	for _, w := range weights {
+		weightByType[w.VoterType] = w.Weight
+	}
+
+	pools := make(map[string]*ConstituencyTally)
+	candidateTotals := make(map[int64]int64)
+	var grandTotal int64
+	for _, c := range counts {
+		voterType := c.VoterType
+		if voterType == "" {
+			voterType = UnknownVoterType
+		}
+		pool, ok := pools[voterType]
+		if !ok {
+			weight := 1.0
+			if weighted {
+				weight = weightByType[voterType]
+			}
+			pool = &ConstituencyTally{
+				VoterType:      voterType,
+				Weight:         weight,
+				CandidateVotes: make(map[int64]int64),
+			}
+			pools[voterType] = pool
+		}
+		pool.CandidateVotes[c.CandidateID] += c.Votes
+		pool.TotalVotes += c.Votes
+		candidateTotals[c.CandidateID] += c.Votes
+		grandTotal += c.Votes
+	}
+
+	tally := &WeightedTally{
+		Weighted:             weighted,
+		Constituencies:       make([]ConstituencyTally, 0, len(pools)),
+		CandidatePercentages: make(map[int64]float64, len(candidateTotals)),
+	}
+	for _, pool := range pools {
+		tally.Constituencies = append(tally.Constituencies, *pool)
+	}
+	sort.Slice(tally.Constituencies, func(i, j int) bool {
+		return voterTypeRank(tally.Constituencies[i].VoterType) < voterTypeRank(tally.Constituencies[j].VoterType)
+	})
+
+	if !weighted {
+		for candidateID, votes := range candidateTotals {
+			tally.CandidatePercentages[candidateID] = float64(votes) * 100 / float64(grandTotal)
+		}
+		return tally
+	}
+
+	var totalWeight float64
+	for _, pool := range tally.Constituencies {
+		if pool.TotalVotes > 0 {
+			totalWeight += pool.Weight
+		}
+	}
+	for candidateID := range candidateTotals {
+		tally.CandidatePercentages[candidateID] = 0
+	}
+	if totalWeight == 0 {
+		return tally
+	}
+	for _, pool := range tally.Constituencies {
+		if pool.TotalVotes == 0 || pool.Weight == 0 {
+			continue
+		}
+		for candidateID, votes := range pool.CandidateVotes {
+			share := float64(votes) / float64(pool.TotalVotes)
+			tally.CandidatePercentages[candidateID] += share * pool.Weight * 100 / totalWeight
+		}
+	}
+	return tally
+}
+
+// ComputeWeightedTallyByRace tallies every race separately, since shares are
+// only comparable between candidates of the same race. The result is keyed by
+// RaceID.
+func ComputeWeightedTallyByRace(counts []VoterTypeVoteCount, weights []VoterTypeWeight) map[int64]*WeightedTally {
+	byRace := make(map[int64][]VoterTypeVoteCount)
+	for _, c := range counts {
+		byRace[c.RaceID] = append(byRace[c.RaceID], c)
+	}
+	tallies := make(map[int64]*WeightedTally, len(byRace))
+	for raceID, raceCounts := range byRace {
+		tallies[raceID] = ComputeWeightedTally(raceCounts, weights)
+	}
+	return tallies
+}
+
+func voterTypeRank(voterType string) int {
+	if rank, ok := voterTypeOrder[voterType]; ok {
+		return rank
+	}
+	return len(voterTypeOrder)
+}
@@ -0,0 +1,79 @@
+package election
+
+import (
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestComputeWeightedTally_UnweightedIsPlainShare(t *testing.T) {
+	counts := []VoterTypeVoteCount{
+		{VoterType: "STUDENT", CandidateID: 1, Votes: 30},
+		{VoterType: "STUDENT", CandidateID: 2, Votes: 10},
+		{VoterType: "LECTURER", CandidateID: 2, Votes: 10},
+	}
+
+	tally := ComputeWeightedTally(counts, nil)
+
+	if tally.Weighted {
+		t.Fatalf("expected unweighted tally")
+	}
+	if !approxEqual(tally.CandidatePercentages[1], 60) || !approxEqual(tally.CandidatePercentages[2], 40) {
+		t.Fatalf("unexpected percentages: %v", tally.CandidatePercentages)
+	}
+	if len(tally.Constituencies) != 2 || tally.Constituencies[0].VoterType != "STUDENT" {
+		t.Fatalf("expected STUDENT then LECTURER constituencies, got %+v", tally.Constituencies)
+	}
+}
+
+func TestComputeWeightedTally_CombinesPoolsByWeight(t *testing.T) {
+	counts := []VoterTypeVoteCount{
+		// Students: candidate 1 wins 80/20.
+		{VoterType: "STUDENT", CandidateID: 1, Votes: 800},
+		{VoterType: "STUDENT", CandidateID: 2, Votes: 200},
+		// Lecturers: candidate 2 wins 10/30.
+		{VoterType: "LECTURER", CandidateID: 1, Votes: 10},
+		{VoterType: "LECTURER", CandidateID: 2, Votes: 30},
+		// Staff have no rule and must not affect the weighted result.
+		{VoterType: "STAFF", CandidateID: 1, Votes: 5},
+	}
+	weights := []VoterTypeWeight{
+		{VoterType: "STUDENT", Weight: 1},
+		{VoterType: "LECTURER", Weight: 3},
+	}
+
+	tally := ComputeWeightedTally(counts, weights)
+
+	// (0.8*1 + 0.25*3) / 4 = 38.75%, (0.2*1 + 0.75*3) / 4 = 61.25%
+	if !approxEqual(tally.CandidatePercentages[1], 38.75) || !approxEqual(tally.CandidatePercentages[2], 61.25) {
+		t.Fatalf("unexpected weighted percentages: %v", tally.CandidatePercentages)
+	}
+	staff := tally.Constituencies[2]
+	if staff.VoterType != "STAFF" || staff.Weight != 0 || staff.CandidateVotes[1] != 5 {
+		t.Fatalf("expected raw STAFF pool with weight 0, got %+v", staff)
+	}
+}
+
+func TestVoterTypeWeightsRequest_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		weights []VoterTypeWeight
+		want    error
+	}{
+		{"empty clears weighting", nil, nil},
+		{"valid", []VoterTypeWeight{{"STUDENT", 0.5}, {"LECTURER", 0.3}, {"STAFF", 0.2}}, nil},
+		{"unknown type", []VoterTypeWeight{{"ALUMNI", 1}}, ErrInvalidVoterTypeWeight},
+		{"negative", []VoterTypeWeight{{"STUDENT", -1}}, ErrInvalidVoterTypeWeight},
+		{"all zero", []VoterTypeWeight{{"STUDENT", 0}}, ErrInvalidVoterTypeWeight},
+		{"duplicate", []VoterTypeWeight{{"STUDENT", 1}, {"STUDENT", 2}}, ErrDuplicateVoterTypeWeight},
+	}
+
+	for _, tc := range cases {
+		if err := (VoterTypeWeightsRequest{Weights: tc.weights}).Validate(); err != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -69,6 +69,22 @@ func AuthStudentOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handle
 	}
 }
 
+// AuthVoterOnly ensures only voter roles (STUDENT, LECTURER, STAFF) can access.
+// Whether the voter may vote in a given election is decided by the voting service
+// from election_voters enrollment.
+func AuthVoterOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return JWTAuth(jwtManager)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := ctxkeys.GetUserRole(r.Context())
+			if !ok || (role != string(constants.RoleStudent) && role != string(constants.RoleLecturer) && role != string(constants.RoleStaff)) {
+				response.Forbidden(w, "FORBIDDEN", "Akses ditolak. Hanya untuk pemilih.")
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
 // AuthAdminOnly ensures only ADMIN or SUPER_ADMIN role can access
 func AuthAdminOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -89,6 +105,20 @@ func AuthAdminOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handler
 	}
 }
 
+// AuthSuperAdminOnly ensures only SUPER_ADMIN role can access
+func AuthSuperAdminOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return JWTAuth(jwtManager)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := ctxkeys.GetUserRole(r.Context())
+			if !ok || role != string(constants.RoleSuperAdmin) {
+				response.Forbidden(w, "FORBIDDEN", "Akses ditolak. Hanya untuk super admin.")
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
 // AuthTPSOperatorOnly ensures only TPS_OPERATOR role can access
 func AuthTPSOperatorOnly(jwtManager *auth.JWTManager) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"pemira-api/internal/shared/ctxkeys"
+)
+
+// ClientInfo stores the client IP address and user agent in the request context
+// so that services (e.g. audit logging) can read them without access to *http.Request.
+// Mount it after chi's RealIP middleware so RemoteAddr reflects the real client.
+func ClientInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		ctx := context.WithValue(r.Context(), ctxkeys.IPAddressKey, ip)
+		ctx = context.WithValue(ctx, ctxkeys.UserAgentKey, r.UserAgent())
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,192 @@
+// Package idempotency lets clients retry unsafe requests with an
+// Idempotency-Key header: the first response under a key is stored and
+// replayed for retries instead of running the request again.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/http/response"
+)
+
+const (
+	// HeaderKey is the request header carrying the client's key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses replayed from the store.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	maxBodyBytes = 1 << 20
+)
+
+// Middleware replays the stored response of a request for the same user, key
+// and request (method, path and body). A key reused for a different request,
+// or retried while the first request still runs, gets 409. Server errors
+// (5xx) are not stored, so the request can be retried under the same key.
+// Requests without the header, or without an authenticated user, pass
+// through unchanged; mount it after the auth middleware.
+type Middleware struct {
+	store   Store
+	secret  []byte
+	receipt ReceiptFunc
+}
+
+// ReceiptFunc returns what a retry of a completed request gets instead of its
+// stored response. r carries the retry's body.
+type ReceiptFunc func(r *http.Request) (any, error)
+
+func NewMiddleware(store Store) *Middleware {
+	return &Middleware{store: store}
+}
+
+// NewReceiptMiddleware is a Middleware for requests whose body and response
+// must not be kept, such as vote casts: the request is identified by an HMAC
+// of its method, path and body under secret, so a stored key cannot be
+// matched against guessed ballots, and nothing else of the body or response
+// is stored. A retry of a successful request gets receipt instead of the
+// first response. Failed requests release the key and run again when retried.
+func NewReceiptMiddleware(store Store, secret []byte, receipt ReceiptFunc) *Middleware {
+	return &Middleware{store: store, secret: secret, receipt: receipt}
+}
+
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(HeaderKey))
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authUser, ok := auth.FromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			response.BadRequest(w, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key maksimal 255 karakter.")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+		if err != nil || len(body) > maxBodyBytes {
+			response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(r, body)
+		if m.receipt != nil {
+			hash = keyedRequestHash(m.secret, r, body)
+		}
+		rec, err := m.store.Claim(r.Context(), authUser.ID, key, hash)
+		if err != nil {
+			slog.Error("failed to claim idempotency key", "error", err)
+			response.InternalServerError(w, "INTERNAL_ERROR", "Terjadi kesalahan pada sistem.")
+			return
+		}
+		if rec != nil {
+			if m.receipt != nil && rec.RequestHash == hash && rec.Completed {
+				m.replayReceipt(w, r, rec)
+				return
+			}
+			replay(w, rec, hash)
+			return
+		}
+
+		// Store the response once the handler is done; the request context
+		// may already be cancelled by then.
+		ctx := context.WithoutCancel(r.Context())
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		var buf bytes.Buffer
+		if m.receipt == nil {
+			ww.Tee(&buf)
+		}
+
+		completed := false
+		defer func() {
+			if !completed {
+				if err := m.store.Release(ctx, authUser.ID, key); err != nil {
+					slog.Error("failed to release idempotency key", "error", err)
+				}
+			}
+		}()
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		contentType, respBody := ww.Header().Get("Content-Type"), buf.Bytes()
+		if m.receipt != nil {
+			// Only successful requests are kept, and only by their status
+			if status >= http.StatusMultipleChoices {
+				return
+			}
+			contentType, respBody = "", nil
+		}
+		if err := m.store.Complete(ctx, authUser.ID, key, status, contentType, respBody); err != nil {
+			slog.Error("failed to store idempotent response", "error", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// replay answers a request whose key is already held.
+func replay(w http.ResponseWriter, rec *Record, hash string) {
+	switch {
+	case rec.RequestHash != hash:
+		response.Conflict(w, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key sudah dipakai untuk permintaan dengan isi berbeda.")
+	case !rec.Completed:
+		response.Conflict(w, "IDEMPOTENCY_KEY_IN_PROGRESS", "Permintaan dengan Idempotency-Key ini masih diproses, coba lagi sebentar.")
+	default:
+		if rec.ContentType != "" {
+			w.Header().Set("Content-Type", rec.ContentType)
+		}
+		w.Header().Set(HeaderReplayed, "true")
+		w.WriteHeader(rec.StatusCode)
+		w.Write(rec.Body)
+	}
+}
+
+// replayReceipt answers a retry of a completed request with its receipt.
+func (m *Middleware) replayReceipt(w http.ResponseWriter, r *http.Request, rec *Record) {
+	receipt, err := m.receipt(r)
+	if err != nil {
+		slog.Error("failed to load idempotent receipt", "error", err)
+		response.InternalServerError(w, "INTERNAL_ERROR", "Terjadi kesalahan pada sistem.")
+		return
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	response.Success(w, rec.StatusCode, receipt)
+}
+
+// requestHash identifies a request by method, path and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// keyedRequestHash is requestHash under an HMAC key.
+func keyedRequestHash(secret []byte, r *http.Request, body []byte) string {
+	h := hmac.New(sha256.New, secret)
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
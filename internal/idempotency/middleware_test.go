@@ -0,0 +1,159 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"pemira-api/internal/shared/ctxkeys"
+)
+
+type memoryStore struct {
+	mu   sync.Mutex
+	recs map[string]*Record
+}
+
+func (s *memoryStore) Claim(ctx context.Context, userID int64, key, requestHash string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.recs[key]; ok {
+		copied := *rec
+		return &copied, nil
+	}
+	s.recs[key] = &Record{RequestHash: requestHash}
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, userID int64, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.recs[key]
+	rec.Completed, rec.StatusCode, rec.ContentType, rec.Body = true, statusCode, contentType, body
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, userID int64, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recs, key)
+	return nil
+}
+
+func TestMiddlewareReplaysResponse(t *testing.T) {
+	calls := 0
+	status := http.StatusOK
+	handler := NewMiddleware(&memoryStore{recs: map[string]*Record{}}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/voting/online/cast", strings.NewReader(body))
+		ctx := context.WithValue(req.Context(), ctxkeys.UserIDKey, int64(7))
+		ctx = context.WithValue(ctx, ctxkeys.UserRoleKey, "STUDENT")
+		if key != "" {
+			req.Header.Set(HeaderKey, key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req.WithContext(ctx))
+		return rec
+	}
+
+	first := do("k1", `{"candidate_id":1}`)
+	retry := do("k1", `{"candidate_id":1}`)
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get(HeaderReplayed) != "true" {
+		t.Errorf("retry got %d %q, want replay of %d %q", retry.Code, retry.Body, first.Code, first.Body)
+	}
+
+	if got := do("k1", `{"candidate_id":2}`); got.Code != http.StatusConflict || !strings.Contains(got.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("reused key with another body got %d %s", got.Code, got.Body)
+	}
+
+	// Server errors are not stored
+	status = http.StatusInternalServerError
+	do("k2", `{}`)
+	status = http.StatusOK
+	if got := do("k2", `{}`); got.Code != http.StatusOK || calls != 3 {
+		t.Errorf("retry after 5xx got %d after %d calls, want a fresh run", got.Code, calls)
+	}
+
+	do("", `{}`)
+	do("", `{}`)
+	if calls != 5 {
+		t.Errorf("requests without a key ran %d times in total, want 5", calls)
+	}
+}
+
+func TestReceiptMiddlewareKeepsNoBallot(t *testing.T) {
+	store := &memoryStore{recs: map[string]*Record{}}
+	calls := 0
+	status := http.StatusOK
+	receipt := map[string]string{"token_hash": "abc123", "voted_at": "2025-06-13T09:00:00Z"}
+	secret := []byte("test-secret")
+	handler := NewReceiptMiddleware(store, secret, func(r *http.Request) (any, error) {
+		return receipt, nil
+	}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/voting/tps/ballots/cast-from-qr", strings.NewReader(body))
+		ctx := context.WithValue(req.Context(), ctxkeys.UserIDKey, int64(7))
+		ctx = context.WithValue(ctx, ctxkeys.UserRoleKey, "STUDENT")
+		req.Header.Set(HeaderKey, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req.WithContext(ctx))
+		return rec
+	}
+
+	ballot := `{"election_id":1,"candidate_id":3,"ballot_qr_payload":"PEMIRA-UNIWA|E:1|C:3|V:1|S:x"}`
+	do("k1", ballot)
+	for key, rec := range store.recs {
+		stored := fmt.Sprintf("%+v", *rec)
+		if len(rec.Body) > 0 || rec.ContentType != "" || strings.Contains(stored, "candidate") || strings.Contains(stored, "C:3") {
+			t.Errorf("store kept ballot data under %q: %s", key, stored)
+		}
+		// The plain hash of a guessed ballot must not match
+		if rec.RequestHash == requestHash(httptest.NewRequest(http.MethodPost, "/api/v1/voting/tps/ballots/cast-from-qr", nil), []byte(ballot)) {
+			t.Errorf("request hash under %q is an unkeyed hash of the ballot", key)
+		}
+	}
+
+	// Retries of the same ballot get the receipt
+	retry := do("k1", ballot)
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if retry.Code != http.StatusOK || retry.Header().Get(HeaderReplayed) != "true" ||
+		!strings.Contains(retry.Body.String(), "abc123") || strings.Contains(retry.Body.String(), "candidate") {
+		t.Errorf("retry got %d %s, want the receipt", retry.Code, retry.Body)
+	}
+
+	// Same key, different candidate
+	other := do("k1", `{"election_id":1,"candidate_id":4,"ballot_qr_payload":"PEMIRA-UNIWA|E:1|C:4|V:1|S:y"}`)
+	if calls != 1 || other.Code != http.StatusConflict || !strings.Contains(other.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("different ballot got %d %s, want 409 IDEMPOTENCY_KEY_REUSED", other.Code, other.Body)
+	}
+
+	// Rejected casts are not kept either
+	status = http.StatusUnprocessableEntity
+	do("k2", ballot)
+	if _, ok := store.recs["k2"]; ok {
+		t.Error("rejected cast kept its key")
+	}
+}
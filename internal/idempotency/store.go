@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// staleAfter is how long a claimed key may stay without a response before it
+// is assumed abandoned (the instance handling it died) and can be claimed
+// again. It is longer than the server's request timeout.
+const staleAfter = 2 * time.Minute
+
+// Record is the request held under a key and, once completed, its response.
+type Record struct {
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Store keeps idempotency keys per user.
+type Store interface {
+	// Claim reserves key for a request. It returns nil when the caller holds
+	// the key and should run the request, or the record already held.
+	Claim(ctx context.Context, userID int64, key, requestHash string) (*Record, error)
+	// Complete stores the response of a claimed key.
+	Complete(ctx context.Context, userID int64, key string, statusCode int, contentType string, body []byte) error
+	// Release gives up a claimed key so the request can be retried.
+	Release(ctx context.Context, userID int64, key string) error
+}
+
+// PgStore keeps idempotency keys in idempotency_keys for TTL.
+type PgStore struct {
+	db  *pgxpool.Pool
+	ttl time.Duration
+}
+
+func NewPgStore(db *pgxpool.Pool, ttl time.Duration) *PgStore {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &PgStore{db: db, ttl: ttl}
+}
+
+func (s *PgStore) Claim(ctx context.Context, userID int64, key, requestHash string) (*Record, error) {
+	// Expired keys and abandoned claims are taken over
+	var claimed bool
+	err := s.db.QueryRow(ctx, `
+INSERT INTO idempotency_keys (user_id, idem_key, request_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, idem_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    content_type = NULL,
+    response_body = NULL,
+    created_at = NOW(),
+    completed_at = NULL
+WHERE idempotency_keys.created_at < NOW() - make_interval(secs => $4)
+   OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.created_at < NOW() - make_interval(secs => $5))
+RETURNING TRUE
+`, userID, key, requestHash, s.ttl.Seconds(), staleAfter.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	var (
+		rec         Record
+		statusCode  *int
+		contentType *string
+	)
+	err = s.db.QueryRow(ctx, `
+SELECT request_hash, completed_at IS NOT NULL, status_code, content_type, response_body
+FROM idempotency_keys
+WHERE user_id = $1 AND idem_key = $2
+`, userID, key).Scan(&rec.RequestHash, &rec.Completed, &statusCode, &contentType, &rec.Body)
+	if err != nil {
+		return nil, fmt.Errorf("load idempotency key: %w", err)
+	}
+	if statusCode != nil {
+		rec.StatusCode = *statusCode
+	}
+	if contentType != nil {
+		rec.ContentType = *contentType
+	}
+	return &rec, nil
+}
+
+func (s *PgStore) Complete(ctx context.Context, userID int64, key string, statusCode int, contentType string, body []byte) error {
+	_, err := s.db.Exec(ctx, `
+UPDATE idempotency_keys
+SET status_code = $3, content_type = $4, response_body = $5, completed_at = NOW()
+WHERE user_id = $1 AND idem_key = $2
+`, userID, key, statusCode, contentType, body)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *PgStore) Release(ctx context.Context, userID int64, key string) error {
+	_, err := s.db.Exec(ctx, `
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND idem_key = $2 AND completed_at IS NULL
+`, userID, key)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Run deletes expired keys every hour until ctx is done.
+func (s *PgStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := s.db.Exec(ctx, `
+DELETE FROM idempotency_keys
+WHERE created_at < NOW() - make_interval(secs => $1)
+`, s.ttl.Seconds())
+			if err != nil {
+				slog.Error("failed to prune idempotency keys", "error", err)
+			}
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package monitoring
 
-import "time"
+import (
+	"time"
+
+	"pemira-api/internal/election"
+)
 
 type VoteStats struct {
 	ElectionID       int64     `json:"election_id"`
@@ -35,5 +39,72 @@ type LiveCountSnapshot struct {
 	TotalVotes     int64              `json:"total_votes"`
 	Participation  ParticipationStats `json:"participation"`
 	CandidateVotes map[int64]int64    `json:"candidate_votes"`
-	TPSStats       []TPSStats         `json:"tps_stats"`
+	// AbstainVotes counts blank ballots. They are excluded from TotalVotes and from
+	// every candidate share.
+	AbstainVotes int64 `json:"abstain_votes"`
+	// WeightedTally carries raw counts per voter_type and the weighted combined share.
+	// Multi-race elections report one tally per race (keyed by race ID) in
+	// RaceTallies instead, as shares are only comparable within a race.
+	WeightedTally *election.WeightedTally           `json:"weighted_tally"`
+	RaceTallies   map[int64]*election.WeightedTally `json:"race_tallies,omitempty"`
+	TPSStats      []TPSStats                        `json:"tps_stats"`
+	// ResultsSealed is set when per-candidate numbers are withheld because the
+	// election's results are sealed until voting closes.
+	ResultsSealed bool `json:"results_sealed"`
+}
+
+// ResultCandidate is a candidate row used to build the final result.
+type ResultCandidate struct {
+	CandidateID int64
+	RaceID      int64
+	Number      int
+	Name        string
+}
+
+// ResultRace is a race used to group the final result.
+type ResultRace struct {
+	RaceID int64
+	Code   string
+	Name   string
+}
+
+type CandidateResult struct {
+	CandidateID        int64            `json:"candidate_id"`
+	RaceID             *int64           `json:"race_id,omitempty"`
+	Number             int              `json:"number"`
+	Name               string           `json:"name"`
+	Rank               int              `json:"rank"`
+	TotalVotes         int64            `json:"total_votes"`
+	Percentage         float64          `json:"percentage"`
+	WeightedPercentage float64          `json:"weighted_percentage"`
+	VotesByVoterType   map[string]int64 `json:"votes_by_voter_type"`
+}
+
+// ElectionResult is the final result: raw counts per constituency and the
+// combined (weighted when rules exist) ranking.
+type ElectionResult struct {
+	ElectionID     int64                        `json:"election_id"`
+	ComputedAt     time.Time                    `json:"computed_at"`
+	TotalVotes     int64                        `json:"total_votes"`
+	AbstainVotes   int64                        `json:"abstain_votes"`
+	Weighted       bool                         `json:"weighted"`
+	Constituencies []election.ConstituencyTally `json:"constituencies"`
+	Candidates     []CandidateResult            `json:"candidates"`
+	// WinnerCandidateIDs has more than one entry when the top result is tied.
+	// In multi-race elections it holds the winners of every race.
+	WinnerCandidateIDs []int64 `json:"winner_candidate_ids"`
+	// Races is the per-race breakdown of a multi-race election.
+	Races []RaceResult `json:"races,omitempty"`
+}
+
+// RaceResult is the ranking of one race; winners are decided per race.
+type RaceResult struct {
+	RaceID             int64                        `json:"race_id"`
+	Code               string                       `json:"code"`
+	Name               string                       `json:"name"`
+	TotalVotes         int64                        `json:"total_votes"`
+	AbstainVotes       int64                        `json:"abstain_votes"`
+	Constituencies     []election.ConstituencyTally `json:"constituencies"`
+	Candidates         []CandidateResult            `json:"candidates"`
+	WinnerCandidateIDs []int64                      `json:"winner_candidate_ids"`
 }
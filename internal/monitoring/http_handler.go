@@ -1,11 +1,13 @@
 package monitoring
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	
+	"pemira-api/internal/election"
 	"pemira-api/internal/http/response"
 )
 
@@ -21,6 +23,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/admin/monitoring/summary", h.GetSummary)
 	r.Get("/admin/monitoring/live-count/{electionID}", h.GetLiveCount)
 	r.Get("/admin/monitoring/participation/{electionID}", h.GetParticipation)
+	r.Get("/admin/monitoring/results/{electionID}", h.GetResults)
 }
 
 func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
@@ -66,3 +69,23 @@ func (h *Handler) GetParticipation(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusOK, participation)
 }
+
+func (h *Handler) GetResults(w http.ResponseWriter, r *http.Request) {
+	electionID, err := strconv.ParseInt(chi.URLParam(r, "electionID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, "INVALID_REQUEST", "Invalid election ID")
+		return
+	}
+
+	result, err := h.service.GetElectionResult(r.Context(), electionID)
+	if err != nil {
+		if errors.Is(err, election.ErrResultsSealed) {
+			response.Forbidden(w, "RESULTS_SEALED", "Candidate results are sealed until voting closes")
+			return
+		}
+		response.InternalServerError(w, "INTERNAL_ERROR", "Failed to compute results")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
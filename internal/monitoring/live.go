@@ -0,0 +1,274 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pemira-api/internal/election"
+)
+
+const (
+	// EventLiveCountSnapshot carries a LiveCountSnapshot, sent on connect.
+	EventLiveCountSnapshot = "livecount.snapshot"
+	// EventLiveCountDelta carries a LiveCountDelta.
+	EventLiveCountDelta = "livecount.delta"
+
+	// DefaultLiveCountInterval is how often pending changes are published.
+	DefaultLiveCountInterval = time.Second
+)
+
+// LiveCountChannel is the real-time channel of an election's live count.
+func LiveCountChannel(electionID int64) string {
+	return fmt.Sprintf("election:%d:live", electionID)
+}
+
+// parseLiveCountChannel returns the election of a live count channel.
+func parseLiveCountChannel(channel string) (int64, bool) {
+	id, ok := strings.CutPrefix(channel, "election:")
+	if !ok {
+		return 0, false
+	}
+	id, ok = strings.CutSuffix(id, ":live")
+	if !ok {
+		return 0, false
+	}
+	electionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || electionID <= 0 {
+		return 0, false
+	}
+	return electionID, true
+}
+
+// LiveCountDelta holds the parts of the live count that changed since the
+// previous delta. Values are current totals rather than increments, so a
+// delta already covered by the snapshot a client started from is harmless to
+// apply again. Nil or empty fields did not change.
+type LiveCountDelta struct {
+	ElectionID    int64               `json:"election_id"`
+	Timestamp     time.Time           `json:"timestamp"`
+	TotalVotes    *int64              `json:"total_votes,omitempty"`
+	AbstainVotes  *int64              `json:"abstain_votes,omitempty"`
+	Participation *ParticipationStats `json:"participation,omitempty"`
+	// CandidateVotes holds only the candidates whose count changed.
+	CandidateVotes map[int64]int64                   `json:"candidate_votes,omitempty"`
+	WeightedTally  *election.WeightedTally           `json:"weighted_tally,omitempty"`
+	RaceTallies    map[int64]*election.WeightedTally `json:"race_tallies,omitempty"`
+	// TPSStats holds only the TPS with new votes or check-ins.
+	TPSStats []TPSStats `json:"tps_stats,omitempty"`
+	// ResultsSealed is set while per-candidate numbers are withheld; deltas are
+	// broadcast to every subscriber, so they are sealed for all roles.
+	ResultsSealed bool `json:"results_sealed"`
+}
+
+// liveChanges records what changed in an election since the last publish.
+type liveChanges struct {
+	votes bool
+	tps   map[int64]struct{}
+}
+
+// LiveCount streams the live count of elections: it is told about committed
+// votes and check-in changes, and publishes one LiveCountDelta per election
+// and interval on LiveCountChannel, so bursts of votes cost one set of
+// queries.
+type LiveCount struct {
+	svc      *Service
+	notifier election.Notifier
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[int64]*liveChanges
+	// candidateVotes is the last published count per candidate of each
+	// election, used to send only the counts that changed.
+	candidateVotes map[int64]map[int64]int64
+}
+
+func NewLiveCount(svc *Service, notifier election.Notifier) *LiveCount {
+	return &LiveCount{
+		svc:            svc,
+		notifier:       notifier,
+		interval:       DefaultLiveCountInterval,
+		pending:        make(map[int64]*liveChanges),
+		candidateVotes: make(map[int64]map[int64]int64),
+	}
+}
+
+// SetInterval sets how often pending changes are published.
+func (l *LiveCount) SetInterval(d time.Duration) {
+	if d > 0 {
+		l.interval = d
+	}
+}
+
+// VoteCast records a committed vote; tpsID is nil for online votes.
+func (l *LiveCount) VoteCast(electionID int64, tpsID *int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.changes(electionID)
+	c.votes = true
+	if tpsID != nil {
+		c.tps[*tpsID] = struct{}{}
+	}
+}
+
+// CheckinUpdated records a new or changed check-in at a TPS.
+func (l *LiveCount) CheckinUpdated(electionID, tpsID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.changes(electionID).tps[tpsID] = struct{}{}
+}
+
+func (l *LiveCount) changes(electionID int64) *liveChanges {
+	c, ok := l.pending[electionID]
+	if !ok {
+		c = &liveChanges{tps: make(map[int64]struct{})}
+		l.pending[electionID] = c
+	}
+	return c
+}
+
+// Run publishes pending changes every interval until ctx is done.
+func (l *LiveCount) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.flush(ctx)
+		}
+	}
+}
+
+func (l *LiveCount) flush(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[int64]*liveChanges)
+	l.mu.Unlock()
+
+	for electionID, changes := range pending {
+		delta, err := l.delta(ctx, electionID, changes)
+		if err != nil {
+			slog.Error("failed to build live count delta", "election_id", electionID, "error", err)
+			continue
+		}
+		l.notifier.Publish(LiveCountChannel(electionID), EventLiveCountDelta, delta)
+	}
+}
+
+func (l *LiveCount) delta(ctx context.Context, electionID int64, changes *liveChanges) (*LiveCountDelta, error) {
+	// No caller role here: the delta goes to every subscriber.
+	sealed, err := election.SealedFor(ctx, l.svc.seal, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &LiveCountDelta{ElectionID: electionID, Timestamp: time.Now(), ResultsSealed: sealed}
+
+	if changes.votes {
+		voteStats, err := l.svc.repo.GetVoteStats(ctx, electionID)
+		if err != nil {
+			return nil, err
+		}
+		participation, err := l.svc.repo.GetParticipationStats(ctx, electionID)
+		if err != nil {
+			return nil, err
+		}
+		abstain, err := l.svc.repo.GetAbstainCounts(ctx, electionID)
+		if err != nil {
+			return nil, err
+		}
+
+		var totalVotes int64
+		current := make(map[int64]int64, len(voteStats))
+		for _, stat := range voteStats {
+			current[stat.CandidateID] = stat.TotalVotes
+			totalVotes += stat.TotalVotes
+		}
+		abstainVotes := sumCounts(abstain)
+		delta.TotalVotes = &totalVotes
+		delta.AbstainVotes = &abstainVotes
+		delta.Participation = participation
+
+		if sealed {
+			l.forgetCandidateVotes(electionID)
+		} else {
+			delta.CandidateVotes = l.changedCandidateVotes(electionID, current)
+
+			counts, weights, err := l.svc.voteCounts(ctx, electionID)
+			if err != nil {
+				return nil, err
+			}
+			tallies := election.ComputeWeightedTallyByRace(counts, weights)
+			if _, single := tallies[0]; len(tallies) > 1 || (len(tallies) == 1 && !single) {
+				delta.RaceTallies = tallies
+			} else {
+				delta.WeightedTally = tallyOrEmpty(tallies, 0, weights)
+			}
+		}
+	}
+
+	if len(changes.tps) > 0 {
+		tpsStats, err := l.svc.repo.GetTPSStats(ctx, electionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, stat := range tpsStats {
+			if stat == nil {
+				continue
+			}
+			if _, ok := changes.tps[stat.TPSID]; ok {
+				delta.TPSStats = append(delta.TPSStats, *stat)
+			}
+		}
+	}
+	return delta, nil
+}
+
+// changedCandidateVotes returns the counts in current that differ from the
+// last ones published for the election and remembers current.
+func (l *LiveCount) changedCandidateVotes(electionID int64, current map[int64]int64) map[int64]int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.candidateVotes[electionID]
+	changed := make(map[int64]int64)
+	for candidateID, votes := range current {
+		if old, ok := previous[candidateID]; !ok || old != votes {
+			changed[candidateID] = votes
+		}
+	}
+	l.candidateVotes[electionID] = current
+	return changed
+}
+
+// forgetCandidateVotes drops the published counts of a sealed election, so
+// the first delta after unsealing carries every candidate.
+func (l *LiveCount) forgetCandidateVotes(electionID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.candidateVotes, electionID)
+}
+
+// Snapshot returns the live count snapshot of a live count channel for the
+// caller in ctx (a ws.SnapshotFunc). Other channels have no snapshot.
+func (l *LiveCount) Snapshot(ctx context.Context, channel string) (string, any, error) {
+	electionID, ok := parseLiveCountChannel(channel)
+	if !ok {
+		return "", nil, nil
+	}
+	snapshot, err := l.svc.GetLiveCountSnapshot(ctx, electionID)
+	if err != nil {
+		return "", nil, err
+	}
+	return EventLiveCountSnapshot, snapshot, nil
+}
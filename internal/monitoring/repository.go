@@ -1,10 +1,19 @@
 package monitoring
 
-import "context"
+import (
+	"context"
+
+	"pemira-api/internal/election"
+)
 
 type Repository interface {
 	GetVoteStats(ctx context.Context, electionID int64) ([]*VoteStats, error)
 	GetParticipationStats(ctx context.Context, electionID int64) (*ParticipationStats, error)
 	GetTPSStats(ctx context.Context, electionID int64) ([]*TPSStats, error)
 	GetLiveCount(ctx context.Context, electionID int64) (map[int64]int64, error)
+	GetVoteCountsByVoterType(ctx context.Context, electionID int64) ([]election.VoterTypeVoteCount, error)
+	GetVoterTypeWeights(ctx context.Context, electionID int64) ([]election.VoterTypeWeight, error)
+	GetResultCandidates(ctx context.Context, electionID int64) ([]ResultCandidate, error)
+	GetResultRaces(ctx context.Context, electionID int64) ([]ResultRace, error)
+	GetAbstainCounts(ctx context.Context, electionID int64) (map[int64]int64, error)
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"pemira-api/internal/election"
 )
 
 type PgRepository struct {
@@ -26,7 +28,7 @@ SELECT
     SUM(CASE WHEN channel = 'TPS' THEN 1 ELSE 0 END) AS total_votes_tps,
     COALESCE(MAX(cast_at), NOW()) AS updated_at
 FROM votes
-WHERE election_id = $1
+WHERE election_id = $1 AND NOT is_abstain
 GROUP BY election_id, candidate_id
 ORDER BY candidate_id
 `
@@ -108,7 +110,7 @@ FROM tps t
 LEFT JOIN (
     SELECT
         tps_id,
-        COUNT(*) FILTER (WHERE channel = 'TPS') AS total_votes,
+        COUNT(DISTINCT token_hash) FILTER (WHERE channel = 'TPS') AS total_votes,
         MAX(cast_at) FILTER (WHERE channel = 'TPS') AS last_vote_at
     FROM votes
     WHERE election_id = $1
@@ -159,7 +161,7 @@ func (r *PgRepository) GetLiveCount(ctx context.Context, electionID int64) (map[
 	const q = `
 SELECT candidate_id, COUNT(*) AS total_votes
 FROM votes
-WHERE election_id = $1
+WHERE election_id = $1 AND NOT is_abstain
 GROUP BY candidate_id
 `
 	rows, err := r.db.Query(ctx, q, electionID)
@@ -178,3 +180,131 @@ GROUP BY candidate_id
 	}
 	return result, rows.Err()
 }
+
+// GetVoteCountsByVoterType returns raw votes per candidate within each voter_type pool.
+// Abstain votes are excluded; see GetAbstainCounts.
+func (r *PgRepository) GetVoteCountsByVoterType(ctx context.Context, electionID int64) ([]election.VoterTypeVoteCount, error) {
+	const q = `
+SELECT COALESCE(race_id, 0), COALESCE(voter_type, ''), candidate_id, COUNT(*) AS total_votes
+FROM votes
+WHERE election_id = $1 AND NOT is_abstain
+GROUP BY race_id, voter_type, candidate_id
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []election.VoterTypeVoteCount
+	for rows.Next() {
+		var c election.VoterTypeVoteCount
+		if err := rows.Scan(&c.RaceID, &c.VoterType, &c.CandidateID, &c.Votes); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetVoterTypeWeights returns the election's weighting rules (empty when unweighted).
+func (r *PgRepository) GetVoterTypeWeights(ctx context.Context, electionID int64) ([]election.VoterTypeWeight, error) {
+	const q = `
+SELECT voter_type, weight::float8
+FROM election_voter_type_weights
+WHERE election_id = $1
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weights []election.VoterTypeWeight
+	for rows.Next() {
+		var w election.VoterTypeWeight
+		if err := rows.Scan(&w.VoterType, &w.Weight); err != nil {
+			return nil, err
+		}
+		weights = append(weights, w)
+	}
+	return weights, rows.Err()
+}
+
+// GetResultCandidates returns published candidates plus any candidate that received votes.
+func (r *PgRepository) GetResultCandidates(ctx context.Context, electionID int64) ([]ResultCandidate, error) {
+	const q = `
+SELECT c.id, COALESCE(c.race_id, 0), c.number, c.name
+FROM candidates c
+WHERE c.election_id = $1
+  AND (c.status = 'APPROVED' OR EXISTS (
+        SELECT 1 FROM votes v WHERE v.election_id = $1 AND v.candidate_id = c.id
+  ))
+ORDER BY c.number
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []ResultCandidate
+	for rows.Next() {
+		var c ResultCandidate
+		if err := rows.Scan(&c.CandidateID, &c.RaceID, &c.Number, &c.Name); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// GetResultRaces returns the races of a multi-race election in display order.
+func (r *PgRepository) GetResultRaces(ctx context.Context, electionID int64) ([]ResultRace, error) {
+	const q = `
+SELECT id, code, name
+FROM election_races
+WHERE election_id = $1
+ORDER BY display_order, id
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var races []ResultRace
+	for rows.Next() {
+		var race ResultRace
+		if err := rows.Scan(&race.RaceID, &race.Code, &race.Name); err != nil {
+			return nil, err
+		}
+		races = append(races, race)
+	}
+	return races, rows.Err()
+}
+
+// GetAbstainCounts returns abstain votes per race (race 0 for elections without races).
+func (r *PgRepository) GetAbstainCounts(ctx context.Context, electionID int64) (map[int64]int64, error) {
+	const q = `
+SELECT COALESCE(race_id, 0), COUNT(*) AS total_votes
+FROM votes
+WHERE election_id = $1 AND is_abstain
+GROUP BY race_id
+`
+	rows, err := r.db.Query(ctx, q, electionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]int64)
+	for rows.Next() {
+		var raceID, total int64
+		if err := rows.Scan(&raceID, &total); err != nil {
+			return nil, err
+		}
+		result[raceID] = total
+	}
+	return result, rows.Err()
+}
@@ -2,17 +2,27 @@ package monitoring
 
 import (
 	"context"
+	"sort"
 	"time"
+
+	"pemira-api/internal/election"
 )
 
 type Service struct {
 	repo Repository
+	seal election.ResultSeal
 }
 
 func NewService(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// SetResultSeal sets the check that withholds per-candidate numbers while an
+// election's results are sealed.
+func (s *Service) SetResultSeal(seal election.ResultSeal) {
+	s.seal = seal
+}
+
 func (s *Service) GetLiveCountSnapshot(ctx context.Context, electionID int64) (*LiveCountSnapshot, error) {
 	// Get all stats in parallel
 	voteStats, err := s.repo.GetVoteStats(ctx, electionID)
@@ -45,14 +55,199 @@ func (s *Service) GetLiveCountSnapshot(ctx context.Context, electionID int64) (*
 		totalVotes += stat.TotalVotes
 	}
 
-	return &LiveCountSnapshot{
+	counts, weights, err := s.voteCounts(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	abstain, err := s.repo.GetAbstainCounts(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &LiveCountSnapshot{
 		ElectionID:     electionID,
 		Timestamp:      time.Now(),
 		TotalVotes:     totalVotes,
 		Participation:  *participation,
 		CandidateVotes: candidateVotes,
+		AbstainVotes:   sumCounts(abstain),
 		TPSStats:       tpsStatsVal,
-	}, nil
+	}
+
+	sealed, err := election.SealedFor(ctx, s.seal, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		// Turnout stays visible; per-candidate numbers are withheld.
+		snapshot.CandidateVotes = map[int64]int64{}
+		snapshot.ResultsSealed = true
+		return snapshot, nil
+	}
+
+	tallies := election.ComputeWeightedTallyByRace(counts, weights)
+	if _, single := tallies[0]; len(tallies) > 1 || (len(tallies) == 1 && !single) {
+		snapshot.RaceTallies = tallies
+	} else {
+		snapshot.WeightedTally = tallyOrEmpty(tallies, 0, weights)
+	}
+	return snapshot, nil
+}
+
+func (s *Service) voteCounts(ctx context.Context, electionID int64) ([]election.VoterTypeVoteCount, []election.VoterTypeWeight, error) {
+	counts, err := s.repo.GetVoteCountsByVoterType(ctx, electionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	weights, err := s.repo.GetVoterTypeWeights(ctx, electionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return counts, weights, nil
+}
+
+// tallyOrEmpty returns the race's tally, or an empty one when it has no votes yet.
+func tallyOrEmpty(tallies map[int64]*election.WeightedTally, raceID int64, weights []election.VoterTypeWeight) *election.WeightedTally {
+	if t, ok := tallies[raceID]; ok {
+		return t
+	}
+	return election.ComputeWeightedTally(nil, weights)
+}
+
+// GetElectionResult computes the final result: raw counts per constituency and
+// the ranking by combined share (weighted when the election has weighting rules).
+// Abstain ballots are reported separately and do not count towards any share.
+// Multi-race elections are ranked per race and have one winner set per race.
+func (s *Service) GetElectionResult(ctx context.Context, electionID int64) (*ElectionResult, error) {
+	sealed, err := election.SealedFor(ctx, s.seal, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		return nil, election.ErrResultsSealed
+	}
+
+	counts, weights, err := s.voteCounts(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.GetResultCandidates(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	races, err := s.repo.GetResultRaces(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	abstain, err := s.repo.GetAbstainCounts(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := election.ComputeWeightedTallyByRace(counts, weights)
+	result := &ElectionResult{
+		ElectionID:     electionID,
+		ComputedAt:     time.Now(),
+		AbstainVotes:   sumCounts(abstain),
+		Weighted:       len(weights) > 0,
+		Constituencies: election.ComputeWeightedTally(counts, weights).Constituencies,
+	}
+
+	if len(races) == 0 {
+		ranked, winners, total := rankCandidates(candidates, tallyOrEmpty(tallies, 0, weights))
+		result.TotalVotes = total
+		result.Candidates = ranked
+		result.WinnerCandidateIDs = winners
+		return result, nil
+	}
+
+	result.Candidates = make([]CandidateResult, 0, len(candidates))
+	for _, race := range races {
+		var raceCandidates []ResultCandidate
+		for _, c := range candidates {
+			if c.RaceID == race.RaceID {
+				raceCandidates = append(raceCandidates, c)
+			}
+		}
+		tally := tallyOrEmpty(tallies, race.RaceID, weights)
+		ranked, winners, total := rankCandidates(raceCandidates, tally)
+		for i := range ranked {
+			raceID := race.RaceID
+			ranked[i].RaceID = &raceID
+		}
+
+		result.Races = append(result.Races, RaceResult{
+			RaceID:             race.RaceID,
+			Code:               race.Code,
+			Name:               race.Name,
+			TotalVotes:         total,
+			AbstainVotes:       abstain[race.RaceID],
+			Constituencies:     tally.Constituencies,
+			Candidates:         ranked,
+			WinnerCandidateIDs: winners,
+		})
+		result.TotalVotes += total
+		result.Candidates = append(result.Candidates, ranked...)
+		result.WinnerCandidateIDs = append(result.WinnerCandidateIDs, winners...)
+	}
+	return result, nil
+}
+
+func sumCounts(counts map[int64]int64) int64 {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	return total
+}
+
+// rankCandidates ranks candidates competing in one tally and returns the
+// ranking, the winners (several on a tie) and the total votes of the tally.
+func rankCandidates(candidates []ResultCandidate, tally *election.WeightedTally) ([]CandidateResult, []int64, int64) {
+	var totalVotes int64
+	for _, pool := range tally.Constituencies {
+		totalVotes += pool.TotalVotes
+	}
+
+	results := make([]CandidateResult, 0, len(candidates))
+	for _, c := range candidates {
+		res := CandidateResult{
+			CandidateID:        c.CandidateID,
+			Number:             c.Number,
+			Name:               c.Name,
+			WeightedPercentage: tally.CandidatePercentages[c.CandidateID],
+			VotesByVoterType:   make(map[string]int64, len(tally.Constituencies)),
+		}
+		for _, pool := range tally.Constituencies {
+			votes := pool.CandidateVotes[c.CandidateID]
+			res.VotesByVoterType[pool.VoterType] = votes
+			res.TotalVotes += votes
+		}
+		if totalVotes > 0 {
+			res.Percentage = float64(res.TotalVotes) * 100 / float64(totalVotes)
+		}
+		results = append(results, res)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].WeightedPercentage != results[j].WeightedPercentage {
+			return results[i].WeightedPercentage > results[j].WeightedPercentage
+		}
+		return results[i].Number < results[j].Number
+	})
+
+	var winners []int64
+	for i := range results {
+		if i > 0 && results[i].WeightedPercentage == results[i-1].WeightedPercentage {
+			results[i].Rank = results[i-1].Rank
+		} else {
+			results[i].Rank = i + 1
+		}
+		if results[i].Rank == 1 && results[i].TotalVotes > 0 {
+			winners = append(winners, results[i].CandidateID)
+		}
+	}
+	return results, winners, totalVotes
 }
 
 func (s *Service) GetDashboardSummary(ctx context.Context, electionID int64) (map[string]interface{}, error) {
@@ -66,7 +261,10 @@ func (s *Service) GetDashboardSummary(ctx context.Context, electionID int64) (ma
 		"total_eligible":    snapshot.Participation.TotalEligible,
 		"participation_pct": snapshot.Participation.ParticipationPct,
 		"candidate_votes":   snapshot.CandidateVotes,
+		"weighted_tally":    snapshot.WeightedTally,
+		"race_tallies":      snapshot.RaceTallies,
 		"tps_count":         len(snapshot.TPSStats),
+		"results_sealed":    snapshot.ResultsSealed,
 		"last_updated":      snapshot.Timestamp,
 	}, nil
 }
@@ -11,6 +11,8 @@ const (
 	RequestIDKey  contextKey = "request_id"
 	ElectionIDKey contextKey = "election_id"
 	TPSIDKey      contextKey = "tps_id"
+	IPAddressKey  contextKey = "ip_address"
+	UserAgentKey  contextKey = "user_agent"
 )
 
 // GetVoterID extracts voter ID from context
@@ -56,3 +58,23 @@ func GetTPSID(ctx context.Context) (int64, bool) {
 	id, ok := v.(int64)
 	return id, ok
 }
+
+// GetIPAddress extracts client IP address from context
+func GetIPAddress(ctx context.Context) (string, bool) {
+	v := ctx.Value(IPAddressKey)
+	if v == nil {
+		return "", false
+	}
+	ip, ok := v.(string)
+	return ip, ok
+}
+
+// GetUserAgent extracts client user agent from context
+func GetUserAgent(ctx context.Context) (string, bool) {
+	v := ctx.Value(UserAgentKey)
+	if v == nil {
+		return "", false
+	}
+	ua, ok := v.(string)
+	return ua, ok
+}
@@ -131,4 +131,5 @@ type TPSActivityTimeline struct {
 	Total    int64  `json:"checkins"` // total checkins in bucket
 	Approved int64  `json:"approved"` // approved/used/voted
 	Voted    int64  `json:"voted"`    // voted in bucket (approx by status USED/VOTED)
+	Expired  int64  `json:"expired"`  // expired without being acted on
 }
@@ -529,7 +529,8 @@ func (r *PgAdminRepository) GetActivity(ctx context.Context, tpsID int64) (*TPSA
 			to_char(date_trunc('hour', scan_at), 'YYYY-MM-DD"T"HH24:00:00Z') AS bucket,
 			COUNT(*) AS total,
 			COUNT(*) FILTER (WHERE status IN ('APPROVED','USED','VOTED')) AS approved,
-			COUNT(*) FILTER (WHERE status IN ('USED','VOTED')) AS voted
+			COUNT(*) FILTER (WHERE status IN ('USED','VOTED')) AS voted,
+			COUNT(*) FILTER (WHERE status = 'EXPIRED') AS expired
 		FROM tps_checkins
 		WHERE tps_id = $1
 		  AND scan_at >= NOW() - INTERVAL '24 hour'
@@ -543,7 +544,7 @@ func (r *PgAdminRepository) GetActivity(ctx context.Context, tpsID int64) (*TPSA
 
 	for rows.Next() {
 		var tl TPSActivityTimeline
-		if err := rows.Scan(&tl.Hour, &tl.Total, &tl.Approved, &tl.Voted); err != nil {
+		if err := rows.Scan(&tl.Hour, &tl.Total, &tl.Approved, &tl.Voted, &tl.Expired); err != nil {
 			return nil, err
 		}
 		summary.Timeline = append(summary.Timeline, tl)
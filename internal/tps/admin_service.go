@@ -1,15 +1,25 @@
 package tps
 
-import "context"
+import (
+	"context"
+
+	"pemira-api/internal/ws"
+)
 
 type AdminService struct {
-	repo AdminRepository
+	repo   AdminRepository
+	events ws.Publisher
 }
 
 func NewAdminService(repo AdminRepository) *AdminService {
 	return &AdminService{repo: repo}
 }
 
+// SetEventPublisher sets where TPS status changes are published.
+func (s *AdminService) SetEventPublisher(p ws.Publisher) {
+	s.events = p
+}
+
 // CRUD TPS
 func (s *AdminService) List(ctx context.Context, electionID int64) ([]TPSDTO, error) {
 	return s.repo.List(ctx, electionID)
@@ -24,7 +34,18 @@ func (s *AdminService) Create(ctx context.Context, req TPSCreateRequest) (*TPSDT
 }
 
 func (s *AdminService) Update(ctx context.Context, id int64, req TPSUpdateRequest) (*TPSDTO, error) {
-	return s.repo.Update(ctx, id, req)
+	dto, err := s.repo.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		status := StatusDraft
+		if *req.IsActive {
+			status = StatusActive
+		}
+		ws.PublishTPSStatus(s.events, ws.TPSStatusEvent{TPSID: id, Status: status})
+	}
+	return dto, nil
 }
 
 func (s *AdminService) Delete(ctx context.Context, id int64) error {
@@ -224,6 +224,7 @@ type PanelTimelineRow struct {
 	BucketStart time.Time
 	CheckedIn   int
 	Voted       int
+	Expired     int
 }
 
 type PanelLogRow struct {
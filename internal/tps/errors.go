@@ -11,6 +11,9 @@ var (
 	ErrTPSClosed            = errors.New("TPS sudah ditutup")
 	ErrQRInvalid            = errors.New("Payload QR tidak valid")
 	ErrQRRevoked            = errors.New("QR sudah tidak berlaku")
+	ErrQRExpired            = errors.New("QR sudah kedaluwarsa, pindai ulang QR di layar TPS")
+	ErrQRReplayed           = errors.New("QR ini sudah digunakan, pindai ulang QR di layar TPS")
+	ErrQRStaticDisabled     = errors.New("QR cetak tidak berlaku, pindai QR di layar TPS")
 	ErrElectionNotOpen      = errors.New("Pemilu bukan di fase voting")
 	ErrNotEligible          = errors.New("Mahasiswa bukan DPT / tidak berhak")
 	ErrAlreadyVoted         = errors.New("Mahasiswa sudah pernah voting")
@@ -38,6 +41,9 @@ var errorCodeMap = map[error]ErrorCode{
 	ErrTPSClosed:            {Code: "TPS_CLOSED", HTTPStatus: http.StatusBadRequest},
 	ErrQRInvalid:            {Code: "QR_INVALID", HTTPStatus: http.StatusBadRequest},
 	ErrQRRevoked:            {Code: "QR_REVOKED", HTTPStatus: http.StatusBadRequest},
+	ErrQRExpired:            {Code: "QR_EXPIRED", HTTPStatus: http.StatusBadRequest},
+	ErrQRReplayed:           {Code: "QR_REPLAYED", HTTPStatus: http.StatusConflict},
+	ErrQRStaticDisabled:     {Code: "QR_STATIC_DISABLED", HTTPStatus: http.StatusBadRequest},
 	ErrElectionNotOpen:      {Code: "ELECTION_NOT_OPEN", HTTPStatus: http.StatusBadRequest},
 	ErrNotEligible:          {Code: "NOT_ELIGIBLE", HTTPStatus: http.StatusBadRequest},
 	ErrAlreadyVoted:         {Code: "ALREADY_VOTED", HTTPStatus: http.StatusConflict},
@@ -118,6 +118,15 @@ func (h *Handler) handleTPSError(w http.ResponseWriter, err error) {
 	case errors.Is(err, ErrQRRevoked):
 		response.Error(w, http.StatusBadRequest, "QR_REVOKED", "Kode QR ini sudah tidak berlaku.", nil)
 
+	case errors.Is(err, ErrQRExpired):
+		response.Error(w, http.StatusBadRequest, "QR_EXPIRED", "Kode QR sudah kedaluwarsa, pindai ulang QR di layar TPS.", nil)
+
+	case errors.Is(err, ErrQRReplayed):
+		response.Error(w, http.StatusConflict, "QR_REPLAYED", "Kode QR ini sudah Anda gunakan, pindai ulang QR di layar TPS.", nil)
+
+	case errors.Is(err, ErrQRStaticDisabled):
+		response.Error(w, http.StatusBadRequest, "QR_STATIC_DISABLED", "QR cetak tidak berlaku, pindai QR di layar TPS.", nil)
+
 	case errors.Is(err, ErrTPSNotFound):
 		response.Error(w, http.StatusNotFound, "TPS_NOT_FOUND", "TPS tidak ditemukan.", nil)
 
@@ -327,6 +327,7 @@ func (h *PanelHandler) handleCheckin(w http.ResponseWriter, r *http.Request, use
 			return
 		}
 	}
+	h.svc.notifyCheckinCreated(checkin)
 
 	resp := map[string]interface{}{
 		"success": true,
@@ -384,6 +385,41 @@ func (h *PanelHandler) Status(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// GET /tps-panel/qr/current
+func (h *PanelHandler) CurrentQR(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	electionID, err := strconv.ParseInt(chi.URLParam(r, "electionID"), 10, 64)
+	if err != nil || electionID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionId tidak valid.")
+		return
+	}
+	tpsID, err := strconv.ParseInt(chi.URLParam(r, "tpsID"), 10, 64)
+	if err != nil || tpsID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "tpsId tidak valid.")
+		return
+	}
+	role, _ := ctxkeys.GetUserRole(ctx)
+	tokenTPS, _ := ctxkeys.GetTPSID(ctx)
+	if role == "" {
+		response.Forbidden(w, "TPS_ACCESS_DENIED", "Akses ditolak.")
+		return
+	}
+
+	if _, err := h.svc.EnsureAccess(ctx, electionID, tpsID, role, &tokenTPS); err != nil {
+		code, status := GetErrorCode(err)
+		response.Error(w, status, code, err.Error(), nil)
+		return
+	}
+
+	qr, err := h.svc.CurrentQR(ctx, tpsID)
+	if err != nil {
+		code, status := GetErrorCode(err)
+		response.Error(w, status, code, err.Error(), nil)
+		return
+	}
+	response.JSON(w, http.StatusOK, qr)
+}
+
 // GET /tps-panel/stats
 func (h *PanelHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
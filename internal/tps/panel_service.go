@@ -4,16 +4,57 @@ import (
 	"context"
 	"strings"
 	"time"
+
+	"pemira-api/internal/ws"
 )
 
 type PanelService struct {
-	repo Repository
+	repo            Repository
+	checkinListener CheckinListener
+	events          ws.Publisher
+	rotatingQR      *RotatingQR
 }
 
 func NewPanelService(repo Repository) *PanelService {
 	return &PanelService{repo: repo}
 }
 
+// SetCheckinListener sets the listener told about check-ins created from the panel.
+func (s *PanelService) SetCheckinListener(l CheckinListener) {
+	s.checkinListener = l
+}
+
+// SetEventPublisher sets where check-ins created from the panel are published.
+func (s *PanelService) SetEventPublisher(p ws.Publisher) {
+	s.events = p
+}
+
+// SetRotatingQR enables the rotating check-in QR shown on the panel.
+func (s *PanelService) SetRotatingQR(r *RotatingQR) {
+	s.rotatingQR = r
+}
+
+func (s *PanelService) notifyCheckinCreated(row *PanelCheckinRow) {
+	if s.checkinListener != nil {
+		s.checkinListener.CheckinUpdated(row.ElectionID, row.TPSID)
+	}
+	scanAt := row.ScanAt
+	ws.PublishCheckin(s.events, ws.EventCheckinNew, ws.CheckinEvent{
+		CheckinID:  row.ID,
+		ElectionID: row.ElectionID,
+		TPSID:      row.TPSID,
+		Status:     row.Status,
+		Voter: &VoterInfo{
+			ID:           row.VoterID,
+			NIM:          row.VoterNIM,
+			Name:         row.VoterName,
+			Faculty:      row.Faculty,
+			StudyProgram: row.Program,
+		},
+		ScanAt: &scanAt,
+	})
+}
+
 type PanelDashboard struct {
 	ElectionID int64        `json:"election_id"`
 	TPS        PanelTPSInfo `json:"tps"`
@@ -69,6 +110,7 @@ type TimelinePoint struct {
 	Hour      string `json:"hour"`
 	CheckedIn int    `json:"checked_in"`
 	Voted     int    `json:"voted"`
+	Expired   int    `json:"expired"`
 }
 
 type PanelLog struct {
@@ -242,6 +284,25 @@ type TPSStatusResponse struct {
 	VotingWindow VotingWindow `json:"voting_window"`
 }
 
+// CurrentQR returns the rotating check-in QR the panel should display now.
+func (s *PanelService) CurrentQR(ctx context.Context, tpsID int64) (*RotatingQRPayload, error) {
+	if s.rotatingQR == nil {
+		return nil, ErrQRInvalid
+	}
+	tpsRow, err := s.repo.GetByID(ctx, tpsID)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := s.repo.GetActiveQR(ctx, tpsID)
+	if err != nil {
+		return nil, err
+	}
+	if qr == nil {
+		return nil, ErrQRInvalid
+	}
+	return s.rotatingQR.Payload(tpsRow.ID, tpsRow.Code, qr.QRToken, time.Now()), nil
+}
+
 func (s *PanelService) Timeline(ctx context.Context, tpsID int64) ([]TimelinePoint, error) {
 	points, err := s.repo.PanelTimeline(ctx, tpsID)
 	if err != nil {
@@ -253,6 +314,7 @@ func (s *PanelService) Timeline(ctx context.Context, tpsID int64) ([]TimelinePoi
 			Hour:      p.BucketStart.Format(time.RFC3339),
 			CheckedIn: p.CheckedIn,
 			Voted:     p.Voted,
+			Expired:   p.Expired,
 		})
 	}
 	return resp, nil
@@ -317,5 +379,10 @@ func (s *PanelService) CreateCheckinViaQR(ctx context.Context, tpsID int64, raw
 	}
 
 	reg.TPSID = &tpsID
-	return s.repo.CreatePanelCheckin(ctx, *reg)
+	row, err := s.repo.CreatePanelCheckin(ctx, *reg)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyCheckinCreated(row)
+	return row, nil
 }
@@ -21,6 +21,11 @@ type Repository interface {
 	GetQRMetadata(ctx context.Context, tpsID int64) (*QRInfo, error)
 	RotateQR(ctx context.Context, tpsID int64) (*QRInfo, error)
 	GetQRPrintPayload(ctx context.Context, tpsID int64) (string, error)
+	// CreateRotatingCheckin creates checkin and records that its voter used
+	// the rotating code of window at its TPS, in one transaction. It reports
+	// false, creating nothing, when that code or a later one was already used
+	// by the voter.
+	CreateRotatingCheckin(ctx context.Context, checkin *TPSCheckin, window int64) (bool, error)
 
 	// Panitia Management
 	AssignPanitia(ctx context.Context, tpsID int64, members []TPSPanitia) error
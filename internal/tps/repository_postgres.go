@@ -377,6 +377,41 @@ func (r *PostgresRepository) GetQRPrintPayload(ctx context.Context, tpsID int64)
 	return fmt.Sprintf("PEMIRA|%s|%s", tpsRow.Code, qr.QRToken), nil
 }
 
+func (r *PostgresRepository) CreateRotatingCheckin(ctx context.Context, checkin *TPSCheckin, window int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO tps_qr_window_uses (tps_id, voter_id, last_window, used_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (tps_id, voter_id) DO UPDATE
+		SET last_window = EXCLUDED.last_window, used_at = EXCLUDED.used_at
+		WHERE tps_qr_window_uses.last_window < EXCLUDED.last_window
+	`, checkin.TPSID, checkin.VoterID, window)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO tps_checkins (tps_id, voter_id, election_id, status, scan_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`,
+		checkin.TPSID, checkin.VoterID, checkin.ElectionID,
+		checkin.Status, checkin.ScanAt,
+	).Scan(&checkin.ID, &checkin.CreatedAt, &checkin.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
 // Panitia Management
 func (r *PostgresRepository) AssignPanitia(ctx context.Context, tpsID int64, members []TPSPanitia) error {
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -841,17 +876,28 @@ func (r *PostgresRepository) PanelTimeline(ctx context.Context, tpsID int64) ([]
 			WHERE tps_id = $1
 			GROUP BY 1
 		),
+		expired_hours AS (
+			SELECT date_trunc('hour', expired_at) AS hour_ts,
+			       COUNT(*) AS expired
+			FROM tps_checkins
+			WHERE tps_id = $1 AND status = 'EXPIRED' AND expired_at IS NOT NULL
+			GROUP BY 1
+		),
 		hours AS (
 			SELECT hour_ts FROM checkin_hours
 			UNION
 			SELECT hour_ts FROM vote_hours
+			UNION
+			SELECT hour_ts FROM expired_hours
 		)
-		SELECT to_char(hours.hour_ts, 'HH24:MI') AS hour,
+		SELECT hours.hour_ts AS hour,
 		       COALESCE(ch.checked_in, 0) AS checked_in,
-		       COALESCE(vh.voted, 0) AS voted
+		       COALESCE(vh.voted, 0) AS voted,
+		       COALESCE(eh.expired, 0) AS expired
 		FROM hours
 		LEFT JOIN checkin_hours ch ON ch.hour_ts = hours.hour_ts
 		LEFT JOIN vote_hours vh ON vh.hour_ts = hours.hour_ts
+		LEFT JOIN expired_hours eh ON eh.hour_ts = hours.hour_ts
 		ORDER BY hours.hour_ts
 	`
 
@@ -864,7 +910,7 @@ func (r *PostgresRepository) PanelTimeline(ctx context.Context, tpsID int64) ([]
 	var list []PanelTimelineRow
 	for rows.Next() {
 		var row PanelTimelineRow
-		if err := rows.Scan(&row.BucketStart, &row.CheckedIn, &row.Voted); err != nil {
+		if err := rows.Scan(&row.BucketStart, &row.CheckedIn, &row.Voted, &row.Expired); err != nil {
 			return nil, err
 		}
 		list = append(list, row)
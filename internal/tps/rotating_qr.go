@@ -0,0 +1,113 @@
+package tps
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotatingQR derives TPS check-in codes that change every Period from the
+// secret of the active tps_qr row and the time window, TOTP-style. The panel
+// displays the current code; a photographed code stops working once its
+// window (plus Skew windows of clock allowance) has passed.
+//
+// Rotating payload: "PEMIRA|TPS01|<window>|<code>". The static payload
+// "PEMIRA|TPS01|<secret>" printed by AdminGetQRPrint is still accepted while
+// StaticAllowed is set.
+type RotatingQR struct {
+	Period        time.Duration
+	Skew          int
+	StaticAllowed bool
+}
+
+func NewRotatingQR(period time.Duration, skew int, staticAllowed bool) *RotatingQR {
+	if period < time.Second {
+		period = 30 * time.Second
+	}
+	if skew < 0 {
+		skew = 0
+	}
+	return &RotatingQR{Period: period, Skew: skew, StaticAllowed: staticAllowed}
+}
+
+// RotatingQRPayload is the code a TPS panel displays right now.
+type RotatingQRPayload struct {
+	TPSID         int64     `json:"tps_id"`
+	Payload       string    `json:"payload"`
+	Window        int64     `json:"window"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	PeriodSeconds int       `json:"period_seconds"`
+}
+
+// checkinQR is a parsed check-in payload. Secret is set for static payloads,
+// Window and Code for rotating ones.
+type checkinQR struct {
+	TPSCode  string
+	Secret   string
+	Window   int64
+	Code     string
+	Rotating bool
+}
+
+func parseCheckinQR(payload string) (*checkinQR, error) {
+	parts := strings.Split(payload, "|")
+	if parts[0] != "PEMIRA" {
+		return nil, ErrQRInvalid
+	}
+	switch len(parts) {
+	case 3:
+		if parts[1] == "" || parts[2] == "" {
+			return nil, ErrQRInvalid
+		}
+		return &checkinQR{TPSCode: parts[1], Secret: parts[2]}, nil
+	case 4:
+		window, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || parts[1] == "" || parts[3] == "" {
+			return nil, ErrQRInvalid
+		}
+		return &checkinQR{TPSCode: parts[1], Window: window, Code: parts[3], Rotating: true}, nil
+	}
+	return nil, ErrQRInvalid
+}
+
+func (r *RotatingQR) window(t time.Time) int64 {
+	return t.Unix() / int64(r.Period/time.Second)
+}
+
+// code is the first 8 bytes of HMAC-SHA256(secret, "<tpsCode>|<window>"), hex.
+func (r *RotatingQR) code(tpsCode, secret string, window int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s|%d", tpsCode, window)
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// Payload returns the code to display at now.
+func (r *RotatingQR) Payload(tpsID int64, tpsCode, secret string, now time.Time) *RotatingQRPayload {
+	window := r.window(now)
+	period := int64(r.Period / time.Second)
+	return &RotatingQRPayload{
+		TPSID:         tpsID,
+		Payload:       fmt.Sprintf("PEMIRA|%s|%d|%s", tpsCode, window, r.code(tpsCode, secret, window)),
+		Window:        window,
+		ExpiresAt:     time.Unix((window+1)*period, 0).UTC(),
+		PeriodSeconds: int(period),
+	}
+}
+
+// Verify checks a rotating payload against the secret of its TPS. Codes from
+// more than Skew windows away from now are expired.
+func (r *RotatingQR) Verify(qr *checkinQR, secret string, now time.Time) error {
+	want := r.code(qr.TPSCode, secret, qr.Window)
+	if !hmac.Equal([]byte(want), []byte(strings.ToLower(qr.Code))) {
+		return ErrQRInvalid
+	}
+	current := r.window(now)
+	if qr.Window < current-int64(r.Skew) || qr.Window > current+int64(r.Skew) {
+		return ErrQRExpired
+	}
+	return nil
+}
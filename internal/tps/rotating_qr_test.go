@@ -0,0 +1,36 @@
+package tps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingQR_Verify(t *testing.T) {
+	r := NewRotatingQR(30*time.Second, 1, true)
+	now := time.Date(2025, 6, 12, 9, 0, 10, 0, time.UTC)
+
+	shown := r.Payload(1, "TPS01", "c9423e5f97d4", now)
+	assert.Equal(t, now.Add(20*time.Second), shown.ExpiresAt)
+
+	qr, err := parseCheckinQR(shown.Payload)
+	require.NoError(t, err)
+	require.True(t, qr.Rotating)
+
+	assert.NoError(t, r.Verify(qr, "c9423e5f97d4", now))
+	// One window of skew either way.
+	assert.NoError(t, r.Verify(qr, "c9423e5f97d4", now.Add(-30*time.Second)))
+	assert.NoError(t, r.Verify(qr, "c9423e5f97d4", now.Add(40*time.Second)))
+	assert.Equal(t, ErrQRExpired, r.Verify(qr, "c9423e5f97d4", now.Add(80*time.Second)))
+
+	// The code is bound to the secret, the TPS and the window.
+	assert.Equal(t, ErrQRInvalid, r.Verify(qr, "rotated-secret", now))
+	other := *qr
+	other.TPSCode = "TPS02"
+	assert.Equal(t, ErrQRInvalid, r.Verify(&other, "c9423e5f97d4", now))
+	other = *qr
+	other.Window++
+	assert.Equal(t, ErrQRInvalid, r.Verify(&other, "c9423e5f97d4", now))
+}
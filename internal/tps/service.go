@@ -7,16 +7,72 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"pemira-api/internal/ws"
 )
 
 type Service struct {
-	repo Repository
+	repo            Repository
+	checkinListener CheckinListener
+	events          ws.Publisher
+	rotatingQR      *RotatingQR
+}
+
+// CheckinListener is told when a check-in is created or changes status
+// (implemented by monitoring.LiveCount).
+type CheckinListener interface {
+	CheckinUpdated(electionID, tpsID int64)
 }
 
 func NewService(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// SetCheckinListener sets the listener told about check-in changes.
+func (s *Service) SetCheckinListener(l CheckinListener) {
+	s.checkinListener = l
+}
+
+// SetEventPublisher sets where queue and TPS status events are published.
+func (s *Service) SetEventPublisher(p ws.Publisher) {
+	s.events = p
+}
+
+// SetRotatingQR enables rotating check-in QR codes. Without it only static
+// payloads are accepted.
+func (s *Service) SetRotatingQR(r *RotatingQR) {
+	s.rotatingQR = r
+}
+
+// notifyCheckin tells the listener about a check-in change and publishes it
+// to the queue of its TPS as eventType.
+func (s *Service) notifyCheckin(ctx context.Context, eventType string, checkin *TPSCheckin) {
+	if s.checkinListener != nil {
+		s.checkinListener.CheckinUpdated(checkin.ElectionID, checkin.TPSID)
+	}
+	if s.events == nil {
+		return
+	}
+
+	event := ws.CheckinEvent{
+		CheckinID:  checkin.ID,
+		ElectionID: checkin.ElectionID,
+		TPSID:      checkin.TPSID,
+		Status:     checkin.Status,
+	}
+	if eventType == ws.EventCheckinNew {
+		if voter, _ := s.repo.GetVoterInfo(ctx, checkin.VoterID); voter != nil {
+			event.Voter = voter
+		}
+		scanAt := checkin.ScanAt
+		event.ScanAt = &scanAt
+	}
+	if checkin.RejectionReason != nil {
+		event.Reason = *checkin.RejectionReason
+	}
+	ws.PublishCheckin(s.events, eventType, event)
+}
+
 func (s *Service) ensureTPSElection(ctx context.Context, electionID, tpsID int64) (*TPS, error) {
 	if electionID > 0 {
 		return s.repo.GetByIDElection(ctx, electionID, tpsID)
@@ -199,7 +255,6 @@ func (s *Service) Update(ctx context.Context, id int64, req *UpdateTPSRequest) e
 
 	tps.Name = req.Name
 	tps.Location = req.Location
-	tps.Status = req.Status
 	tps.VotingDate = &votingDate
 	tps.OpenTime = req.OpenTime
 	tps.CloseTime = req.CloseTime
@@ -208,7 +263,15 @@ func (s *Service) Update(ctx context.Context, id int64, req *UpdateTPSRequest) e
 	tps.PICPhone = req.PICPhone
 	tps.Notes = req.Notes
 
-	return s.repo.Update(ctx, tps)
+	previousStatus := tps.Status
+	tps.Status = req.Status
+	if err := s.repo.Update(ctx, tps); err != nil {
+		return err
+	}
+	if tps.Status != previousStatus {
+		ws.PublishTPSStatus(s.events, ws.TPSStatusEvent{TPSID: tps.ID, Status: tps.Status})
+	}
+	return nil
 }
 
 func (s *Service) UpdateWithElection(ctx context.Context, electionID, id int64, req *UpdateTPSRequest) error {
@@ -330,27 +393,20 @@ func (s *Service) RegenerateQR(ctx context.Context, tpsID int64) (*RegenerateQRR
 
 // Student Check-in
 func (s *Service) ScanQR(ctx context.Context, voterID int64, req *ScanQRRequest) (*ScanQRResponse, error) {
-	// Parse QR payload: PEMIRA|TPS01|c9423e5f97d4
-	parts := strings.Split(req.QRPayload, "|")
-	if len(parts) != 3 || parts[0] != "PEMIRA" {
-		return nil, ErrQRInvalid
-	}
-
-	tpsCode := parts[1]
-	qrSecret := parts[2]
-
-	// Validate QR
-	qr, err := s.repo.GetQRBySecret(ctx, tpsCode, qrSecret)
+	// Parse QR payload: PEMIRA|TPS01|c9423e5f97d4 (static) or
+	// PEMIRA|TPS01|<window>|<code> (rotating)
+	payload, err := parseCheckinQR(req.QRPayload)
 	if err != nil {
-		return nil, ErrQRInvalid
+		return nil, err
 	}
 
-	if !qr.IsActive {
-		return nil, ErrQRRevoked
+	// Validate QR
+	if err := s.verifyCheckinQR(ctx, payload); err != nil {
+		return nil, err
 	}
 
 	// Validate TPS
-	tps, err := s.repo.GetByCode(ctx, tpsCode)
+	tps, err := s.repo.GetByCode(ctx, payload.TPSCode)
 	if err != nil {
 		return nil, ErrTPSNotFound
 	}
@@ -395,9 +451,20 @@ func (s *Service) ScanQR(ctx context.Context, voterID int64, req *ScanQRRequest)
 		ScanAt:     time.Now(),
 	}
 
-	if err := s.repo.CreateCheckin(ctx, checkin); err != nil {
+	// A rotating code is good for one check-in per voter; it is only spent
+	// together with the check-in it creates
+	if payload.Rotating {
+		fresh, err := s.repo.CreateRotatingCheckin(ctx, checkin, payload.Window)
+		if err != nil {
+			return nil, err
+		}
+		if !fresh {
+			return nil, ErrQRReplayed
+		}
+	} else if err := s.repo.CreateCheckin(ctx, checkin); err != nil {
 		return nil, err
 	}
+	s.notifyCheckin(ctx, ws.EventCheckinNew, checkin)
 
 	return &ScanQRResponse{
 		CheckinID: checkin.ID,
@@ -412,6 +479,37 @@ func (s *Service) ScanQR(ctx context.Context, voterID int64, req *ScanQRRequest)
 	}, nil
 }
 
+// verifyCheckinQR checks a static payload against the active tps_qr secret,
+// or a rotating one against the code derived from it.
+func (s *Service) verifyCheckinQR(ctx context.Context, payload *checkinQR) error {
+	if !payload.Rotating {
+		if s.rotatingQR != nil && !s.rotatingQR.StaticAllowed {
+			return ErrQRStaticDisabled
+		}
+		qr, err := s.repo.GetQRBySecret(ctx, payload.TPSCode, payload.Secret)
+		if err != nil {
+			return ErrQRInvalid
+		}
+		if !qr.IsActive {
+			return ErrQRRevoked
+		}
+		return nil
+	}
+
+	if s.rotatingQR == nil {
+		return ErrQRInvalid
+	}
+	tps, err := s.repo.GetByCode(ctx, payload.TPSCode)
+	if err != nil {
+		return ErrQRInvalid
+	}
+	qr, err := s.repo.GetActiveQR(ctx, tps.ID)
+	if err != nil || qr == nil {
+		return ErrQRInvalid
+	}
+	return s.rotatingQR.Verify(payload, qr.QRToken, time.Now())
+}
+
 func (s *Service) GetCheckinStatus(ctx context.Context, voterID, electionID int64) (*CheckinStatusResponse, error) {
 	checkin, err := s.repo.GetCheckinByVoter(ctx, voterID, electionID)
 	if err != nil || checkin == nil {
@@ -521,6 +619,7 @@ func (s *Service) ApproveCheckin(ctx context.Context, tpsID, checkinID, approver
 	if err := s.repo.UpdateCheckin(ctx, checkin); err != nil {
 		return nil, err
 	}
+	s.notifyCheckin(ctx, ws.EventCheckinUpdated, checkin)
 
 	voterInfo, _ := s.repo.GetVoterInfo(ctx, checkin.VoterID)
 	if voterInfo == nil {
@@ -566,6 +665,7 @@ func (s *Service) RejectCheckin(ctx context.Context, tpsID, checkinID, approverI
 	if err := s.repo.UpdateCheckin(ctx, checkin); err != nil {
 		return nil, err
 	}
+	s.notifyCheckin(ctx, ws.EventCheckinUpdated, checkin)
 
 	return &RejectCheckinResponse{
 		CheckinID: checkin.ID,
@@ -2,8 +2,7 @@ package tps
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"encoding/json"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -11,7 +10,8 @@ import (
 )
 
 type CheckinService struct {
-	db *pgxpool.Pool
+	db         *pgxpool.Pool
+	rotatingQR *RotatingQR
 }
 
 func NewCheckinService(db *pgxpool.Pool) *CheckinService {
@@ -20,6 +20,12 @@ func NewCheckinService(db *pgxpool.Pool) *CheckinService {
 	}
 }
 
+// SetRotatingQR enables rotating check-in QR codes. Without it only static
+// payloads are accepted.
+func (s *CheckinService) SetRotatingQR(r *RotatingQR) {
+	s.rotatingQR = r
+}
+
 func (s *CheckinService) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -45,7 +51,7 @@ func (s *CheckinService) CheckinScan(
 	qrPayload string,
 ) (*ScanQRResponse, error) {
 	// Parse QR payload
-	tpsCode, qrSecret, err := s.parseQRPayload(qrPayload)
+	payload, err := s.parseQRPayload(qrPayload)
 	if err != nil {
 		return nil, ErrQRInvalid
 	}
@@ -54,7 +60,7 @@ func (s *CheckinService) CheckinScan(
 
 	err = s.withTx(ctx, func(tx pgx.Tx) error {
 		// 1. Load QR entry & TPS
-		qr, err := s.findActiveQRByCodeAndSecret(ctx, tx, tpsCode, qrSecret)
+		qr, err := s.findCheckinQR(ctx, tx, payload)
 		if err != nil {
 			return err
 		}
@@ -74,8 +80,8 @@ func (s *CheckinService) CheckinScan(
 			return err
 		}
 
-		if election.Status != "VOTING_OPEN" {
-			return ErrElectionNotOpen
+		if err := s.ensureVotingOpen(ctx, tx, election, tpsEntry.ID); err != nil {
+			return err
 		}
 
 		// 3. Load voter + status
@@ -115,7 +121,14 @@ func (s *CheckinService) CheckinScan(
 			return nil
 		}
 
-		// 5. Buat row tps_checkins status PENDING
+		// 5. QR dinamis hanya berlaku sekali per pemilih
+		if payload.Rotating {
+			if err := s.useQRWindow(ctx, tx, tpsEntry.ID, voter.ID, payload.Window); err != nil {
+				return err
+			}
+		}
+
+		// 6. Buat row tps_checkins status PENDING
 		now := time.Now().UTC()
 		checkinID, err := s.insertCheckin(ctx, tx, &TPSCheckin{
 			ElectionID: election.ID,
@@ -128,8 +141,9 @@ func (s *CheckinService) CheckinScan(
 			return err
 		}
 
-		// 6. (Opsional) Audit log
-		_ = s.logAudit(ctx, tx, AuditLog{
+		// 7. Audit log, committed with the check-in
+		if err := logAudit(ctx, tx, AuditLog{
+			ElectionID:   &election.ID,
 			ActorVoterID: &voter.ID,
 			Action:       "TPS_CHECKIN_CREATED",
 			EntityType:   "TPS_CHECKIN",
@@ -137,9 +151,11 @@ func (s *CheckinService) CheckinScan(
 			Metadata: map[string]interface{}{
 				"tps_id": tpsEntry.ID,
 			},
-		})
+		}); err != nil {
+			return err
+		}
 
-		// 7. Build result
+		// 8. Build result
 		result = &ScanQRResponse{
 			CheckinID: checkinID,
 			TPS: TPSInfo{
@@ -201,8 +217,8 @@ func (s *CheckinService) ApproveCheckin(
 			return err
 		}
 
-		if election.Status != "VOTING_OPEN" {
-			return ErrElectionNotOpen
+		if err := s.ensureVotingOpen(ctx, tx, election, checkin.TPSID); err != nil {
+			return err
 		}
 
 		status, err := s.getVoterStatus(ctx, tx, election.ID, checkin.VoterID)
@@ -234,7 +250,8 @@ func (s *CheckinService) ApproveCheckin(
 		}
 
 		// 6. Audit
-		_ = s.logAudit(ctx, tx, AuditLog{
+		if err := logAudit(ctx, tx, AuditLog{
+			ElectionID:  &election.ID,
 			ActorUserID: &operatorUserID,
 			Action:      "TPS_CHECKIN_APPROVED",
 			EntityType:  "TPS_CHECKIN",
@@ -244,7 +261,9 @@ func (s *CheckinService) ApproveCheckin(
 				"voter_id":   voter.ID,
 				"expires_at": expiresAt,
 			},
-		})
+		}); err != nil {
+			return err
+		}
 
 		// Build result
 		result = &ApproveCheckinResponse{
@@ -314,7 +333,8 @@ func (s *CheckinService) RejectCheckin(
 		}
 
 		// 4. Audit
-		_ = s.logAudit(ctx, tx, AuditLog{
+		if err := logAudit(ctx, tx, AuditLog{
+			ElectionID:  &checkin.ElectionID,
 			ActorUserID: &operatorUserID,
 			Action:      "TPS_CHECKIN_REJECTED",
 			EntityType:  "TPS_CHECKIN",
@@ -323,7 +343,9 @@ func (s *CheckinService) RejectCheckin(
 				"tps_id": tpsID,
 				"reason": reason,
 			},
-		})
+		}); err != nil {
+			return err
+		}
 
 		result = &RejectCheckinResponse{
 			CheckinID: checkin.ID,
@@ -341,15 +363,193 @@ func (s *CheckinService) RejectCheckin(
 	return result, nil
 }
 
+// OfflineCheckin is a check-in a TPS panel recorded while offline and
+// uploads later through the TPS sync API.
+type OfflineCheckin struct {
+	ElectionID     int64
+	TPSID          int64
+	VoterID        int64
+	OperatorUserID int64
+	ScannedAt      time.Time
+}
+
+// ApplyOfflineCheckin records an offline check-in inside the caller's
+// transaction with the checks of CheckinScan and ApproveCheckin. The panel
+// already verified the voter on site, so the check-in is approved directly
+// (a PENDING check-in of the voter at this TPS is approved instead of adding
+// another), as of ScannedAt. The caller checks that the TPS was open for
+// voting at ScannedAt.
+func (s *CheckinService) ApplyOfflineCheckin(ctx context.Context, tx pgx.Tx, req OfflineCheckin) (*TPSCheckin, error) {
+	// 1. TPS & election
+	tpsEntry, err := s.getTPSByID(ctx, tx, req.TPSID)
+	if err != nil {
+		return nil, err
+	}
+	if tpsEntry.Status != StatusActive {
+		return nil, ErrTPSInactive
+	}
+	if tpsEntry.ElectionID != req.ElectionID {
+		return nil, ErrTPSMismatch
+	}
+
+	election, err := s.getElectionByID(ctx, tx, tpsEntry.ElectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Voter + status
+	voter, err := s.getVoterByID(ctx, tx, election.ID, req.VoterID)
+	if err != nil {
+		return nil, err
+	}
+	if !voter.IsEligible {
+		return nil, ErrNotEligible
+	}
+
+	status, err := s.getVoterStatus(ctx, tx, election.ID, voter.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status.HasVoted {
+		return nil, ErrAlreadyVoted
+	}
+
+	active, err := s.getActiveCheckin(ctx, tx, voter.ID, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, ErrCheckinAlreadyExists
+	}
+
+	// 3. Approve the pending check-in or create an approved one. The panel
+	// approved it on site at ScannedAt; the ballot may follow until expiresAt.
+	approvedAt := req.ScannedAt
+	expiresAt := time.Now().UTC().Add(15 * time.Minute)
+
+	checkin, err := s.getPendingCheckin(ctx, tx, voter.ID, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if checkin == nil || checkin.TPSID != tpsEntry.ID {
+		checkin = &TPSCheckin{
+			ElectionID: election.ID,
+			TPSID:      tpsEntry.ID,
+			VoterID:    voter.ID,
+			Status:     CheckinStatusPending,
+			ScanAt:     req.ScannedAt,
+		}
+		if checkin.ID, err = s.insertCheckin(ctx, tx, checkin); err != nil {
+			return nil, err
+		}
+	}
+	if approvedAt.Before(checkin.ScanAt) {
+		approvedAt = checkin.ScanAt
+	}
+
+	if err := s.updateCheckinToApproved(ctx, tx, checkin.ID, req.OperatorUserID, approvedAt, expiresAt); err != nil {
+		return nil, err
+	}
+	checkin.Status = CheckinStatusApproved
+	checkin.ApprovedAt = &approvedAt
+	checkin.ApprovedByID = &req.OperatorUserID
+	checkin.ExpiresAt = &expiresAt
+
+	// 4. Audit
+	if err := logAudit(ctx, tx, AuditLog{
+		ElectionID:  &election.ID,
+		ActorUserID: &req.OperatorUserID,
+		Action:      "TPS_CHECKIN_APPROVED",
+		EntityType:  "TPS_CHECKIN",
+		EntityID:    checkin.ID,
+		Metadata: map[string]interface{}{
+			"tps_id":     tpsEntry.ID,
+			"voter_id":   voter.ID,
+			"expires_at": expiresAt,
+			"offline":    true,
+			"scanned_at": req.ScannedAt,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return checkin, nil
+}
+
 // ===== Helper functions (repository methods with tx) =====
 
-func (s *CheckinService) parseQRPayload(payload string) (tpsCode, qrSecret string, err error) {
-	// Format: "PEMIRA|TPS01|c9423e5f97d4"
-	parts := strings.Split(payload, "|")
-	if len(parts) != 3 || parts[0] != "PEMIRA" {
-		return "", "", ErrQRInvalid
+func (s *CheckinService) parseQRPayload(payload string) (*checkinQR, error) {
+	// Format: "PEMIRA|TPS01|c9423e5f97d4" (statis) atau
+	// "PEMIRA|TPS01|<window>|<code>" (dinamis)
+	return parseCheckinQR(payload)
+}
+
+// findCheckinQR loads the active QR of the TPS in payload and checks the
+// payload against it.
+func (s *CheckinService) findCheckinQR(ctx context.Context, tx pgx.Tx, payload *checkinQR) (*TPSQR, error) {
+	if !payload.Rotating {
+		if s.rotatingQR != nil && !s.rotatingQR.StaticAllowed {
+			return nil, ErrQRStaticDisabled
+		}
+		return s.findActiveQRByCodeAndSecret(ctx, tx, payload.TPSCode, payload.Secret)
+	}
+
+	if s.rotatingQR == nil {
+		return nil, ErrQRInvalid
 	}
-	return parts[1], parts[2], nil
+	qr, err := s.findActiveQRByCode(ctx, tx, payload.TPSCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rotatingQR.Verify(payload, qr.QRToken, time.Now()); err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+func (s *CheckinService) findActiveQRByCode(ctx context.Context, tx pgx.Tx, tpsCode string) (*TPSQR, error) {
+	query := `
+		SELECT qr.id, qr.tps_id, qr.qr_secret_suffix, qr.is_active, qr.revoked_at, qr.created_at
+		FROM tps_qr qr
+		JOIN tps t ON t.id = qr.tps_id
+		WHERE t.code = $1 AND qr.is_active = true
+		ORDER BY qr.created_at DESC
+		LIMIT 1
+	`
+
+	var qr TPSQR
+	err := tx.QueryRow(ctx, query, tpsCode).Scan(
+		&qr.ID, &qr.TPSID, &qr.QRToken, &qr.IsActive, &qr.RotatedAt, &qr.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrQRInvalid
+		}
+		return nil, err
+	}
+
+	return &qr, nil
+}
+
+// useQRWindow records the rotating code window used by the voter, rejecting
+// a code the voter already used (or one older than it).
+func (s *CheckinService) useQRWindow(ctx context.Context, tx pgx.Tx, tpsID, voterID, window int64) error {
+	query := `
+		INSERT INTO tps_qr_window_uses (tps_id, voter_id, last_window, used_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (tps_id, voter_id) DO UPDATE
+		SET last_window = EXCLUDED.last_window, used_at = EXCLUDED.used_at
+		WHERE tps_qr_window_uses.last_window < EXCLUDED.last_window
+	`
+
+	tag, err := tx.Exec(ctx, query, tpsID, voterID, window)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrQRReplayed
+	}
+	return nil
 }
 
 func (s *CheckinService) findActiveQRByCodeAndSecret(ctx context.Context, tx pgx.Tx, tpsCode, secret string) (*TPSQR, error) {
@@ -404,6 +604,32 @@ type Election struct {
 	Status string
 }
 
+// ensureVotingOpen checks that the TPS accepts check-ins: while the election
+// is open, or after it closed when the TPS holds an unexpired voting extension.
+func (s *CheckinService) ensureVotingOpen(ctx context.Context, tx pgx.Tx, election *Election, tpsID int64) error {
+	if election.Status == "VOTING_OPEN" {
+		return nil
+	}
+	if election.Status != "VOTING_CLOSED" && election.Status != "CLOSED" {
+		return ErrElectionNotOpen
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM voting_extensions
+			WHERE election_id = $1 AND tps_id = $2 AND voting_end_at > NOW()
+		)
+	`
+	var open bool
+	if err := tx.QueryRow(ctx, query, election.ID, tpsID).Scan(&open); err != nil {
+		return err
+	}
+	if !open {
+		return ErrElectionNotOpen
+	}
+	return nil
+}
+
 func (s *CheckinService) getElectionByID(ctx context.Context, tx pgx.Tx, id int64) (*Election, error) {
 	query := `SELECT id, status FROM elections WHERE id = $1`
 
@@ -498,6 +724,20 @@ func (s *CheckinService) getPendingCheckin(ctx context.Context, tx pgx.Tx, voter
 	return &checkin, nil
 }
 
+// getActiveCheckin reports whether the voter holds an approved or used check-in.
+func (s *CheckinService) getActiveCheckin(ctx context.Context, tx pgx.Tx, voterID, electionID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM tps_checkins
+			WHERE voter_id = $1 AND election_id = $2 AND status IN ('APPROVED','USED','VOTED')
+		)
+	`
+
+	var exists bool
+	err := tx.QueryRow(ctx, query, voterID, electionID).Scan(&exists)
+	return exists, err
+}
+
 func (s *CheckinService) insertCheckin(ctx context.Context, tx pgx.Tx, checkin *TPSCheckin) (int64, error) {
 	query := `
 		INSERT INTO tps_checkins (tps_id, voter_id, election_id, status, scan_at, created_at, updated_at)
@@ -533,6 +773,7 @@ func (s *CheckinService) getCheckinByID(ctx context.Context, tx pgx.Tx, id int64
 		       created_at, updated_at
 		FROM tps_checkins
 		WHERE id = $1
+		FOR UPDATE
 	`
 
 	var checkin TPSCheckin
@@ -574,6 +815,7 @@ func (s *CheckinService) updateCheckinToRejected(ctx context.Context, tx pgx.Tx,
 }
 
 type AuditLog struct {
+	ElectionID   *int64
 	ActorVoterID *int64
 	ActorUserID  *int64
 	Action       string
@@ -582,24 +824,23 @@ type AuditLog struct {
 	Metadata     map[string]interface{}
 }
 
-func (s *CheckinService) logAudit(ctx context.Context, tx pgx.Tx, log AuditLog) error {
-	// Simplified audit logging - adjust based on your audit table schema
-	query := `
-		INSERT INTO audit_logs (actor_voter_id, actor_user_id, action, entity_type, entity_id, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
-	`
-
-	metadataJSON := fmt.Sprintf("%v", log.Metadata) // Simple string representation, use json.Marshal in production
-
-	_, err := tx.Exec(ctx, query,
-		log.ActorVoterID, log.ActorUserID, log.Action, log.EntityType, log.EntityID, metadataJSON,
-	)
-
-	// Don't fail the transaction if audit fails
+// logAudit writes an audit entry inside tx.
+func logAudit(ctx context.Context, tx pgx.Tx, log AuditLog) error {
+	metadata := log.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
-		// Log error but don't return it
-		return nil
+		return err
 	}
 
-	return nil
+	query := `
+		INSERT INTO audit_logs (election_id, actor_voter_id, actor_user_id, action, entity_type, entity_id, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+	`
+	_, err = tx.Exec(ctx, query,
+		log.ElectionID, log.ActorVoterID, log.ActorUserID, log.Action, log.EntityType, log.EntityID, metadataJSON,
+	)
+	return err
 }
@@ -122,6 +122,65 @@ func TestCheckinScan_Success(t *testing.T) {
 	assert.Contains(t, result.Message, "menunggu verifikasi")
 }
 
+func TestCheckinScan_RotatingQRAuditedWithCheckin(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	service := NewCheckinService(pool)
+	rotating := NewRotatingQR(30*time.Second, 1, false)
+	service.SetRotatingQR(rotating)
+	ctx := context.Background()
+
+	electionID := createTestElection(t, pool)
+	tpsID, tpsCode := createTestTPS(t, pool, electionID)
+	_, qrSecret := createTestQR(t, pool, tpsID)
+	voterID := createTestVoter(t, pool, electionID)
+	countCheckins := func() int {
+		var n int
+		require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM tps_checkins WHERE voter_id = $1`, voterID).Scan(&n))
+		return n
+	}
+
+	// A check-in whose audit entry fails is not kept
+	_, err := pool.Exec(ctx, `
+		CREATE OR REPLACE FUNCTION test_reject_checkin_audit() RETURNS TRIGGER AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit unavailable';
+		END;
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER test_reject_checkin_audit BEFORE INSERT ON audit_logs
+			FOR EACH ROW WHEN (NEW.action = 'TPS_CHECKIN_CREATED')
+			EXECUTE FUNCTION test_reject_checkin_audit();
+	`)
+	require.NoError(t, err)
+	dropTrigger := func() {
+		_, _ = pool.Exec(ctx, `
+			DROP TRIGGER IF EXISTS test_reject_checkin_audit ON audit_logs;
+			DROP FUNCTION IF EXISTS test_reject_checkin_audit();
+		`)
+	}
+	defer dropTrigger()
+
+	payload := rotating.Payload(tpsID, tpsCode, qrSecret, time.Now()).Payload
+	_, err = service.CheckinScan(ctx, voterID, payload)
+	assert.Error(t, err)
+	assert.Equal(t, 0, countCheckins())
+	dropTrigger()
+
+	// Otherwise both commit, the audit entry on the election's chain
+	result, err := service.CheckinScan(ctx, voterID, payload)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countCheckins())
+
+	var auditElectionID int64
+	err = pool.QueryRow(ctx, `
+		SELECT election_id FROM audit_logs
+		WHERE action = 'TPS_CHECKIN_CREATED' AND entity_id = $1
+	`, result.CheckinID).Scan(&auditElectionID)
+	require.NoError(t, err)
+	assert.Equal(t, electionID, auditElectionID)
+}
+
 func TestCheckinScan_InvalidQR(t *testing.T) {
 	pool := setupTestDB(t)
 	defer pool.Close()
@@ -328,11 +387,20 @@ func TestRejectCheckin_Success(t *testing.T) {
 func TestParseQRPayload_Valid(t *testing.T) {
 	service := &CheckinService{}
 
-	tpsCode, qrSecret, err := service.parseQRPayload("PEMIRA|TPS01|abc123")
+	qr, err := service.parseQRPayload("PEMIRA|TPS01|abc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TPS01", qr.TPSCode)
+	assert.Equal(t, "abc123", qr.Secret)
+	assert.False(t, qr.Rotating)
+
+	qr, err = service.parseQRPayload("PEMIRA|TPS01|57766667|0a1b2c3d4e5f6071")
 
 	assert.NoError(t, err)
-	assert.Equal(t, "TPS01", tpsCode)
-	assert.Equal(t, "abc123", qrSecret)
+	assert.Equal(t, "TPS01", qr.TPSCode)
+	assert.Equal(t, int64(57766667), qr.Window)
+	assert.Equal(t, "0a1b2c3d4e5f6071", qr.Code)
+	assert.True(t, qr.Rotating)
 }
 
 func TestParseQRPayload_Invalid(t *testing.T) {
@@ -346,11 +414,12 @@ func TestParseQRPayload_Invalid(t *testing.T) {
 		{"Missing parts", "PEMIRA|TPS01"},
 		{"Empty", ""},
 		{"Single pipe", "PEMIRA|"},
+		{"Rotating without window", "PEMIRA|TPS01|now|0a1b2c3d4e5f6071"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, _, err := service.parseQRPayload(tt.payload)
+			_, err := service.parseQRPayload(tt.payload)
 			assert.Error(t, err)
 			assert.Equal(t, ErrQRInvalid, err)
 		})
@@ -74,6 +74,9 @@ func (m *mockRepository) RotateQR(ctx context.Context, tpsID int64) (*tps.QRInfo
 func (m *mockRepository) GetQRPrintPayload(ctx context.Context, tpsID int64) (string, error) {
 	return "PEMIRA|TPS01|token", nil
 }
+func (m *mockRepository) CreateRotatingCheckin(ctx context.Context, checkin *tps.TPSCheckin, window int64) (bool, error) {
+	return true, m.CreateCheckin(ctx, checkin)
+}
 func (m *mockRepository) AssignPanitia(ctx context.Context, tpsID int64, members []tps.TPSPanitia) error {
 	return nil
 }
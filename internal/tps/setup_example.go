@@ -5,32 +5,30 @@ import (
 	"log"
 
 	"github.com/go-chi/chi/v5"
+
+	"pemira-api/internal/ws"
 )
 
 // SetupTPSModule initializes TPS module with all dependencies
 // This is an example - adjust to your application structure
-func SetupTPSModule(db *sql.DB, router chi.Router) (*ServiceWithWebSocket, *WSHandler) {
+func SetupTPSModule(db *sql.DB, router chi.Router, hub *ws.Hub) *Service {
 	// Create repository
 	repo := NewPostgresRepository(db)
 
-	// Create WebSocket hub
-	wsHub := NewWSHub()
-	go wsHub.Run() // Start hub in background
-
-	// Create service with WebSocket support
-	service := NewServiceWithWebSocket(repo, wsHub)
+	// Create service; queue updates are published to the shared websocket hub
+	// (the hub is served by ws.Handler on /ws/tps:{id}:queue)
+	service := NewService(repo)
+	service.SetEventPublisher(hub)
 
 	// Create HTTP handlers
-	httpHandler := NewHandler(service.Service)
-	wsHandler := NewWSHandler(wsHub, service.Service)
+	httpHandler := NewHandler(service)
 
 	// Register routes
 	httpHandler.RegisterRoutes(router)
-	wsHandler.RegisterRoutes(router)
 
 	log.Println("TPS module initialized successfully")
 
-	return service, wsHandler
+	return service
 }
 
 // Example usage in main.go or router setup:
@@ -39,50 +37,16 @@ func main() {
 	// ... setup database, router, etc
 
 	// Setup TPS module
-	tpsService, tpsWSHandler := tps.SetupTPSModule(db, router)
-
-	// TPS service is now available for use by other modules
-	// Example: Voting module can use tpsService.MarkCheckinAsUsed()
+	hub := ws.NewHub()
+	go hub.Run(ctx)
+	tpsService := tps.SetupTPSModule(db, router, hub)
 
 	// Start HTTP server
 	http.ListenAndServe(":8080", router)
 }
 */
 
-// Example integration with Voting module:
-/*
-// In voting module service
-type VotingService struct {
-	tpsService *tps.ServiceWithWebSocket
-}
-
-func (v *VotingService) CastTPSVote(ctx context.Context, voterID, electionID int64, ballot Ballot) error {
-	// 1. Validate check-in
-	checkin, err := v.tpsService.repo.GetCheckinByVoter(ctx, voterID, electionID)
-	if err != nil || checkin == nil {
-		return errors.New("no active check-in found")
-	}
-
-	if checkin.Status != tps.CheckinStatusApproved {
-		return errors.New("check-in not approved")
-	}
-
-	if time.Now().After(*checkin.ExpiresAt) {
-		return tps.ErrCheckinExpired
-	}
-
-	// 2. Process vote
-	err = v.processVote(ctx, voterID, electionID, ballot)
-	if err != nil {
-		return err
-	}
-
-	// 3. Mark check-in as used
-	err = v.tpsService.MarkCheckinAsUsed(ctx, checkin.ID)
-	if err != nil {
-		log.Printf("Failed to mark check-in as used: %v", err)
-	}
-
-	return nil
-}
-*/
+// Integration with Voting module:
+// voting.Service marks the check-in VOTED when the vote is cast and, with
+// votingService.SetEventPublisher(hub), publishes checkin.updated and
+// vote.cast to the same TPS queue channel.
@@ -0,0 +1,244 @@
+package tps
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"pemira-api/internal/ws"
+)
+
+// sweeperLockKey is the advisory lock key held by the replica running a
+// sweep, so only one API instance expires check-ins at a time.
+const sweeperLockKey int64 = 0x5357454550 // "SWEEP"
+
+// sweepBatchSize caps the check-ins expired per transaction; an election with
+// more due is swept in several.
+const sweepBatchSize = 100
+
+// sweepDue selects check-ins due to expire, given the pending ($1) and
+// approved ($2) TTLs in seconds.
+const sweepDue = `
+    ((status = 'PENDING' AND scan_at < NOW() - make_interval(secs => $1))
+  OR (status = 'APPROVED'
+      AND COALESCE(expires_at, COALESCE(approved_at, scan_at) + make_interval(secs => $2)) < NOW()))
+`
+
+// ExpiredCheckin is a check-in moved to EXPIRED by the sweeper.
+type ExpiredCheckin struct {
+	ID             int64
+	ElectionID     int64
+	TPSID          int64
+	VoterID        int64
+	PreviousStatus string
+}
+
+// Sweeper expires TPS check-ins nobody acted on: PENDING ones never approved
+// within pendingTTL of the scan, and APPROVED ones past their expires_at
+// (approvedTTL after approval when unset) without a vote. Every replica may
+// run one; an advisory lock makes sure a tick runs on one only.
+type Sweeper struct {
+	db          *pgxpool.Pool
+	interval    time.Duration
+	pendingTTL  time.Duration
+	approvedTTL time.Duration
+	listener    CheckinListener
+	events      ws.Publisher
+	logger      *slog.Logger
+}
+
+func NewSweeper(db *pgxpool.Pool, interval, pendingTTL, approvedTTL time.Duration, logger *slog.Logger) *Sweeper {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Sweeper{
+		db:          db,
+		interval:    interval,
+		pendingTTL:  pendingTTL,
+		approvedTTL: approvedTTL,
+		logger:      logger,
+	}
+}
+
+// SetCheckinListener sets the listener told about expired check-ins.
+func (s *Sweeper) SetCheckinListener(l CheckinListener) {
+	s.listener = l
+}
+
+// SetEventPublisher sets where checkin.updated events are published.
+func (s *Sweeper) SetEventPublisher(p ws.Publisher) {
+	s.events = p
+}
+
+// Run ticks until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("checkin sweeper started",
+		"interval", s.interval.String(),
+		"pending_ttl", s.pendingTTL.String(),
+		"approved_ttl", s.approvedTTL.String(),
+	)
+	for {
+		if _, err := s.Tick(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Error("checkin sweeper tick failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("checkin sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick expires due check-ins election by election, in transactions of at
+// most sweepBatchSize check-ins, each writing an audit entry per check-in and,
+// once committed, publishing checkin.updated for them. It does nothing when
+// another replica holds the sweeper lock.
+func (s *Sweeper) Tick(ctx context.Context) ([]ExpiredCheckin, error) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	// The lock spans the tick's transactions, so it is held by the session
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, sweeperLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+	defer func() {
+		unlockCtx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, sweeperLockKey); err != nil {
+			// Closing the session is the only other way to drop the lock
+			s.logger.Error("failed to release checkin sweeper lock", "error", err)
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	rows, err := conn.Query(ctx, `SELECT DISTINCT election_id FROM tps_checkins WHERE `+sweepDue,
+		s.pendingTTL.Seconds(), s.approvedTTL.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	electionIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []ExpiredCheckin
+	for _, electionID := range electionIDs {
+		for {
+			batch, err := s.sweepBatch(ctx, conn, electionID)
+			if err != nil {
+				return expired, err
+			}
+			s.publishExpired(batch)
+			expired = append(expired, batch...)
+			if len(batch) < sweepBatchSize {
+				break
+			}
+		}
+	}
+
+	if len(expired) > 0 {
+		s.logger.Info("tps check-ins expired", "count", len(expired), "elections", len(electionIDs))
+	}
+	return expired, nil
+}
+
+// sweepBatch expires up to sweepBatchSize due check-ins of an election in one
+// transaction.
+func (s *Sweeper) sweepBatch(ctx context.Context, conn *pgxpool.Conn, electionID int64) ([]ExpiredCheckin, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Rows locked by an approval or a vote in flight are skipped and
+	// reconsidered on the next tick.
+	rows, err := tx.Query(ctx, `
+WITH due AS (
+    SELECT id, status
+    FROM tps_checkins
+    WHERE election_id = $3 AND `+sweepDue+`
+    ORDER BY id
+    LIMIT $4
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE tps_checkins c
+SET status = 'EXPIRED', expired_at = NOW(), updated_at = NOW()
+FROM due
+WHERE c.id = due.id
+RETURNING c.id, c.election_id, c.tps_id, c.voter_id, due.status::text
+`, s.pendingTTL.Seconds(), s.approvedTTL.Seconds(), electionID, sweepBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	expired, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (ExpiredCheckin, error) {
+		var e ExpiredCheckin
+		err := row.Scan(&e.ID, &e.ElectionID, &e.TPSID, &e.VoterID, &e.PreviousStatus)
+		return e, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range expired {
+		if err := logAudit(ctx, tx, AuditLog{
+			ElectionID: &e.ElectionID,
+			Action:     "TPS_CHECKIN_EXPIRED",
+			EntityType: "TPS_CHECKIN",
+			EntityID:   e.ID,
+			Metadata: map[string]interface{}{
+				"tps_id":          e.TPSID,
+				"voter_id":        e.VoterID,
+				"previous_status": e.PreviousStatus,
+				"reason":          expiryReason(e.PreviousStatus),
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// publishExpired tells the listener and the panels about committed expiries.
+func (s *Sweeper) publishExpired(expired []ExpiredCheckin) {
+	now := time.Now()
+	for _, e := range expired {
+		if s.listener != nil {
+			s.listener.CheckinUpdated(e.ElectionID, e.TPSID)
+		}
+		ws.PublishCheckin(s.events, ws.EventCheckinUpdated, ws.CheckinEvent{
+			CheckinID:  e.ID,
+			ElectionID: e.ElectionID,
+			TPSID:      e.TPSID,
+			Status:     CheckinStatusExpired,
+			Reason:     expiryReason(e.PreviousStatus),
+			At:         now,
+		})
+	}
+}
+
+// expiryReason explains to the panel why a check-in expired.
+func expiryReason(previousStatus string) string {
+	if previousStatus == CheckinStatusPending {
+		return "Check-in tidak disetujui operator dalam batas waktu."
+	}
+	return "Check-in disetujui tetapi pemilih tidak memberikan suara dalam batas waktu."
+}
@@ -0,0 +1,118 @@
+package tps
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSweepCheckin(t *testing.T, pool *pgxpool.Pool, electionID, tpsID int64, n int, status string, scanAt time.Time, expiresAt *time.Time) int64 {
+	var voterID int64
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO voters (election_id, nim, name, faculty, is_eligible, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, true, NOW(), NOW())
+		RETURNING id
+	`, electionID, fmt.Sprintf("20250000%02d", n), "Test Voter", "Teknik").Scan(&voterID)
+	require.NoError(t, err)
+
+	var approvedAt *time.Time
+	if status != CheckinStatusPending {
+		approvedAt = &scanAt
+	}
+	var id int64
+	err = pool.QueryRow(context.Background(), `
+		INSERT INTO tps_checkins (tps_id, voter_id, election_id, status, scan_at, approved_at, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id
+	`, tpsID, voterID, electionID, status, scanAt, approvedAt, expiresAt).Scan(&id)
+	require.NoError(t, err)
+	return id
+}
+
+func checkinStatus(t *testing.T, pool *pgxpool.Pool, id int64) string {
+	var status string
+	err := pool.QueryRow(context.Background(), `SELECT status::text FROM tps_checkins WHERE id = $1`, id).Scan(&status)
+	require.NoError(t, err)
+	return status
+}
+
+func TestSweeperTick_ExpiresDueCheckins(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	ctx := context.Background()
+	electionID := createTestElection(t, pool)
+	tpsID, _ := createTestTPS(t, pool, electionID)
+
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	valid := now.Add(10 * time.Minute)
+	stalePending := createSweepCheckin(t, pool, electionID, tpsID, 1, CheckinStatusPending, now.Add(-time.Hour), nil)
+	freshPending := createSweepCheckin(t, pool, electionID, tpsID, 2, CheckinStatusPending, now.Add(-time.Minute), nil)
+	staleApproved := createSweepCheckin(t, pool, electionID, tpsID, 3, CheckinStatusApproved, now.Add(-20*time.Minute), &expired)
+	freshApproved := createSweepCheckin(t, pool, electionID, tpsID, 4, CheckinStatusApproved, now.Add(-20*time.Minute), &valid)
+	used := createSweepCheckin(t, pool, electionID, tpsID, 5, CheckinStatusUsed, now.Add(-2*time.Hour), &expired)
+
+	sweeper := NewSweeper(pool, time.Minute, 30*time.Minute, 15*time.Minute, nil)
+	result, err := sweeper.Tick(ctx)
+	require.NoError(t, err)
+
+	ids := map[int64]string{}
+	for _, e := range result {
+		ids[e.ID] = e.PreviousStatus
+	}
+	assert.Equal(t, map[int64]string{stalePending: CheckinStatusPending, staleApproved: CheckinStatusApproved}, ids)
+
+	assert.Equal(t, CheckinStatusExpired, checkinStatus(t, pool, stalePending))
+	assert.Equal(t, CheckinStatusExpired, checkinStatus(t, pool, staleApproved))
+	assert.Equal(t, CheckinStatusPending, checkinStatus(t, pool, freshPending))
+	assert.Equal(t, CheckinStatusApproved, checkinStatus(t, pool, freshApproved))
+	assert.Equal(t, CheckinStatusUsed, checkinStatus(t, pool, used))
+
+	var audited int
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM audit_logs
+		WHERE action = 'TPS_CHECKIN_EXPIRED' AND election_id = $1 AND entity_id = ANY($2)
+	`, electionID, []int64{stalePending, staleApproved}).Scan(&audited)
+	require.NoError(t, err)
+	assert.Equal(t, 2, audited)
+
+	// Nothing is left to expire
+	result, err = sweeper.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestSweeperTick_LockHeld(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	ctx := context.Background()
+	electionID := createTestElection(t, pool)
+	tpsID, _ := createTestTPS(t, pool, electionID)
+	stalePending := createSweepCheckin(t, pool, electionID, tpsID, 1, CheckinStatusPending, time.Now().Add(-time.Hour), nil)
+
+	// Another replica is sweeping
+	other, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	defer other.Release()
+	_, err = other.Exec(ctx, `SELECT pg_advisory_lock($1)`, sweeperLockKey)
+	require.NoError(t, err)
+
+	sweeper := NewSweeper(pool, time.Minute, 30*time.Minute, 15*time.Minute, nil)
+	result, err := sweeper.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result)
+	assert.Equal(t, CheckinStatusPending, checkinStatus(t, pool, stalePending))
+
+	_, err = other.Exec(ctx, `SELECT pg_advisory_unlock($1)`, sweeperLockKey)
+	require.NoError(t, err)
+	result, err = sweeper.Tick(ctx)
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+}
@@ -2,11 +2,17 @@ package voting
 
 import (
 	"context"
+	"encoding/json"
 	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"pemira-api/internal/shared/ctxkeys"
 )
 
 // AuditEntry represents an audit log entry
 type AuditEntry struct {
+	ElectionID   *int64         `json:"election_id"`
 	ActorVoterID *int64         `json:"actor_voter_id"`
 	ActorUserID  *int64         `json:"actor_user_id"`
 	Action       string         `json:"action"`
@@ -16,20 +22,60 @@ type AuditEntry struct {
 	CreatedAt    time.Time      `json:"created_at"`
 }
 
-// AuditService handles audit logging
+// AuditService handles audit logging.
+// Log runs inside the caller's transaction so the audit row commits
+// (or rolls back) together with the action it describes.
 type AuditService interface {
-	Log(ctx context.Context, entry AuditEntry) error
+	Log(ctx context.Context, tx pgx.Tx, entry AuditEntry) error
 }
 
-// Simple audit service implementation
 type auditService struct{}
 
+// NewAuditService returns an AuditService that writes to audit_logs.
+// Client IP and user agent are taken from the request context (see middleware.ClientInfo).
 func NewAuditService() AuditService {
 	return &auditService{}
 }
 
-func (s *auditService) Log(ctx context.Context, entry AuditEntry) error {
-	// For now, this is a no-op or could log to stdout
-	// In production, this would write to audit_logs table or send to queue
-	return nil
+func (s *auditService) Log(ctx context.Context, tx pgx.Tx, entry AuditEntry) error {
+	metadata := entry.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+
+	var ipAddress, userAgent *string
+	if ip, ok := ctxkeys.GetIPAddress(ctx); ok && ip != "" {
+		ipAddress = &ip
+	}
+	if ua, ok := ctxkeys.GetUserAgent(ctx); ok && ua != "" {
+		userAgent = &ua
+	}
+
+	_, err = tx.Exec(ctx, `
+INSERT INTO audit_logs (
+    election_id, actor_voter_id, actor_user_id, action, entity_type, entity_id,
+    metadata, ip_address, user_agent, created_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`,
+		entry.ElectionID,
+		entry.ActorVoterID,
+		entry.ActorUserID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		metaJSON,
+		ipAddress,
+		userAgent,
+		createdAt,
+	)
+	return err
 }
@@ -1,14 +1,32 @@
 package voting
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// abstainBallotCode is the candidate part of the abstain ballot QR
+// (PEMIRA-UNIWA|E:<election>|C:ABSTAIN|V:<version>|S:<signature>).
+const abstainBallotCode = "ABSTAIN"
+
+// Printed ballot QR payloads are signed:
+//
+//	PEMIRA-UNIWA|E:<election>|C:<candidate>|V:<version>|S:<signature>
+//
+// The signature is base64url HMAC-SHA256 of the payload before "|S:", keyed
+// with the ballot QR key of the election and version (see ballotQRKey).
+// Rotating issues a new version and so a new key; codes of older versions
+// are deactivated and stop verifying.
 type BallotQR struct {
 	ElectionID  int64
-	CandidateID int64
+	CandidateID int64 // 0 when Abstain
+	Abstain     bool
 	Version     int
+	Signature   string
 }
 
 func parseBallotQR(raw string) (*BallotQR, error) {
@@ -20,7 +38,9 @@ func parseBallotQR(raw string) (*BallotQR, error) {
 	var (
 		electionID  int64
 		candidateID int64
+		abstain     bool
 		version     int
+		signature   string
 	)
 
 	for _, p := range parts[1:] {
@@ -31,6 +51,8 @@ func parseBallotQR(raw string) (*BallotQR, error) {
 				return nil, ErrInvalidBallotQR
 			}
 			electionID = id
+		} else if p == "C:"+abstainBallotCode {
+			abstain = true
 		} else if strings.HasPrefix(p, "C:") {
 			val := strings.TrimPrefix(p, "C:")
 			id, err := strconv.ParseInt(val, 10, 64)
@@ -45,16 +67,61 @@ func parseBallotQR(raw string) (*BallotQR, error) {
 				return nil, ErrInvalidBallotQR
 			}
 			version = v
+		} else if strings.HasPrefix(p, "S:") {
+			signature = strings.TrimPrefix(p, "S:")
 		}
 	}
 
-	if electionID == 0 || candidateID == 0 || version == 0 {
+	if electionID == 0 || version == 0 {
+		return nil, ErrInvalidBallotQR
+	}
+	// Exactly one of a candidate ID or the abstain code
+	if (candidateID == 0 && !abstain) || (candidateID != 0 && abstain) {
 		return nil, ErrInvalidBallotQR
 	}
 
 	return &BallotQR{
 		ElectionID:  electionID,
 		CandidateID: candidateID,
+		Abstain:     abstain,
 		Version:     version,
+		Signature:   signature,
 	}, nil
 }
+
+// unsigned is the signed part of the payload.
+func (q *BallotQR) unsigned() string {
+	candidate := strconv.FormatInt(q.CandidateID, 10)
+	if q.Abstain {
+		candidate = abstainBallotCode
+	}
+	return fmt.Sprintf("PEMIRA-UNIWA|E:%d|C:%s|V:%d", q.ElectionID, candidate, q.Version)
+}
+
+// ballotQRKey derives the signing key of an election's ballot QR version from
+// the server secret, so no key is ever stored: HMAC-SHA256(secret,
+// "<election>|<version>").
+func ballotQRKey(secret []byte, electionID int64, version int) []byte {
+	return ballotQRMAC(secret, fmt.Sprintf("%d|%d", electionID, version))
+}
+
+func ballotQRMAC(key []byte, unsigned string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+// signBallotQR returns the printed payload of q signed with key.
+func signBallotQR(key []byte, q *BallotQR) string {
+	unsigned := q.unsigned()
+	return unsigned + "|S:" + base64.RawURLEncoding.EncodeToString(ballotQRMAC(key, unsigned))
+}
+
+// verify reports whether q carries a valid signature made with key.
+func (q *BallotQR) verify(key []byte) bool {
+	signature, err := base64.RawURLEncoding.DecodeString(q.Signature)
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+	return hmac.Equal(signature, ballotQRMAC(key, q.unsigned()))
+}
@@ -0,0 +1,66 @@
+package voting
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBallotQRSignature(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	for _, issued := range []BallotQR{
+		{ElectionID: 1, CandidateID: 3, Version: 2},
+		{ElectionID: 1, Abstain: true, Version: 2},
+	} {
+		payload := signBallotQR(key, &issued)
+		qr, err := parseBallotQR(payload)
+		if err != nil {
+			t.Fatalf("parse %q: %v", payload, err)
+		}
+		if !qr.verify(key) {
+			t.Errorf("%q does not verify", payload)
+		}
+		if qr.verify([]byte("rotated key")) {
+			t.Errorf("%q verifies with another key", payload)
+		}
+	}
+
+	signed := signBallotQR(key, &BallotQR{ElectionID: 1, CandidateID: 3, Version: 2})
+	signature := signed[strings.Index(signed, "|S:"):]
+	for _, forged := range []string{
+		"PEMIRA-UNIWA|E:1|C:3|V:2",
+		"PEMIRA-UNIWA|E:1|C:4|V:2" + signature,
+		"PEMIRA-UNIWA|E:1|C:3|V:3" + signature,
+		"PEMIRA-UNIWA|E:1|C:ABSTAIN|V:2" + signature,
+	} {
+		qr, err := parseBallotQR(forged)
+		if err != nil {
+			t.Fatalf("parse %q: %v", forged, err)
+		}
+		if qr.verify(key) {
+			t.Errorf("forged %q verifies", forged)
+		}
+	}
+}
+
+func TestBallotQRKeyPerVersion(t *testing.T) {
+	secret := []byte("server secret")
+	issued := BallotQR{ElectionID: 1, CandidateID: 3, Version: 2}
+	qr, err := parseBallotQR(signBallotQR(ballotQRKey(secret, 1, 2), &issued))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !qr.verify(ballotQRKey(secret, 1, 2)) {
+		t.Error("code does not verify with the key of its version")
+	}
+	for name, key := range map[string][]byte{
+		"other version":  ballotQRKey(secret, 1, 3),
+		"other election": ballotQRKey(secret, 2, 2),
+		"other secret":   ballotQRKey([]byte("rotated secret"), 1, 2),
+	} {
+		if qr.verify(key) {
+			t.Errorf("code verifies with the key of %s", name)
+		}
+	}
+}
@@ -1,21 +1,70 @@
 package voting
 
-import "time"
+import (
+	"time"
+
+	"pemira-api/internal/election"
+)
 
 // Request DTOs
 type CastVoteRequest struct {
 	CandidateID int64 `json:"candidate_id" validate:"required,min=1"`
 }
 
+// BallotChoice is the voter's pick in one race. RaceID is nil in elections
+// without races. Abstain choices carry no candidate.
+type BallotChoice struct {
+	RaceID      *int64 `json:"race_id,omitempty"`
+	CandidateID int64  `json:"candidate_id,omitempty"`
+	Abstain     bool   `json:"abstain,omitempty"`
+}
+
+// BallotRanking is the voter's ranking in one race of a ranked-choice (IRV)
+// election: candidate IDs in order of preference. RaceID is nil in elections
+// without races.
+type BallotRanking struct {
+	RaceID  *int64  `json:"race_id,omitempty"`
+	Ranking []int64 `json:"ranking"`
+}
+
+// CastOnlineVoteRequest carries either a single CandidateID or Abstain
+// (single-race elections) or one Choices entry per race the voter is eligible for.
+// Ranked-choice elections take Ranking (single race) or Rankings instead.
 type CastOnlineVoteRequest struct {
-	ElectionID  int64 `json:"election_id"`
-	CandidateID int64 `json:"candidate_id"`
+	ElectionID  int64           `json:"election_id"`
+	CandidateID int64           `json:"candidate_id"`
+	Abstain     bool            `json:"abstain,omitempty"`
+	Choices     []BallotChoice  `json:"choices,omitempty"`
+	Ranking     []int64         `json:"ranking,omitempty"`
+	Rankings    []BallotRanking `json:"rankings,omitempty"`
 }
 
 type CastTPSVoteRequest struct {
-	ElectionID  int64 `json:"election_id"`
-	CandidateID int64 `json:"candidate_id"`
-	TPSID       int64 `json:"tps_id"`
+	ElectionID  int64           `json:"election_id"`
+	CandidateID int64           `json:"candidate_id"`
+	Abstain     bool            `json:"abstain,omitempty"`
+	Choices     []BallotChoice  `json:"choices,omitempty"`
+	Ranking     []int64         `json:"ranking,omitempty"`
+	Rankings    []BallotRanking `json:"rankings,omitempty"`
+	TPSID       int64           `json:"tps_id"`
+}
+
+// ballotChoices returns the choices of a cast request, treating a bare
+// candidate_id or abstain flag as the single choice of a race-less ballot.
+func ballotChoices(candidateID int64, abstain bool, choices []BallotChoice) []BallotChoice {
+	if len(choices) > 0 {
+		return choices
+	}
+	return []BallotChoice{{CandidateID: candidateID, Abstain: abstain}}
+}
+
+// ballotRankings returns the rankings of a cast request, treating a bare
+// ranking as the single ranking of a race-less ballot.
+func ballotRankings(ranking []int64, rankings []BallotRanking) []BallotRanking {
+	if len(rankings) > 0 {
+		return rankings
+	}
+	return []BallotRanking{{Ranking: ranking}}
 }
 
 // QR-based TPS voting (offline device)
@@ -35,38 +84,72 @@ type ParseBallotQRResponse struct {
 	CandidateNumber   string  `json:"candidate_number"`
 	CandidateName     string  `json:"candidate_name"`
 	CandidateViceName *string `json:"candidate_vice_name,omitempty"`
+	Abstain           bool    `json:"abstain"`
 	Version           int     `json:"version"`
 }
 
+// BallotQRSheet is the printable set of active ballot QR codes of an election.
+type BallotQRSheet struct {
+	ElectionID int64              `json:"election_id"`
+	Version    int                `json:"version"`
+	Codes      []BallotQRSheetRow `json:"codes"`
+}
+
+type BallotQRSheetRow struct {
+	CandidateID     int64  `json:"candidate_id,omitempty"`
+	CandidateNumber string `json:"candidate_number,omitempty"`
+	CandidateName   string `json:"candidate_name,omitempty"`
+	Abstain         bool   `json:"abstain"`
+	Payload         string `json:"payload"`
+}
+
 // Response DTOs
 type VotingConfigResponse struct {
 	Election ElectionInfo `json:"election"`
 	Voter    VoterInfo    `json:"voter"`
 	Mode     VotingMode   `json:"mode"`
+	// Races lists the races on the voter's ballot (empty for single-race elections).
+	Races []BallotRace `json:"races,omitempty"`
+}
+
+// BallotRace is a race the voter must cast one choice for.
+type BallotRace struct {
+	ID   int64  `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
 }
 
 type ElectionInfo struct {
-	ID            int64     `json:"id"`
-	Code          string    `json:"code"`
-	Name          string    `json:"name"`
-	Status        string    `json:"status"`
-	VotingStartAt time.Time `json:"voting_start_at"`
-	VotingEndAt   time.Time `json:"voting_end_at"`
+	ID            int64      `json:"id"`
+	Code          string     `json:"code"`
+	Slug          string     `json:"slug"`
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	VotingStartAt *time.Time `json:"voting_start_at"`
+	VotingEndAt   *time.Time `json:"voting_end_at"`
 }
 
 type VoterInfo struct {
-	ID           int64      `json:"id"`
-	NIM          string     `json:"nim"`
-	Name         string     `json:"name"`
-	IsEligible   bool       `json:"is_eligible"`
-	HasVoted     bool       `json:"has_voted"`
-	VotingMethod *string    `json:"voting_method"`
-	VotedAt      *time.Time `json:"voted_at"`
+	ID              int64      `json:"id"`
+	NIM             string     `json:"nim"`
+	Name            string     `json:"name"`
+	IsEligible      bool       `json:"is_eligible"`
+	HasVoted        bool       `json:"has_voted"`
+	VotingMethod    *string    `json:"voting_method"`
+	PreferredMethod *string    `json:"preferred_method"`
+	OnlineAllowed   bool       `json:"online_allowed"`
+	TPSAllowed      bool       `json:"tps_allowed"`
+	TPSID           *int64     `json:"tps_id"`
+	VotedAt         *time.Time `json:"voted_at"`
 }
 
 type VotingMode struct {
-	OnlineEnabled bool `json:"online_enabled"`
-	TPSEnabled    bool `json:"tps_enabled"`
+	OnlineEnabled  bool                       `json:"online_enabled"`
+	TPSEnabled     bool                       `json:"tps_enabled"`
+	AbstainEnabled bool                       `json:"abstain_enabled"`
+	VotingSystem   string                     `json:"voting_system"`
+	OnlineSettings election.OnlineSettingsDTO `json:"online_settings"`
+	TPSSettings    election.TPSSettingsDTO    `json:"tps_settings"`
 }
 
 type VoteReceipt struct {
@@ -89,11 +172,31 @@ type TPSInfo struct {
 	Name string `json:"name"`
 }
 
+// TPS voting steps reported by GetTPSVotingStatus
+const (
+	TPSStepElectionNotOpen = "ELECTION_NOT_OPEN"
+	TPSStepNotTPSVoter     = "NOT_TPS_VOTER"
+	TPSStepAlreadyVoted    = "ALREADY_VOTED"
+	TPSStepCheckinRequired = "CHECKIN_REQUIRED"
+	TPSStepWaitingApproval = "WAITING_APPROVAL"
+	TPSStepCheckinRejected = "CHECKIN_REJECTED"
+	TPSStepCheckinExpired  = "CHECKIN_EXPIRED"
+	TPSStepReadyToVote     = "READY_TO_VOTE"
+)
+
 type TPSVotingStatus struct {
-	Eligible  bool       `json:"eligible"`
-	TPS       *TPSInfo   `json:"tps,omitempty"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
-	Reason    *string    `json:"reason,omitempty"`
+	Eligible         bool       `json:"eligible"`
+	Step             string     `json:"step"`
+	ElectionID       int64      `json:"election_id"`
+	RequireBallotQR  bool       `json:"require_ballot_qr"`
+	CheckinID        *int64     `json:"checkin_id,omitempty"`
+	CheckinStatus    *string    `json:"checkin_status,omitempty"`
+	TPS              *TPSInfo   `json:"tps,omitempty"`
+	ScanAt           *time.Time `json:"scan_at,omitempty"`
+	ApprovedAt       *time.Time `json:"approved_at,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	RemainingSeconds *int64     `json:"remaining_seconds,omitempty"`
+	Reason           *string    `json:"reason,omitempty"`
 }
 
 type ReceiptResponse struct {
@@ -105,6 +208,21 @@ type ReceiptResponse struct {
 	Receipt    *ReceiptDetail `json:"receipt,omitempty"`
 }
 
+type VerifyReceiptRequest struct {
+	TokenHash string `json:"token_hash"`
+}
+
+// ReceiptVerificationResponse confirms a receipt is counted without revealing the candidate.
+type ReceiptVerificationResponse struct {
+	TokenHash    string    `json:"token_hash"`
+	Counted      bool      `json:"counted"`
+	ElectionID   int64     `json:"election_id"`
+	ElectionName string    `json:"election_name"`
+	Channel      string    `json:"channel"`
+	TPS          *TPSInfo  `json:"tps,omitempty"`
+	CastAt       time.Time `json:"cast_at"`
+}
+
 type LiveCountResponse struct {
 	ElectionID int64           `json:"election_id"`
 	Counts     map[int64]int64 `json:"counts"`
@@ -117,6 +235,7 @@ type CastFromBallotQRResponse struct {
 	TPS        TPSInfo           `json:"tps"`
 	Status     string            `json:"status"`
 	Candidate  *CandidateSummary `json:"candidate,omitempty"`
+	Abstain    bool              `json:"abstain"`
 }
 
 type CandidateSummary struct {
@@ -125,3 +244,55 @@ type CandidateSummary struct {
 	Name     string  `json:"candidate_name"`
 	ViceName *string `json:"candidate_vice_name,omitempty"`
 }
+
+// TPS sync item types and statuses
+const (
+	SyncItemCheckin = "CHECKIN"
+	SyncItemBallot  = "BALLOT"
+
+	SyncStatusAccepted = "ACCEPTED"
+	SyncStatusRejected = "REJECTED"
+)
+
+// TPSSyncRequest is a batch of check-ins and ballot casts a TPS panel queued
+// while offline, in the order they happened.
+type TPSSyncRequest struct {
+	Items []TPSSyncItemRequest `json:"items"`
+}
+
+// TPSSyncItemRequest is one queued item. ClientID is generated by the panel
+// and identifies the item across uploads. CHECKIN items carry the voter;
+// BALLOT items reference their check-in by CheckinClientID (queued offline)
+// or CheckinID (made online) and carry the ballot like CastTPSVoteRequest.
+type TPSSyncItemRequest struct {
+	ClientID        string          `json:"client_id"`
+	Type            string          `json:"type"`
+	OccurredAt      time.Time       `json:"occurred_at"`
+	VoterID         int64           `json:"voter_id,omitempty"`
+	CheckinClientID string          `json:"checkin_client_id,omitempty"`
+	CheckinID       int64           `json:"checkin_id,omitempty"`
+	CandidateID     int64           `json:"candidate_id,omitempty"`
+	Abstain         bool            `json:"abstain,omitempty"`
+	Choices         []BallotChoice  `json:"choices,omitempty"`
+	Ranking         []int64         `json:"ranking,omitempty"`
+	Rankings        []BallotRanking `json:"rankings,omitempty"`
+}
+
+type TPSSyncResponse struct {
+	Accepted int                 `json:"accepted"`
+	Rejected int                 `json:"rejected"`
+	Results  []TPSSyncItemResult `json:"results"`
+}
+
+// TPSSyncItemResult is the outcome of one item. Duplicate is set when the
+// item was applied by an earlier upload; its stored outcome is returned.
+type TPSSyncItemResult struct {
+	ClientID  string `json:"client_id"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Duplicate bool   `json:"duplicate"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	VoterID   *int64 `json:"voter_id,omitempty"`
+	CheckinID *int64 `json:"checkin_id,omitempty"`
+}
@@ -0,0 +1,29 @@
+package voting
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckEnrollment(t *testing.T) {
+	for status, want := range map[string]error{
+		"VERIFIED": nil,
+		"PENDING":  ErrNotEligible,
+		"REJECTED": ErrNotEligible,
+		"BLOCKED":  ErrNotEligible,
+		"VOTED":    ErrAlreadyVoted,
+		"":         ErrNotEligible,
+	} {
+		err := checkEnrollment(&ElectionVoterEnrollment{Status: status, VoterType: "STUDENT"}, "STUDENT")
+		if !errors.Is(err, want) {
+			t.Errorf("status %q: got %v, want %v", status, err, want)
+		}
+	}
+
+	if err := checkEnrollment(&ElectionVoterEnrollment{Status: "VERIFIED", VoterType: "STAFF"}, "STUDENT"); !errors.Is(err, ErrNotEligible) {
+		t.Errorf("voter type mismatch: got %v, want ErrNotEligible", err)
+	}
+	if err := checkEnrollment(&ElectionVoterEnrollment{Status: "VERIFIED", VoterType: "STAFF"}, ""); err != nil {
+		t.Errorf("empty voter type skips the check: got %v", err)
+	}
+}
@@ -5,15 +5,42 @@ import "time"
 type Vote struct {
 	ID            int64     `json:"id"`
 	ElectionID    int64     `json:"election_id"`
-	CandidateID   int64     `json:"candidate_id"`
+	CandidateID   int64     `json:"candidate_id"` // 0 for abstain votes
+	RaceID        *int64    `json:"race_id,omitempty"`
+	IsAbstain     bool      `json:"is_abstain"`
 	TokenHash     string    `json:"token_hash"`
 	Channel       string    `json:"channel"` // "ONLINE" | "TPS"
 	TPSID         *int64    `json:"tps_id"`
+	VoterType     string    `json:"voter_type"` // constituency used for weighted tallies
 	CandidateQRID *int64    `json:"candidate_qr_id,omitempty"`
 	BallotScanID  *int64    `json:"ballot_scan_id,omitempty"`
 	CastAt        time.Time `json:"cast_at"`
 }
 
+// RankedBallot is a ballot of a ranked-choice (IRV) election: the candidates of
+// one race in order of preference, first preference first.
+type RankedBallot struct {
+	ID         int64     `json:"id"`
+	ElectionID int64     `json:"election_id"`
+	RaceID     *int64    `json:"race_id,omitempty"`
+	TokenHash  string    `json:"token_hash"`
+	Channel    string    `json:"channel"`
+	TPSID      *int64    `json:"tps_id"`
+	VoterType  string    `json:"voter_type"`
+	Ranking    []int64   `json:"ranking"`
+	CastAt     time.Time `json:"cast_at"`
+}
+
+// VoteReceiptRecord is the public view of a counted vote. It deliberately
+// carries no candidate information.
+type VoteReceiptRecord struct {
+	ElectionID   int64     `json:"election_id"`
+	ElectionName string    `json:"election_name"`
+	Channel      string    `json:"channel"`
+	TPSID        *int64    `json:"tps_id"`
+	CastAt       time.Time `json:"cast_at"`
+}
+
 type VoteToken struct {
 	ID         int64      `json:"id"`
 	ElectionID int64      `json:"election_id"`
@@ -42,6 +69,24 @@ type VoterStatusEntity struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
+// ElectionVoterEnrollment is a voter's row in election_voters joined with voters.voter_type.
+type ElectionVoterEnrollment struct {
+	ID           int64      `json:"id"`
+	ElectionID   int64      `json:"election_id"`
+	VoterID      int64      `json:"voter_id"`
+	VoterType    string     `json:"voter_type"` // "STUDENT" | "LECTURER" | "STAFF"
+	Status       string     `json:"status"`     // "PENDING" | "VERIFIED" | "REJECTED" | "VOTED" | "BLOCKED"
+	VotingMethod string     `json:"voting_method"`
+	TPSID        *int64     `json:"tps_id"`
+	VotedAt      *time.Time `json:"voted_at"`
+}
+
+type VoterIdentity struct {
+	ID   int64  `json:"id"`
+	NIM  string `json:"nim"`
+	Name string `json:"name"`
+}
+
 type VoteResultEntity struct {
 	ElectionID int64         `json:"election_id"`
 	VoterID    int64         `json:"voter_id"`
@@ -51,6 +96,7 @@ type VoteResultEntity struct {
 	Receipt    ReceiptDetail `json:"receipt"`
 }
 
+// CandidateQR is a printed ballot QR. CandidateID 0 marks the abstain QR.
 type CandidateQR struct {
 	ID          int64  `json:"id"`
 	ElectionID  int64  `json:"election_id"`
@@ -85,3 +131,20 @@ type VoterTPSQR struct {
 	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
 }
+
+// TPSSyncItem is the stored outcome of an item uploaded through the TPS sync
+// API, keyed by TPS and the client generated ID.
+type TPSSyncItem struct {
+	TPSID      int64     `json:"tps_id"`
+	ClientID   string    `json:"client_id"`
+	ElectionID int64     `json:"election_id"`
+	ItemType   string    `json:"item_type"`
+	Status     string    `json:"status"`
+	Reason     *string   `json:"reason,omitempty"`
+	Message    *string   `json:"message,omitempty"`
+	VoterID    *int64    `json:"voter_id,omitempty"`
+	CheckinID  *int64    `json:"checkin_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+	SyncedBy   *int64    `json:"synced_by,omitempty"`
+	SyncedAt   time.Time `json:"synced_at"`
+}
@@ -21,6 +21,29 @@ var (
 	ErrInvalidBallotQR       = errors.New("invalid ballot qr")
 	ErrElectionMismatch      = errors.New("election mismatch")
 	ErrModeNotAllowed        = errors.New("voting mode not available")
+	ErrInvalidReceipt        = errors.New("invalid receipt token")
+	ErrReceiptNotFound       = errors.New("receipt not found")
+	ErrBallotIncomplete      = errors.New("ballot must hold one choice per eligible race")
+	ErrRaceNotEligible       = errors.New("voter is not eligible for race")
+	ErrDuplicateRaceChoice   = errors.New("more than one choice for a race")
+	ErrMultiRaceBallotQR     = errors.New("ballot qr cannot be used in multi-race elections")
+	ErrAbstainNotAllowed     = errors.New("abstain is not enabled for this election")
+)
+
+// Ranked-choice (IRV) ballots
+var (
+	ErrRankingRequired          = errors.New("ranked-choice election requires a ranking")
+	ErrRankingNotAllowed        = errors.New("ranking is only accepted in ranked-choice elections")
+	ErrDuplicateRankedCandidate = errors.New("candidate ranked more than once")
+	ErrRankedBallotQR           = errors.New("ballot qr cannot be used in ranked-choice elections")
+)
+
+// Offline TPS sync
+var (
+	ErrInvalidSyncBatch        = errors.New("invalid tps sync batch")
+	ErrSyncCheckinMissing      = errors.New("sync ballot has no accepted check-in")
+	ErrSyncOutsideWindow       = errors.New("sync item occurred outside the voting window")
+	ErrSyncBallotBeforeCheckin = errors.New("sync ballot occurred before its check-in was approved")
 )
 
 func translateNotFound(err error, customErr error) error {
@@ -28,14 +28,59 @@ func NewVotingHandler(svc *Service) *Handler {
 
 // Request DTOs
 type onlineVoteRequest struct {
-	ElectionID  int64 `json:"election_id"`
-	CandidateID int64 `json:"candidate_id"`
+	ElectionID  int64           `json:"election_id"`
+	CandidateID int64           `json:"candidate_id"`
+	Abstain     bool            `json:"abstain"`
+	Choices     []BallotChoice  `json:"choices"`
+	Ranking     []int64         `json:"ranking"`
+	Rankings    []BallotRanking `json:"rankings"`
 }
 
 type tpsVoteRequest struct {
-	ElectionID  int64 `json:"election_id"`
-	CandidateID int64 `json:"candidate_id"`
-	TPSID       int64 `json:"tps_id"`
+	ElectionID  int64           `json:"election_id"`
+	CandidateID int64           `json:"candidate_id"`
+	Abstain     bool            `json:"abstain"`
+	Choices     []BallotChoice  `json:"choices"`
+	Ranking     []int64         `json:"ranking"`
+	Rankings    []BallotRanking `json:"rankings"`
+	TPSID       int64           `json:"tps_id"`
+}
+
+// validChoices reports whether the body holds either a candidate_id, an
+// abstain flag or a non-empty choices list where every choice names a
+// candidate or abstains.
+func validChoices(candidateID int64, abstain bool, choices []BallotChoice) bool {
+	if len(choices) == 0 {
+		return (candidateID > 0) != abstain
+	}
+	for _, c := range choices {
+		if (c.CandidateID > 0) == c.Abstain || (c.RaceID != nil && *c.RaceID <= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// validBallot accepts either a plurality ballot (see validChoices) or a
+// ranked ballot holding ranking or rankings, never both kinds.
+func validBallot(candidateID int64, abstain bool, choices []BallotChoice, ranking []int64, rankings []BallotRanking) bool {
+	if len(ranking) == 0 && len(rankings) == 0 {
+		return validChoices(candidateID, abstain, choices)
+	}
+	if candidateID != 0 || abstain || len(choices) > 0 || (len(ranking) > 0 && len(rankings) > 0) {
+		return false
+	}
+	for _, r := range ballotRankings(ranking, rankings) {
+		if len(r.Ranking) == 0 || (r.RaceID != nil && *r.RaceID <= 0) {
+			return false
+		}
+		for _, id := range r.Ranking {
+			if id <= 0 {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 type castBallotQRRequest struct {
@@ -57,9 +102,12 @@ type voterQRRequest struct {
 	ElectionID int64 `json:"election_id"`
 }
 
+// RegisterRoutes mounts the authenticated voting endpoints. The public
+// POST /voting/receipts/verify is mounted by the caller with its own rate limit.
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Post("/voting/online/cast", h.CastOnlineVote)
 	r.Post("/voting/tps/cast", h.CastTPSVote)
+	r.Get("/voting/config", h.GetVotingConfig)
 	r.Get("/voting/tps/status", h.GetTPSVotingStatus)
 	r.Get("/voting/receipt", h.GetVotingReceipt)
 	r.Post("/voting/method", h.SetVoterMethod)
@@ -91,8 +139,8 @@ func (h *Handler) CastOnlineVote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if reqBody.ElectionID <= 0 || reqBody.CandidateID <= 0 {
-		response.UnprocessableEntity(w, "VALIDATION_ERROR", "election_id dan candidate_id wajib diisi.")
+	if reqBody.ElectionID <= 0 || !validBallot(reqBody.CandidateID, reqBody.Abstain, reqBody.Choices, reqBody.Ranking, reqBody.Rankings) {
+		response.UnprocessableEntity(w, "VALIDATION_ERROR", "election_id dan candidate_id, abstain, choices, atau ranking wajib diisi.")
 		return
 	}
 
@@ -100,6 +148,10 @@ func (h *Handler) CastOnlineVote(w http.ResponseWriter, r *http.Request) {
 	req := CastOnlineVoteRequest{
 		ElectionID:  reqBody.ElectionID,
 		CandidateID: reqBody.CandidateID,
+		Abstain:     reqBody.Abstain,
+		Choices:     reqBody.Choices,
+		Ranking:     reqBody.Ranking,
+		Rankings:    reqBody.Rankings,
 	}
 
 	// Call service
@@ -132,8 +184,8 @@ func (h *Handler) CastTPSVote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if reqBody.ElectionID <= 0 || reqBody.CandidateID <= 0 || reqBody.TPSID <= 0 {
-		response.UnprocessableEntity(w, "VALIDATION_ERROR", "election_id, candidate_id, dan tps_id wajib diisi.")
+	if reqBody.ElectionID <= 0 || !validBallot(reqBody.CandidateID, reqBody.Abstain, reqBody.Choices, reqBody.Ranking, reqBody.Rankings) || reqBody.TPSID <= 0 {
+		response.UnprocessableEntity(w, "VALIDATION_ERROR", "election_id, tps_id, dan candidate_id, abstain, choices, atau ranking wajib diisi.")
 		return
 	}
 
@@ -141,6 +193,10 @@ func (h *Handler) CastTPSVote(w http.ResponseWriter, r *http.Request) {
 	req := CastTPSVoteRequest{
 		ElectionID:  reqBody.ElectionID,
 		CandidateID: reqBody.CandidateID,
+		Abstain:     reqBody.Abstain,
+		Choices:     reqBody.Choices,
+		Ranking:     reqBody.Ranking,
+		Rankings:    reqBody.Rankings,
 		TPSID:       reqBody.TPSID,
 	}
 
@@ -155,6 +211,35 @@ func (h *Handler) CastTPSVote(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GET /voting/config
+func (h *Handler) GetVotingConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authUser, ok := auth.FromContext(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "Token tidak valid.")
+		return
+	}
+
+	if authUser.VoterID == nil {
+		response.Forbidden(w, "VOTER_MAPPING_MISSING", "Akun ini belum terhubung dengan data pemilih.")
+		return
+	}
+
+	electionID, ok := parseOptionalElectionID(w, r)
+	if !ok {
+		return
+	}
+
+	cfg, err := h.service.GetVotingConfig(ctx, *authUser.VoterID, electionID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, cfg)
+}
+
 // GET /voting/tps/status
 func (h *Handler) GetTPSVotingStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -170,7 +255,12 @@ func (h *Handler) GetTPSVotingStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status, err := h.service.GetTPSVotingStatus(ctx, *authUser.VoterID)
+	electionID, ok := parseOptionalElectionID(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.service.GetTPSVotingStatus(ctx, *authUser.VoterID, electionID)
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -194,7 +284,12 @@ func (h *Handler) GetVotingReceipt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	receipt, err := h.service.GetVotingReceipt(ctx, *authUser.VoterID)
+	electionID, ok := parseOptionalElectionID(w, r)
+	if !ok {
+		return
+	}
+
+	receipt, err := h.service.GetVotingReceipt(ctx, *authUser.VoterID, electionID)
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -203,6 +298,53 @@ func (h *Handler) GetVotingReceipt(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, receipt)
 }
 
+// CastReceipt is what the idempotency middleware replays for a retried vote
+// cast: the voter's receipt (token hash and time), never their choice.
+func (h *Handler) CastReceipt(r *http.Request) (any, error) {
+	authUser, ok := auth.FromContext(r.Context())
+	if !ok {
+		return nil, ErrVoterMappingMissing
+	}
+	voterID, _, err := voterFromAuth(authUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody struct {
+		ElectionID int64 `json:"election_id"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&reqBody)
+	var electionID *int64
+	if reqBody.ElectionID > 0 {
+		electionID = &reqBody.ElectionID
+	}
+	return h.service.GetVotingReceipt(r.Context(), voterID, electionID)
+}
+
+// POST /voting/receipts/verify (public)
+func (h *Handler) VerifyReceipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var reqBody VerifyReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	if strings.TrimSpace(reqBody.TokenHash) == "" {
+		response.UnprocessableEntity(w, "VALIDATION_ERROR", "token_hash wajib diisi.")
+		return
+	}
+
+	result, err := h.service.VerifyReceipt(ctx, reqBody.TokenHash)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, result)
+}
+
 // POST /voting/method
 func (h *Handler) SetVoterMethod(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -417,6 +559,97 @@ func (h *Handler) GenerateVoterTPSQR(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, qr)
 }
 
+// GET /admin/elections/{electionID}/ballot-qr/sheet
+func (h *Handler) GetBallotQRSheet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	electionID, err := strconv.ParseInt(chi.URLParam(r, "electionID"), 10, 64)
+	if err != nil || electionID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	sheet, err := h.service.GetBallotQRSheet(ctx, electionID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	response.Success(w, http.StatusOK, sheet)
+}
+
+// POST /admin/elections/{electionID}/ballot-qr/rotate
+func (h *Handler) RotateBallotQR(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authUser, ok := auth.FromContext(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "Token tidak valid.")
+		return
+	}
+
+	electionID, err := strconv.ParseInt(chi.URLParam(r, "electionID"), 10, 64)
+	if err != nil || electionID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+
+	sheet, err := h.service.RotateBallotQR(ctx, authUser, electionID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	response.Success(w, http.StatusOK, sheet)
+}
+
+// SyncTPS applies a batch of check-ins and ballots a TPS panel queued offline.
+func (h *Handler) SyncTPS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authUser, ok := auth.FromContext(ctx)
+	if !ok {
+		response.Unauthorized(w, "UNAUTHORIZED", "Token tidak valid.")
+		return
+	}
+
+	electionID, err := strconv.ParseInt(chi.URLParam(r, "electionID"), 10, 64)
+	if err != nil || electionID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "electionID tidak valid.")
+		return
+	}
+	tpsID, err := strconv.ParseInt(chi.URLParam(r, "tpsID"), 10, 64)
+	if err != nil || tpsID <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "tpsID tidak valid.")
+		return
+	}
+
+	var req TPSSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "VALIDATION_ERROR", "Body tidak valid.")
+		return
+	}
+
+	result, err := h.service.SyncTPS(ctx, authUser, electionID, tpsID, req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	response.Success(w, http.StatusOK, result)
+}
+
+// parseOptionalElectionID reads the optional election_id query parameter.
+func parseOptionalElectionID(w http.ResponseWriter, r *http.Request) (*int64, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("election_id"))
+	if raw == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(w, "VALIDATION_ERROR", "election_id tidak valid.")
+		return nil, false
+	}
+	return &id, true
+}
+
 // handleError maps domain errors to HTTP responses
 func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	switch {
@@ -471,6 +704,42 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, ErrModeNotAllowed):
 		response.UnprocessableEntity(w, "MODE_NOT_AVAILABLE", "Mode voting tidak tersedia.")
 
+	case errors.Is(err, ErrInvalidReceipt):
+		response.BadRequest(w, "INVALID_RECEIPT", "Format kode tanda terima tidak valid.")
+
+	case errors.Is(err, ErrReceiptNotFound):
+		response.NotFound(w, "RECEIPT_NOT_FOUND", "Kode tanda terima tidak ditemukan pada suara yang tercatat.")
+
+	case errors.Is(err, ErrBallotIncomplete):
+		response.UnprocessableEntity(w, "BALLOT_INCOMPLETE", "Surat suara harus berisi tepat satu pilihan untuk setiap posisi yang dapat Anda pilih.")
+
+	case errors.Is(err, ErrRaceNotEligible):
+		response.Forbidden(w, "RACE_NOT_ELIGIBLE", "Anda tidak berhak memilih pada salah satu posisi yang dipilih.")
+
+	case errors.Is(err, ErrDuplicateRaceChoice):
+		response.UnprocessableEntity(w, "DUPLICATE_RACE_CHOICE", "Hanya boleh satu pilihan untuk setiap posisi.")
+
+	case errors.Is(err, ErrAbstainNotAllowed):
+		response.BadRequest(w, "ABSTAIN_NOT_ALLOWED", "Pemilu ini tidak menyediakan pilihan suara kosong (abstain).")
+
+	case errors.Is(err, ErrMultiRaceBallotQR):
+		response.BadRequest(w, "MULTI_RACE_BALLOT", "Pemilu ini memiliki beberapa posisi, gunakan surat suara lengkap.")
+
+	case errors.Is(err, ErrRankingRequired):
+		response.UnprocessableEntity(w, "RANKING_REQUIRED", "Pemilu ini memakai sistem ranked-choice, urutkan minimal satu kandidat di setiap posisi.")
+
+	case errors.Is(err, ErrRankingNotAllowed):
+		response.UnprocessableEntity(w, "RANKING_NOT_ALLOWED", "Pemilu ini tidak memakai sistem ranked-choice, pilih satu kandidat.")
+
+	case errors.Is(err, ErrDuplicateRankedCandidate):
+		response.UnprocessableEntity(w, "DUPLICATE_RANKED_CANDIDATE", "Setiap kandidat hanya boleh diurutkan satu kali.")
+
+	case errors.Is(err, ErrRankedBallotQR):
+		response.BadRequest(w, "RANKED_BALLOT", "Pemilu ini memakai sistem ranked-choice, gunakan surat suara urutan.")
+
+	case errors.Is(err, ErrInvalidSyncBatch):
+		response.BadRequest(w, "INVALID_SYNC_BATCH", "Batch sinkronisasi tidak valid: 1-500 item, masing-masing dengan client_id unik, type CHECKIN/BALLOT, dan occurred_at.")
+
 	case errors.Is(err, ErrDuplicateVoteAttempt):
 		response.Conflict(w, "DUPLICATE_VOTE_ATTEMPT", "Permintaan ini tidak dapat diproses karena suara Anda sudah tercatat.")
 
@@ -0,0 +1,176 @@
+package voting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"pemira-api/internal/election"
+	"pemira-api/internal/shared"
+)
+
+// isRanked reports whether the election counts by instant runoff.
+func (s *Service) isRanked(ctx context.Context, e *election.Election) (bool, error) {
+	settings, err := s.loadModeSettings(ctx, e)
+	if err != nil {
+		return false, err
+	}
+	return settings.VotingSystem == election.VotingSystemIRV, nil
+}
+
+// ensurePlurality rejects per-candidate ballot QR flows in ranked-choice
+// elections, since one QR can only carry a single candidate.
+func (s *Service) ensurePlurality(ctx context.Context, electionID int64) error {
+	e, err := s.electionRepo.GetByID(ctx, electionID)
+	if err != nil {
+		return translateNotFound(err, ErrElectionNotFound)
+	}
+	ranked, err := s.isRanked(ctx, e)
+	if err != nil {
+		return err
+	}
+	if ranked {
+		return ErrRankedBallotQR
+	}
+	return nil
+}
+
+// castRankedVote is the ranked-choice counterpart of castVote: it runs the same
+// eligibility checks and writes one ranked ballot per race under one token.
+func (s *Service) castRankedVote(
+	ctx context.Context,
+	electionID, voterID int64,
+	voterType string,
+	rankings []BallotRanking,
+	channel string,
+	tpsID *int64,
+) (*VoteResultEntity, error) {
+	var result *VoteResultEntity
+
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		result, err = s.castRankedVoteTx(ctx, tx, electionID, voterID, voterType, rankings, channel, tpsID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyVoteCast(electionID, channel, tpsID, result.VotedAt)
+	return result, nil
+}
+
+// castRankedVoteTx runs castRankedVote inside tx without notifying listeners.
+func (s *Service) castRankedVoteTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	electionID, voterID int64,
+	voterType string,
+	rankings []BallotRanking,
+	channel string,
+	tpsID *int64,
+) (*VoteResultEntity, error) {
+	enrollment, vs, err := s.lockVoterForCast(ctx, tx, electionID, voterID, voterType, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRankedBallot(ctx, tx, electionID, voterID, rankings); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	tokenHash, err := s.issueVoteToken(ctx, tx, electionID, voterID, channel, tpsID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rankings {
+		ballot := &RankedBallot{
+			ElectionID: electionID,
+			RaceID:     r.RaceID,
+			TokenHash:  tokenHash,
+			Channel:    channel,
+			TPSID:      tpsID,
+			VoterType:  enrollment.VoterType,
+			Ranking:    r.Ranking,
+			CastAt:     now,
+		}
+		if err := s.voteRepo.InsertRankedBallot(ctx, tx, ballot); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.completeCast(ctx, tx, enrollment, vs, channel, tpsID, tokenHash, now, map[string]any{
+		"races":         len(rankings),
+		"voting_system": election.VotingSystemIRV,
+	})
+}
+
+// validateRankedBallot checks that the ballot holds one ranking per eligible
+// race (a single race-less ranking in elections without races) and that every
+// ranking lists at least one candidate of its race, each at most once.
+// Voters may leave candidates unranked.
+func (s *Service) validateRankedBallot(ctx context.Context, tx pgx.Tx, electionID, voterID int64, rankings []BallotRanking) error {
+	raceCount, err := s.candidateRepo.CountRaces(ctx, tx, electionID)
+	if err != nil {
+		return err
+	}
+
+	if raceCount == 0 {
+		if len(rankings) != 1 || rankings[0].RaceID != nil {
+			return ErrBallotIncomplete
+		}
+	} else {
+		races, err := s.candidateRepo.ListEligibleRaces(ctx, tx, electionID, voterID)
+		if err != nil {
+			return err
+		}
+		ranked := make(map[int64]bool, len(races))
+		for _, race := range races {
+			ranked[race.ID] = false
+		}
+		for _, r := range rankings {
+			if r.RaceID == nil {
+				return ErrBallotIncomplete
+			}
+			done, ok := ranked[*r.RaceID]
+			if !ok {
+				return ErrRaceNotEligible
+			}
+			if done {
+				return ErrDuplicateRaceChoice
+			}
+			ranked[*r.RaceID] = true
+		}
+		if len(races) == 0 || len(rankings) != len(races) {
+			return ErrBallotIncomplete
+		}
+	}
+
+	for _, r := range rankings {
+		if len(r.Ranking) == 0 {
+			return ErrRankingRequired
+		}
+		seen := make(map[int64]struct{}, len(r.Ranking))
+		for _, candidateID := range r.Ranking {
+			if _, dup := seen[candidateID]; dup {
+				return ErrDuplicateRankedCandidate
+			}
+			seen[candidateID] = struct{}{}
+
+			cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, candidateID)
+			if err != nil {
+				if errors.Is(err, shared.ErrNotFound) {
+					return ErrCandidateNotFound
+				}
+				return err
+			}
+			if cand.ElectionID != electionID || !sameRace(cand.RaceID, r.RaceID) {
+				return ErrCandidateNotFound
+			}
+		}
+	}
+	return nil
+}
@@ -19,12 +19,34 @@ type VoterRepository interface {
 
 	// EnsureStatus inserts/updates voter_status with preferred/allowed flags
 	EnsureStatus(ctx context.Context, tx pgx.Tx, electionID, voterID int64, preferred string, onlineAllowed, tpsAllowed bool) (*VoterStatusEntity, error)
+
+	// GetLatestStatus gets voter status without locking. When electionID is nil the
+	// most recently voted (or otherwise latest) election is returned.
+	GetLatestStatus(ctx context.Context, tx pgx.Tx, voterID int64, electionID *int64) (*VoterStatusEntity, error)
+
+	// GetEnrollmentForUpdate gets the voter's election_voters row with row-level lock
+	GetEnrollmentForUpdate(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*ElectionVoterEnrollment, error)
+
+	// MarkEnrollmentVoted sets election_voters status to VOTED
+	MarkEnrollmentVoted(ctx context.Context, tx pgx.Tx, enrollmentID int64, votedAt time.Time) error
+
+	// GetIdentity gets basic voter identity (NIM and name)
+	GetIdentity(ctx context.Context, tx pgx.Tx, voterID int64) (*VoterIdentity, error)
 }
 
 // CandidateRepository handles candidate operations within transaction
 type CandidateRepository interface {
 	// GetByIDWithTx gets candidate by ID within transaction
 	GetByIDWithTx(ctx context.Context, tx pgx.Tx, candidateID int64) (*candidate.Candidate, error)
+
+	// CountRaces returns the number of races in an election (0 for single-race elections)
+	CountRaces(ctx context.Context, tx pgx.Tx, electionID int64) (int, error)
+
+	// ListEligibleRaces returns the races on the voter's ballot
+	ListEligibleRaces(ctx context.Context, tx pgx.Tx, electionID, voterID int64) ([]BallotRace, error)
+
+	// ListApprovedByElection returns the candidates on the ballot, by number
+	ListApprovedByElection(ctx context.Context, tx pgx.Tx, electionID int64) ([]candidate.Candidate, error)
 }
 
 // VoteRepository handles vote and token operations
@@ -35,15 +57,25 @@ type VoteRepository interface {
 	// InsertVote inserts a new vote
 	InsertVote(ctx context.Context, tx pgx.Tx, vote *Vote) error
 
+	// InsertRankedBallot inserts a ranked-choice ballot with its preferences
+	InsertRankedBallot(ctx context.Context, tx pgx.Tx, ballot *RankedBallot) error
+
 	// MarkTokenUsed marks a token as used
 	MarkTokenUsed(ctx context.Context, tx pgx.Tx, electionID int64, tokenHash string, usedAt time.Time) error
 
 	// GetLatestApprovedCheckin gets the latest approved TPS check-in for a voter
 	GetLatestApprovedCheckin(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*tps.TPSCheckin, error)
 
+	// GetLatestCheckin gets the most recent TPS check-in for a voter regardless of status (no lock)
+	GetLatestCheckin(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*tps.TPSCheckin, error)
+
 	// GetTPSByID gets TPS information by ID
 	GetTPSByID(ctx context.Context, tx pgx.Tx, tpsID int64) (*tps.TPS, error)
 
+	// HasOpenTPSExtension reports whether a TPS (any TPS of the election when
+	// tpsID is nil) holds a voting extension that has not ended at the given time
+	HasOpenTPSExtension(ctx context.Context, tx pgx.Tx, electionID int64, tpsID *int64, at time.Time) (bool, error)
+
 	// MarkCheckinUsed marks a check-in as used
 	MarkCheckinUsed(ctx context.Context, tx pgx.Tx, checkinID int64, usedAt time.Time) error
 
@@ -54,10 +86,25 @@ type VoteRepository interface {
 	FindCandidateQRByToken(ctx context.Context, tx pgx.Tx, token string) (*CandidateQR, error)
 	FindActiveCandidateQR(ctx context.Context, tx pgx.Tx, electionID, candidateID int64) (*CandidateQR, error)
 	FindActiveCandidateQRWithVersion(ctx context.Context, tx pgx.Tx, electionID, candidateID int64, version int) (*CandidateQR, error)
+	ListActiveCandidateQRs(ctx context.Context, tx pgx.Tx, electionID int64) ([]CandidateQR, error)
+	DeactivateCandidateQRs(ctx context.Context, tx pgx.Tx, electionID int64, rotatedAt time.Time) error
+	InsertCandidateQR(ctx context.Context, tx pgx.Tx, qr *CandidateQR) error
+
+	// NextBallotQRVersion locks the election for rotation and returns the version to issue
+	NextBallotQRVersion(ctx context.Context, tx pgx.Tx, electionID int64) (int, error)
+
+	// GetVoteReceipt looks up a counted vote by its receipt token (never exposes the candidate)
+	GetVoteReceipt(ctx context.Context, tx pgx.Tx, tokenHash string) (*VoteReceiptRecord, error)
 
 	// Ballot scan logging
 	InsertBallotScan(ctx context.Context, tx pgx.Tx, scan *BallotScan) error
 
+	// Offline TPS sync items. ClaimSyncItem inserts a PENDING item and
+	// reports false when the TPS already holds an item with that client ID.
+	ClaimSyncItem(ctx context.Context, tx pgx.Tx, item *TPSSyncItem) (bool, error)
+	GetSyncItem(ctx context.Context, tx pgx.Tx, tpsID int64, clientID string) (*TPSSyncItem, error)
+	FinishSyncItem(ctx context.Context, tx pgx.Tx, item *TPSSyncItem) error
+
 	// Voter TPS QR
 	GetActiveVoterQR(ctx context.Context, tx pgx.Tx, voterID, electionID int64) (*VoterTPSQR, error)
 	DeactivateVoterQR(ctx context.Context, tx pgx.Tx, qrID int64, rotatedAt time.Time) error
@@ -3,7 +3,7 @@ package voting
 import (
 	"context"
 	"fmt"
-	
+
 	"github.com/jackc/pgx/v5"
 	"pemira-api/internal/candidate"
 	"pemira-api/internal/shared"
@@ -17,34 +17,121 @@ func NewCandidateRepository() CandidateRepository {
 
 func (r *candidateRepository) GetByIDWithTx(ctx context.Context, tx pgx.Tx, candidateID int64) (*candidate.Candidate, error) {
 	query := `
-		SELECT id, election_id, order_number, name, vision_mission, photo_url, is_active, created_at, updated_at
+		SELECT id, election_id, race_id, number, name, photo_url, status, created_at, updated_at
 		FROM candidates
 		WHERE id = $1
 	`
-	
+
 	var c candidate.Candidate
-	
-	var visionMission string
-	var isActive bool
-	
+
 	err := tx.QueryRow(ctx, query, candidateID).Scan(
 		&c.ID,
 		&c.ElectionID,
+		&c.RaceID,
 		&c.Number,
 		&c.Name,
-		&visionMission,  // Skip this field
 		&c.PhotoURL,
-		&isActive,       // Skip this field
+		&c.Status,
 		&c.CreatedAt,
 		&c.UpdatedAt,
 	)
-	
+
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, shared.ErrNotFound
 		}
 		return nil, fmt.Errorf("get candidate: %w", err)
 	}
-	
+
 	return &c, nil
 }
+
+func (r *candidateRepository) ListApprovedByElection(ctx context.Context, tx pgx.Tx, electionID int64) ([]candidate.Candidate, error) {
+	query := `
+		SELECT id, election_id, race_id, number, name, photo_url, status, created_at, updated_at
+		FROM candidates
+		WHERE election_id = $1 AND status = $2
+		ORDER BY number
+	`
+
+	rows, err := tx.Query(ctx, query, electionID, candidate.CandidateStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("list candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []candidate.Candidate
+	for rows.Next() {
+		var c candidate.Candidate
+		if err := rows.Scan(
+			&c.ID,
+			&c.ElectionID,
+			&c.RaceID,
+			&c.Number,
+			&c.Name,
+			&c.PhotoURL,
+			&c.Status,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+func (r *candidateRepository) CountRaces(ctx context.Context, tx pgx.Tx, electionID int64) (int, error) {
+	var count int
+	err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM election_races WHERE election_id = $1`, electionID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count races: %w", err)
+	}
+	return count, nil
+}
+
+func (r *candidateRepository) ListEligibleRaces(ctx context.Context, tx pgx.Tx, electionID, voterID int64) ([]BallotRace, error) {
+	// A race without eligibility rows is open to everyone; otherwise the voter's
+	// faculty or study program (stored as code/name on voters) must match master data.
+	query := `
+		SELECT r.id, r.code, r.name
+		FROM election_races r
+		JOIN voters v ON v.id = $2
+		WHERE r.election_id = $1
+		  AND (
+		    NOT EXISTS (SELECT 1 FROM election_race_eligibility e WHERE e.race_id = r.id)
+		    OR EXISTS (
+		      SELECT 1
+		      FROM election_race_eligibility e
+		      LEFT JOIN faculties f ON f.id = e.faculty_id
+		      LEFT JOIN study_programs sp ON sp.id = e.study_program_id
+		      WHERE e.race_id = r.id
+		        AND (
+		          (f.id IS NOT NULL AND (f.code = v.faculty_code OR f.name = v.faculty_name))
+		          OR (sp.id IS NOT NULL AND (sp.code = v.study_program_code OR sp.name = v.study_program_name))
+		        )
+		    )
+		  )
+		ORDER BY r.display_order, r.id
+	`
+
+	rows, err := tx.Query(ctx, query, electionID, voterID)
+	if err != nil {
+		return nil, fmt.Errorf("list eligible races: %w", err)
+	}
+	defer rows.Close()
+
+	races := make([]BallotRace, 0)
+	for rows.Next() {
+		var race BallotRace
+		if err := rows.Scan(&race.ID, &race.Code, &race.Name); err != nil {
+			return nil, fmt.Errorf("scan race: %w", err)
+		}
+		races = append(races, race)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list eligible races: %w", err)
+	}
+
+	return races, nil
+}
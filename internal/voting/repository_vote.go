@@ -41,8 +41,8 @@ func (r *voteRepository) InsertToken(ctx context.Context, tx pgx.Tx, token *Vote
 
 func (r *voteRepository) InsertVote(ctx context.Context, tx pgx.Tx, vote *Vote) error {
 	query := `
-		INSERT INTO votes (election_id, candidate_id, token_hash, channel, tps_id, candidate_qr_id, ballot_scan_id, cast_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO votes (election_id, candidate_id, token_hash, channel, tps_id, candidate_qr_id, ballot_scan_id, cast_at, voter_type, race_id, is_abstain)
+		VALUES ($1, NULLIF($2::bigint, 0), $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11)
 		RETURNING id
 	`
 
@@ -55,6 +55,9 @@ func (r *voteRepository) InsertVote(ctx context.Context, tx pgx.Tx, vote *Vote)
 		vote.CandidateQRID,
 		vote.BallotScanID,
 		vote.CastAt,
+		vote.VoterType,
+		vote.RaceID,
+		vote.IsAbstain,
 	).Scan(&vote.ID)
 
 	if err != nil {
@@ -64,6 +67,39 @@ func (r *voteRepository) InsertVote(ctx context.Context, tx pgx.Tx, vote *Vote)
 	return nil
 }
 
+func (r *voteRepository) InsertRankedBallot(ctx context.Context, tx pgx.Tx, ballot *RankedBallot) error {
+	query := `
+		INSERT INTO ranked_ballots (election_id, race_id, token_hash, channel, tps_id, voter_type, cast_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id
+	`
+
+	err := tx.QueryRow(ctx, query,
+		ballot.ElectionID,
+		ballot.RaceID,
+		ballot.TokenHash,
+		ballot.Channel,
+		ballot.TPSID,
+		ballot.VoterType,
+		ballot.CastAt,
+	).Scan(&ballot.ID)
+	if err != nil {
+		return fmt.Errorf("insert ranked ballot: %w", err)
+	}
+
+	// Position in the ranking is the rank, starting at 1
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ranked_ballot_preferences (ballot_id, rank, candidate_id)
+		SELECT $1, p.rank, p.candidate_id
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS p(candidate_id, rank)
+	`, ballot.ID, ballot.Ranking)
+	if err != nil {
+		return fmt.Errorf("insert ranked preferences: %w", err)
+	}
+
+	return nil
+}
+
 func (r *voteRepository) MarkTokenUsed(ctx context.Context, tx pgx.Tx, electionID int64, tokenHash string, usedAt time.Time) error {
 	query := `
 		UPDATE vote_tokens
@@ -118,6 +154,63 @@ func (r *voteRepository) GetLatestApprovedCheckin(ctx context.Context, tx pgx.Tx
 	return &checkin, nil
 }
 
+func (r *voteRepository) GetLatestCheckin(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*tps.TPSCheckin, error) {
+	query := `
+		SELECT id, tps_id, voter_id, election_id, status, scan_at,
+		       approved_at, approved_by_id, rejection_reason, expires_at,
+		       created_at, updated_at
+		FROM tps_checkins
+		WHERE election_id = $1 AND voter_id = $2
+		ORDER BY scan_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var checkin tps.TPSCheckin
+
+	err := tx.QueryRow(ctx, query, electionID, voterID).Scan(
+		&checkin.ID,
+		&checkin.TPSID,
+		&checkin.VoterID,
+		&checkin.ElectionID,
+		&checkin.Status,
+		&checkin.ScanAt,
+		&checkin.ApprovedAt,
+		&checkin.ApprovedByID,
+		&checkin.RejectionReason,
+		&checkin.ExpiresAt,
+		&checkin.CreatedAt,
+		&checkin.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get latest checkin: %w", err)
+	}
+
+	return &checkin, nil
+}
+
+func (r *voteRepository) HasOpenTPSExtension(ctx context.Context, tx pgx.Tx, electionID int64, tpsID *int64, at time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM voting_extensions
+			WHERE election_id = $1
+			  AND tps_id IS NOT NULL
+			  AND ($2::bigint IS NULL OR tps_id = $2)
+			  AND voting_end_at > $3
+		)
+	`
+
+	var open bool
+	if err := tx.QueryRow(ctx, query, electionID, tpsID, at).Scan(&open); err != nil {
+		return false, err
+	}
+	return open, nil
+}
+
 func (r *voteRepository) GetTPSByID(ctx context.Context, tx pgx.Tx, tpsID int64) (*tps.TPS, error) {
 	query := `
 		SELECT id, election_id, code, name, location, status, 
@@ -155,6 +248,38 @@ func (r *voteRepository) GetTPSByID(ctx context.Context, tx pgx.Tx, tpsID int64)
 	return &t, nil
 }
 
+func (r *voteRepository) GetVoteReceipt(ctx context.Context, tx pgx.Tx, tokenHash string) (*VoteReceiptRecord, error) {
+	query := `
+		SELECT v.election_id, e.name, v.channel, v.tps_id, v.cast_at
+		FROM (
+			SELECT election_id, channel, tps_id, cast_at FROM votes WHERE token_hash = $1
+			UNION ALL
+			SELECT election_id, channel, tps_id, cast_at FROM ranked_ballots WHERE token_hash = $1
+		) v
+		JOIN elections e ON e.id = v.election_id
+		LIMIT 1
+	`
+
+	var rec VoteReceiptRecord
+
+	err := tx.QueryRow(ctx, query, tokenHash).Scan(
+		&rec.ElectionID,
+		&rec.ElectionName,
+		&rec.Channel,
+		&rec.TPSID,
+		&rec.CastAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get vote receipt: %w", err)
+	}
+
+	return &rec, nil
+}
+
 func (r *voteRepository) MarkCheckinUsed(ctx context.Context, tx pgx.Tx, checkinID int64, usedAt time.Time) error {
 	query := `
 		UPDATE tps_checkins
@@ -172,7 +297,7 @@ func (r *voteRepository) MarkCheckinUsed(ctx context.Context, tx pgx.Tx, checkin
 
 func (r *voteRepository) FindCandidateQRByToken(ctx context.Context, tx pgx.Tx, token string) (*CandidateQR, error) {
 	query := `
-		SELECT id, election_id, candidate_id, version, qr_token, is_active
+		SELECT id, election_id, COALESCE(candidate_id, 0), version, qr_token, is_active
 		FROM candidate_qr_codes
 		WHERE qr_token = $1 AND is_active = TRUE
 		LIMIT 1
@@ -196,11 +321,13 @@ func (r *voteRepository) FindCandidateQRByToken(ctx context.Context, tx pgx.Tx,
 	return &qr, nil
 }
 
+// FindActiveCandidateQR and FindActiveCandidateQRWithVersion take candidateID 0
+// to look up the election's abstain QR.
 func (r *voteRepository) FindActiveCandidateQR(ctx context.Context, tx pgx.Tx, electionID, candidateID int64) (*CandidateQR, error) {
 	query := `
-		SELECT id, election_id, candidate_id, version, qr_token, is_active
+		SELECT id, election_id, COALESCE(candidate_id, 0), version, qr_token, is_active
 		FROM candidate_qr_codes
-		WHERE election_id = $1 AND candidate_id = $2 AND is_active = TRUE
+		WHERE election_id = $1 AND COALESCE(candidate_id, 0) = $2 AND is_active = TRUE
 		LIMIT 1
 	`
 
@@ -224,9 +351,9 @@ func (r *voteRepository) FindActiveCandidateQR(ctx context.Context, tx pgx.Tx, e
 
 func (r *voteRepository) FindActiveCandidateQRWithVersion(ctx context.Context, tx pgx.Tx, electionID, candidateID int64, version int) (*CandidateQR, error) {
 	query := `
-		SELECT id, election_id, candidate_id, version, qr_token, is_active
+		SELECT id, election_id, COALESCE(candidate_id, 0), version, qr_token, is_active
 		FROM candidate_qr_codes
-		WHERE election_id = $1 AND candidate_id = $2 AND version = $3 AND is_active = TRUE
+		WHERE election_id = $1 AND COALESCE(candidate_id, 0) = $2 AND version = $3 AND is_active = TRUE
 		LIMIT 1
 	`
 
@@ -248,6 +375,79 @@ func (r *voteRepository) FindActiveCandidateQRWithVersion(ctx context.Context, t
 	return &qr, nil
 }
 
+func (r *voteRepository) ListActiveCandidateQRs(ctx context.Context, tx pgx.Tx, electionID int64) ([]CandidateQR, error) {
+	query := `
+		SELECT id, election_id, COALESCE(candidate_id, 0), version, qr_token, is_active
+		FROM candidate_qr_codes
+		WHERE election_id = $1 AND is_active = TRUE
+		ORDER BY candidate_id NULLS LAST
+	`
+
+	rows, err := tx.Query(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("list candidate qr: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []CandidateQR
+	for rows.Next() {
+		var qr CandidateQR
+		if err := rows.Scan(&qr.ID, &qr.ElectionID, &qr.CandidateID, &qr.Version, &qr.QRToken, &qr.IsActive); err != nil {
+			return nil, fmt.Errorf("scan candidate qr: %w", err)
+		}
+		codes = append(codes, qr)
+	}
+	return codes, rows.Err()
+}
+
+func (r *voteRepository) DeactivateCandidateQRs(ctx context.Context, tx pgx.Tx, electionID int64, rotatedAt time.Time) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE candidate_qr_codes
+		SET is_active = FALSE, rotated_at = $2
+		WHERE election_id = $1 AND is_active = TRUE
+	`, electionID, rotatedAt)
+	if err != nil {
+		return fmt.Errorf("deactivate candidate qr: %w", err)
+	}
+	return nil
+}
+
+// InsertCandidateQR inserts an active ballot QR; CandidateID 0 inserts the abstain QR.
+func (r *voteRepository) InsertCandidateQR(ctx context.Context, tx pgx.Tx, qr *CandidateQR) error {
+	var candidateID *int64
+	if qr.CandidateID != 0 {
+		candidateID = &qr.CandidateID
+	}
+	err := tx.QueryRow(ctx, `
+		INSERT INTO candidate_qr_codes (election_id, candidate_id, version, qr_token, is_active)
+		VALUES ($1, $2, $3, $4, TRUE)
+		RETURNING id
+	`, qr.ElectionID, candidateID, qr.Version, qr.QRToken).Scan(&qr.ID)
+	if err != nil {
+		return fmt.Errorf("insert candidate qr: %w", err)
+	}
+	qr.IsActive = true
+	return nil
+}
+
+func (r *voteRepository) NextBallotQRVersion(ctx context.Context, tx pgx.Tx, electionID int64) (int, error) {
+	// Concurrent rotations of one election queue on the election row
+	if _, err := tx.Exec(ctx, `SELECT id FROM elections WHERE id = $1 FOR UPDATE`, electionID); err != nil {
+		return 0, fmt.Errorf("lock election: %w", err)
+	}
+
+	var version int
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(version), 0) + 1
+		FROM candidate_qr_codes
+		WHERE election_id = $1
+	`, electionID).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("next ballot qr version: %w", err)
+	}
+	return version, nil
+}
+
 func (r *voteRepository) InsertBallotScan(ctx context.Context, tx pgx.Tx, scan *BallotScan) error {
 	query := `
 		INSERT INTO tps_ballot_scans (election_id, tps_id, checkin_id, voter_id, candidate_id, candidate_qr_id,
@@ -359,3 +559,61 @@ func (r *voteRepository) GetCheckinByID(ctx context.Context, tx pgx.Tx, checkinI
 
 	return &checkin, nil
 }
+
+func (r *voteRepository) ClaimSyncItem(ctx context.Context, tx pgx.Tx, item *TPSSyncItem) (bool, error) {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO tps_sync_items (tps_id, client_id, election_id, item_type, status, occurred_at, synced_by)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		ON CONFLICT (tps_id, client_id) DO NOTHING
+		RETURNING synced_at
+	`, item.TPSID, item.ClientID, item.ElectionID, item.ItemType, item.Status, item.OccurredAt, item.SyncedBy).Scan(&item.SyncedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim sync item: %w", err)
+	}
+	return true, nil
+}
+
+func (r *voteRepository) GetSyncItem(ctx context.Context, tx pgx.Tx, tpsID int64, clientID string) (*TPSSyncItem, error) {
+	var item TPSSyncItem
+	err := tx.QueryRow(ctx, `
+		SELECT tps_id, client_id, election_id, item_type, status, reason, message,
+		       voter_id, checkin_id, occurred_at, synced_by, synced_at
+		FROM tps_sync_items
+		WHERE tps_id = $1 AND client_id = $2
+	`, tpsID, clientID).Scan(
+		&item.TPSID,
+		&item.ClientID,
+		&item.ElectionID,
+		&item.ItemType,
+		&item.Status,
+		&item.Reason,
+		&item.Message,
+		&item.VoterID,
+		&item.CheckinID,
+		&item.OccurredAt,
+		&item.SyncedBy,
+		&item.SyncedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get sync item: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *voteRepository) FinishSyncItem(ctx context.Context, tx pgx.Tx, item *TPSSyncItem) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE tps_sync_items
+		SET status = $3, reason = $4, message = $5, voter_id = $6, checkin_id = $7
+		WHERE tps_id = $1 AND client_id = $2
+	`, item.TPSID, item.ClientID, item.Status, item.Reason, item.Message, item.VoterID, item.CheckinID)
+	if err != nil {
+		return fmt.Errorf("finish sync item: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package voting
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"pemira-api/internal/shared"
@@ -120,3 +121,103 @@ func (r *voterRepository) EnsureStatus(ctx context.Context, tx pgx.Tx, electionI
 	}
 	return &vs, nil
 }
+
+func (r *voterRepository) GetLatestStatus(ctx context.Context, tx pgx.Tx, voterID int64, electionID *int64) (*VoterStatusEntity, error) {
+	query := `
+		SELECT id, election_id, voter_id, is_eligible, has_voted,
+		       voting_method, tps_id, voted_at, vote_token_hash,
+		       preferred_method, online_allowed, tps_allowed, created_at, updated_at
+		FROM voter_status
+		WHERE voter_id = $1
+		  AND ($2::bigint IS NULL OR election_id = $2)
+		ORDER BY has_voted DESC, voted_at DESC NULLS LAST, election_id DESC
+		LIMIT 1
+	`
+
+	var vs VoterStatusEntity
+	err := tx.QueryRow(ctx, query, voterID, electionID).Scan(
+		&vs.ID,
+		&vs.ElectionID,
+		&vs.VoterID,
+		&vs.IsEligible,
+		&vs.HasVoted,
+		&vs.VotingMethod,
+		&vs.TPSID,
+		&vs.VotedAt,
+		&vs.TokenHash,
+		&vs.PreferredMethod,
+		&vs.OnlineAllowed,
+		&vs.TPSAllowed,
+		&vs.CreatedAt,
+		&vs.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get latest voter status: %w", err)
+	}
+	return &vs, nil
+}
+
+func (r *voterRepository) GetIdentity(ctx context.Context, tx pgx.Tx, voterID int64) (*VoterIdentity, error) {
+	query := `
+		SELECT id, COALESCE(nim, ''), COALESCE(name, '')
+		FROM voters
+		WHERE id = $1
+	`
+
+	var v VoterIdentity
+	if err := tx.QueryRow(ctx, query, voterID).Scan(&v.ID, &v.NIM, &v.Name); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get voter identity: %w", err)
+	}
+	return &v, nil
+}
+
+func (r *voterRepository) GetEnrollmentForUpdate(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*ElectionVoterEnrollment, error) {
+	query := `
+		SELECT ev.id, ev.election_id, ev.voter_id, COALESCE(v.voter_type, 'STUDENT'),
+		       ev.status::text, ev.voting_method::text, ev.tps_id, ev.voted_at
+		FROM election_voters ev
+		JOIN voters v ON v.id = ev.voter_id
+		WHERE ev.election_id = $1 AND ev.voter_id = $2
+		FOR UPDATE OF ev
+	`
+
+	var e ElectionVoterEnrollment
+	err := tx.QueryRow(ctx, query, electionID, voterID).Scan(
+		&e.ID,
+		&e.ElectionID,
+		&e.VoterID,
+		&e.VoterType,
+		&e.Status,
+		&e.VotingMethod,
+		&e.TPSID,
+		&e.VotedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, shared.ErrNotFound
+		}
+		return nil, fmt.Errorf("get election voter enrollment: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *voterRepository) MarkEnrollmentVoted(ctx context.Context, tx pgx.Tx, enrollmentID int64, votedAt time.Time) error {
+	query := `
+		UPDATE election_voters
+		SET status = 'VOTED',
+		    voted_at = $1,
+		    updated_at = NOW()
+		WHERE id = $2
+	`
+
+	if _, err := tx.Exec(ctx, query, votedAt, enrollmentID); err != nil {
+		return fmt.Errorf("mark election voter voted: %w", err)
+	}
+	return nil
+}
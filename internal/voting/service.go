@@ -18,17 +18,41 @@ import (
 	"pemira-api/internal/shared"
 	"pemira-api/internal/shared/constants"
 	"pemira-api/internal/tps"
+	"pemira-api/internal/ws"
 )
 
 type Service struct {
-	db            *pgxpool.Pool
-	repo          Repository
-	electionRepo  election.Repository
-	voterRepo     VoterRepository
-	candidateRepo CandidateRepository
-	voteRepo      VoteRepository
-	statsRepo     VoteStatsRepository
-	auditSvc      AuditService
+	db             *pgxpool.Pool
+	repo           Repository
+	electionRepo   election.Repository
+	voterRepo      VoterRepository
+	candidateRepo  CandidateRepository
+	voteRepo       VoteRepository
+	statsRepo      VoteStatsRepository
+	auditSvc       AuditService
+	modeSettings   ModeSettingsProvider
+	voteListener   VoteListener
+	events         ws.Publisher
+	checkins       OfflineCheckinApplier
+	ballotQRSecret []byte
+}
+
+// VoteListener is told about every vote after it is committed
+// (implemented by monitoring.LiveCount).
+type VoteListener interface {
+	VoteCast(electionID int64, tpsID *int64)
+}
+
+// OfflineCheckinApplier applies check-ins a TPS panel recorded offline inside
+// the sync transaction (implemented by tps.CheckinService).
+type OfflineCheckinApplier interface {
+	ApplyOfflineCheckin(ctx context.Context, tx pgx.Tx, req tps.OfflineCheckin) (*tps.TPSCheckin, error)
+}
+
+// ModeSettingsProvider supplies per-election voting mode settings
+// (implemented by election.PgAdminRepository).
+type ModeSettingsProvider interface {
+	GetModeSettings(ctx context.Context, id int64) (*election.ModeSettingsDTO, error)
 }
 
 type SetMethodRequest struct {
@@ -78,6 +102,109 @@ func NewVotingService(
 	}
 }
 
+// SetModeSettingsProvider sets the source of election mode settings used by
+// GetVotingConfig and GetTPSVotingStatus.
+func (s *Service) SetModeSettingsProvider(p ModeSettingsProvider) {
+	s.modeSettings = p
+}
+
+// SetVoteListener sets the listener told about committed votes.
+func (s *Service) SetVoteListener(l VoteListener) {
+	s.voteListener = l
+}
+
+// SetEventPublisher sets where vote.cast and check-in updates are published.
+func (s *Service) SetEventPublisher(p ws.Publisher) {
+	s.events = p
+}
+
+// SetOfflineCheckinApplier enables the TPS sync API.
+func (s *Service) SetOfflineCheckinApplier(a OfflineCheckinApplier) {
+	s.checkins = a
+}
+
+// SetBallotQRSecret sets the server secret ballot QR signing keys are derived
+// from. Changing it invalidates every printed ballot QR code.
+func (s *Service) SetBallotQRSecret(secret string) {
+	s.ballotQRSecret = []byte(secret)
+}
+
+func (s *Service) notifyVoteCast(electionID int64, channel string, tpsID *int64, castAt time.Time) {
+	if s.voteListener != nil {
+		s.voteListener.VoteCast(electionID, tpsID)
+	}
+	ws.PublishVoteCast(s.events, ws.VoteCastEvent{
+		ElectionID: electionID,
+		Channel:    channel,
+		TPSID:      tpsID,
+		CastAt:     castAt,
+	})
+}
+
+// notifyCheckinVoted publishes that the check-in was used to vote.
+func (s *Service) notifyCheckinVoted(electionID, tpsID, checkinID int64) {
+	ws.PublishCheckin(s.events, ws.EventCheckinUpdated, ws.CheckinEvent{
+		CheckinID:  checkinID,
+		ElectionID: electionID,
+		TPSID:      tpsID,
+		Status:     tps.CheckinStatusVoted,
+	})
+}
+
+// voterTypeByRole maps the roles that may vote to the voters.voter_type they vote as.
+var voterTypeByRole = map[constants.Role]string{
+	constants.RoleStudent:  "STUDENT",
+	constants.RoleLecturer: "LECTURER",
+	constants.RoleStaff:    "STAFF",
+}
+
+// isVoterRole reports whether the role belongs to a voter account.
+func isVoterRole(role constants.Role) bool {
+	_, ok := voterTypeByRole[role]
+	return ok
+}
+
+// voterFromAuth returns the voter ID and voter type of an authenticated voter.
+func voterFromAuth(authUser auth.AuthUser) (int64, string, error) {
+	voterType, ok := voterTypeByRole[authUser.Role]
+	if !ok || authUser.VoterID == nil {
+		return 0, "", ErrVoterMappingMissing
+	}
+	return *authUser.VoterID, voterType, nil
+}
+
+// authorizeEnrollment locks the voter's election_voters row and checks the voter
+// is enrolled in the election with a status that still allows voting (see
+// checkEnrollment).
+func (s *Service) authorizeEnrollment(ctx context.Context, tx pgx.Tx, electionID, voterID int64, voterType string) (*ElectionVoterEnrollment, error) {
+	enrollment, err := s.voterRepo.GetEnrollmentForUpdate(ctx, tx, electionID, voterID)
+	if err != nil {
+		return nil, translateNotFound(err, ErrNotEligible)
+	}
+	if err := checkEnrollment(enrollment, voterType); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// checkEnrollment allows only VERIFIED enrollments to vote; pending
+// (self-registered, unverified), rejected and blocked ones are not eligible.
+// A non-empty voterType must match voters.voter_type (empty skips the check,
+// e.g. when a TPS operator casts on the voter's behalf).
+func checkEnrollment(enrollment *ElectionVoterEnrollment, voterType string) error {
+	switch enrollment.Status {
+	case "VERIFIED":
+	case "VOTED":
+		return ErrAlreadyVoted
+	default:
+		return ErrNotEligible
+	}
+	if voterType != "" && enrollment.VoterType != voterType {
+		return ErrNotEligible
+	}
+	return nil
+}
+
 // withTx executes a function within a transaction
 func (s *Service) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
@@ -98,11 +225,127 @@ func (s *Service) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
 	return tx.Commit(ctx)
 }
 
+// resolveElection returns the requested election or the current one when electionID is nil.
+func (s *Service) resolveElection(ctx context.Context, electionID *int64) (*election.Election, error) {
+	var (
+		e   *election.Election
+		err error
+	)
+	if electionID != nil {
+		e, err = s.electionRepo.GetByID(ctx, *electionID)
+	} else {
+		e, err = s.electionRepo.GetCurrentElection(ctx)
+	}
+	if err != nil {
+		if errors.Is(err, election.ErrElectionNotFound) {
+			return nil, ErrElectionNotFound
+		}
+		return nil, translateNotFound(err, ErrElectionNotFound)
+	}
+	if e == nil {
+		return nil, ErrElectionNotFound
+	}
+	return e, nil
+}
+
+// loadModeSettings returns the election mode settings, falling back to the
+// on/off flags of the election row when no provider is configured.
+func (s *Service) loadModeSettings(ctx context.Context, e *election.Election) (*election.ModeSettingsDTO, error) {
+	if s.modeSettings == nil {
+		return &election.ModeSettingsDTO{
+			ElectionID:    e.ID,
+			OnlineEnabled: e.OnlineEnabled,
+			TPSEnabled:    e.TPSEnabled,
+			VotingSystem:  election.VotingSystemPlurality,
+		}, nil
+	}
+
+	settings, err := s.modeSettings.GetModeSettings(ctx, e.ID)
+	if err != nil {
+		if errors.Is(err, election.ErrElectionNotFound) {
+			return nil, ErrElectionNotFound
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
 // GetVotingConfig returns voting configuration and voter eligibility
-func (s *Service) GetVotingConfig(ctx context.Context, voterID int64) (*VotingConfigResponse, error) {
-	// TODO: Implement with actual database queries
-	// This is a stub implementation
-	return &VotingConfigResponse{}, nil
+func (s *Service) GetVotingConfig(ctx context.Context, voterID int64, electionID *int64) (*VotingConfigResponse, error) {
+	if s.db == nil || s.electionRepo == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	electionRow, err := s.resolveElection(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.loadModeSettings(ctx, electionRow)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VotingConfigResponse{
+		Election: ElectionInfo{
+			ID:            electionRow.ID,
+			Code:          electionRow.Code,
+			Slug:          electionRow.Slug,
+			Name:          electionRow.Name,
+			Status:        string(electionRow.Status),
+			VotingStartAt: electionRow.VotingStartAt,
+			VotingEndAt:   electionRow.VotingEndAt,
+		},
+		Mode: VotingMode{
+			OnlineEnabled:  settings.OnlineEnabled,
+			TPSEnabled:     settings.TPSEnabled,
+			AbstainEnabled: settings.AbstainEnabled,
+			VotingSystem:   settings.VotingSystem,
+			OnlineSettings: settings.OnlineSettings,
+			TPSSettings:    settings.TPSSettings,
+		},
+	}
+
+	err = s.withTx(ctx, func(tx pgx.Tx) error {
+		identity, err := s.voterRepo.GetIdentity(ctx, tx, voterID)
+		if err != nil {
+			return translateNotFound(err, ErrNotEligible)
+		}
+		result.Voter = VoterInfo{
+			ID:   identity.ID,
+			NIM:  identity.NIM,
+			Name: identity.Name,
+		}
+
+		status, err := s.voterRepo.GetLatestStatus(ctx, tx, voterID, &electionRow.ID)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		result.Voter.IsEligible = status.IsEligible
+		result.Voter.HasVoted = status.HasVoted
+		result.Voter.VotingMethod = status.VotingMethod
+		result.Voter.PreferredMethod = status.PreferredMethod
+		result.Voter.OnlineAllowed = status.OnlineAllowed && settings.OnlineEnabled
+		result.Voter.TPSAllowed = status.TPSAllowed && settings.TPSEnabled
+		result.Voter.TPSID = status.TPSID
+		result.Voter.VotedAt = status.VotedAt
+
+		races, err := s.candidateRepo.ListEligibleRaces(ctx, tx, electionRow.ID, voterID)
+		if err != nil {
+			return err
+		}
+		result.Races = races
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // CastOnlineVote handles online voting with full validation
@@ -112,12 +355,11 @@ func (s *Service) CastOnlineVote(ctx context.Context, authUser auth.AuthUser, re
 	}
 
 	// Check if user has voter mapping
-	if authUser.Role != constants.RoleStudent || authUser.VoterID == nil {
-		return ErrVoterMappingMissing
+	voterID, voterType, err := voterFromAuth(authUser)
+	if err != nil {
+		return err
 	}
 
-	voterID := *authUser.VoterID
-
 	// 1. Get election
 	election, err := s.electionRepo.GetByID(ctx, req.ElectionID)
 	if err != nil {
@@ -134,8 +376,21 @@ func (s *Service) CastOnlineVote(ctx context.Context, authUser auth.AuthUser, re
 		return ErrMethodNotAllowed
 	}
 
-	// 4. Cast vote with transaction
-	_, err = s.castVote(ctx, req.ElectionID, voterID, req.CandidateID, "ONLINE", nil)
+	// 4. Ranked-choice elections take a ranking instead of a single choice
+	ranked, err := s.isRanked(ctx, election)
+	if err != nil {
+		return err
+	}
+	if ranked {
+		_, err = s.castRankedVote(ctx, req.ElectionID, voterID, voterType, ballotRankings(req.Ranking, req.Rankings), "ONLINE", nil)
+		return err
+	}
+	if len(req.Ranking) > 0 || len(req.Rankings) > 0 {
+		return ErrRankingNotAllowed
+	}
+
+	// 5. Cast vote with transaction
+	_, err = s.castVote(ctx, req.ElectionID, voterID, voterType, ballotChoices(req.CandidateID, req.Abstain, req.Choices), "ONLINE", nil)
 	return err
 }
 
@@ -146,32 +401,31 @@ func (s *Service) CastTPSVote(ctx context.Context, authUser auth.AuthUser, req C
 	}
 
 	// Check if user has voter mapping
-	if authUser.Role != constants.RoleStudent || authUser.VoterID == nil {
-		return ErrVoterMappingMissing
+	voterID, voterType, err := voterFromAuth(authUser)
+	if err != nil {
+		return err
 	}
 
-	voterID := *authUser.VoterID
-
 	// 1. Get election
 	election, err := s.electionRepo.GetByID(ctx, req.ElectionID)
 	if err != nil {
 		return translateNotFound(err, ErrElectionNotFound)
 	}
 
-	// 2. Validate election status
-	if election.Status != "VOTING_OPEN" {
-		return ErrElectionNotOpen
-	}
-
-	// 3. Validate TPS mode enabled
+	// 2. Validate TPS mode enabled
 	if !election.TPSEnabled {
 		return ErrMethodNotAllowed
 	}
 
-	// 4. Get & validate latest approved check-in
+	// 3. Validate the TPS is open (the election, or an extension of this TPS)
+	// and get & validate latest approved check-in
 	var checkin *tps.TPSCheckin
 
 	err = s.withTx(ctx, func(tx pgx.Tx) error {
+		if err := s.ensureTPSVotingOpen(ctx, tx, election, &req.TPSID); err != nil {
+			return err
+		}
+
 		var err error
 		checkin, err = s.voteRepo.GetLatestApprovedCheckin(ctx, tx, req.ElectionID, voterID)
 		if err != nil {
@@ -200,181 +454,600 @@ func (s *Service) CastTPSVote(ctx context.Context, authUser auth.AuthUser, req C
 		return err
 	}
 
-	// 5. Cast vote with TPS info
-	_, err = s.castVote(ctx, req.ElectionID, voterID, req.CandidateID, "TPS", &req.TPSID)
+	// 4. Cast vote with TPS info; ranked-choice elections take a ranking
+	ranked, err := s.isRanked(ctx, election)
+	if err != nil {
+		return err
+	}
+	if ranked {
+		_, err = s.castRankedVote(ctx, req.ElectionID, voterID, voterType, ballotRankings(req.Ranking, req.Rankings), "TPS", &req.TPSID)
+	} else if len(req.Ranking) > 0 || len(req.Rankings) > 0 {
+		err = ErrRankingNotAllowed
+	} else {
+		_, err = s.castVote(ctx, req.ElectionID, voterID, voterType, ballotChoices(req.CandidateID, req.Abstain, req.Choices), "TPS", &req.TPSID)
+	}
 	if err != nil {
 		return err
 	}
 
-	// 6. Mark check-in as used
-	_ = s.withTx(ctx, func(tx pgx.Tx) error {
+	// 5. Mark check-in as used
+	err = s.withTx(ctx, func(tx pgx.Tx) error {
 		return s.voteRepo.MarkCheckinUsed(ctx, tx, checkin.ID, time.Now().UTC())
 	})
+	if err == nil {
+		s.notifyCheckinVoted(req.ElectionID, checkin.TPSID, checkin.ID)
+	}
 
 	return nil
 }
 
-// castVote is the core voting logic with transaction safety
+// castVote is the core voting logic with transaction safety. The ballot holds
+// one choice per race; all votes are written atomically under one token.
 func (s *Service) castVote(
 	ctx context.Context,
-	electionID, voterID, candidateID int64,
+	electionID, voterID int64,
+	voterType string,
+	choices []BallotChoice,
 	channel string,
 	tpsID *int64,
 ) (*VoteResultEntity, error) {
 	var result *VoteResultEntity
 
 	err := s.withTx(ctx, func(tx pgx.Tx) error {
-		// 1. Lock voter_status with FOR UPDATE
-		vs, err := s.voterRepo.GetStatusForUpdate(ctx, tx, electionID, voterID)
-		if err != nil {
-			return translateNotFound(err, ErrNotEligible)
+		var err error
+		result, err = s.castVoteTx(ctx, tx, electionID, voterID, voterType, choices, channel, tpsID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyVoteCast(electionID, channel, tpsID, result.VotedAt)
+	return result, nil
+}
+
+// castVoteTx runs castVote inside tx without notifying listeners, so callers
+// can combine it with other writes (e.g. the TPS sync batch).
+func (s *Service) castVoteTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	electionID, voterID int64,
+	voterType string,
+	choices []BallotChoice,
+	channel string,
+	tpsID *int64,
+) (*VoteResultEntity, error) {
+	// 1. Lock and check the voter's enrollment and voter_status
+	enrollment, vs, err := s.lockVoterForCast(ctx, tx, electionID, voterID, voterType, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate the ballot: one existing candidate per eligible race
+	candidateIDs, err := s.validateBallot(ctx, tx, electionID, voterID, choices)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Issue the vote token
+	now := time.Now().UTC()
+	tokenHash, err := s.issueVoteToken(ctx, tx, electionID, voterID, channel, tpsID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Insert one vote per race under the same token
+	for i, choice := range choices {
+		vote := &Vote{
+			ElectionID:  electionID,
+			CandidateID: candidateIDs[i],
+			RaceID:      choice.RaceID,
+			IsAbstain:   choice.Abstain,
+			TokenHash:   tokenHash,
+			Channel:     channel,
+			TPSID:       tpsID,
+			VoterType:   enrollment.VoterType,
+			CastAt:      now,
 		}
+		if err := s.voteRepo.InsertVote(ctx, tx, vote); err != nil {
+			return nil, err
+		}
+	}
 
-		// 2. Check eligibility
-		if !vs.IsEligible {
-			return ErrNotEligible
+	// 5. Update stats (optional)
+	if s.statsRepo != nil {
+		for _, candidateID := range candidateIDs {
+			if candidateID == 0 {
+				continue // abstain
+			}
+			if err := s.statsRepo.IncrementCandidateCount(ctx, tx, electionID, candidateID, channel, tpsID); err != nil {
+				return nil, err
+			}
 		}
-		if vs.HasVoted {
-			return ErrAlreadyVoted
+	}
+
+	// 6. Mark the voter as voted, audit and build the result
+	return s.completeCast(ctx, tx, enrollment, vs, channel, tpsID, tokenHash, now, map[string]any{
+		"races": len(choices),
+	})
+}
+
+// lockVoterForCast locks the voter's enrollment and voter_status rows (FOR
+// UPDATE) and checks the voter may still cast a ballot over channel. Every
+// ballot type goes through it, so eligibility rules live in one place.
+func (s *Service) lockVoterForCast(
+	ctx context.Context,
+	tx pgx.Tx,
+	electionID, voterID int64,
+	voterType string,
+	channel string,
+) (*ElectionVoterEnrollment, *VoterStatusEntity, error) {
+	enrollment, err := s.authorizeEnrollment(ctx, tx, electionID, voterID, voterType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vs, err := s.voterRepo.GetStatusForUpdate(ctx, tx, electionID, voterID)
+	if err != nil {
+		return nil, nil, translateNotFound(err, ErrNotEligible)
+	}
+	if !vs.IsEligible {
+		return nil, nil, ErrNotEligible
+	}
+	if vs.HasVoted {
+		return nil, nil, ErrAlreadyVoted
+	}
+	if channel == "ONLINE" && !vs.OnlineAllowed {
+		return nil, nil, ErrMethodNotAllowed
+	}
+	if channel == "TPS" && !vs.TPSAllowed {
+		return nil, nil, ErrMethodNotAllowed
+	}
+	return enrollment, vs, nil
+}
+
+// issueVoteToken inserts the voter's vote token and returns its hash, under
+// which the ballot rows are written.
+func (s *Service) issueVoteToken(ctx context.Context, tx pgx.Tx, electionID, voterID int64, channel string, tpsID *int64, now time.Time) (string, error) {
+	tokenHash := generateTokenHash(electionID, voterID)
+	token := &VoteToken{
+		ElectionID: electionID,
+		VoterID:    voterID,
+		TokenHash:  tokenHash,
+		IssuedAt:   now,
+		Method:     channel,
+		TPSID:      tpsID,
+	}
+	if err := s.voteRepo.InsertToken(ctx, tx, token); err != nil {
+		return "", err
+	}
+	return tokenHash, nil
+}
+
+// castAuditTime is the time recorded in the audit entry of a vote cast at
+// castAt: other votes of the same minute share it.
+func castAuditTime(castAt time.Time) time.Time {
+	return castAt.Truncate(time.Minute)
+}
+
+// completeCast marks the voter as voted, writes the audit entry (extra is
+// merged into its metadata) and builds the result, once the ballot rows are
+// written.
+func (s *Service) completeCast(
+	ctx context.Context,
+	tx pgx.Tx,
+	enrollment *ElectionVoterEnrollment,
+	vs *VoterStatusEntity,
+	channel string,
+	tpsID *int64,
+	tokenHash string,
+	now time.Time,
+	extra map[string]any,
+) (*VoteResultEntity, error) {
+	electionID, voterID := enrollment.ElectionID, enrollment.VoterID
+
+	vs.HasVoted = true
+	method := channel
+	vs.VotingMethod = &method
+	vs.TPSID = tpsID
+	vs.VotedAt = &now
+	vs.TokenHash = &tokenHash
+
+	if err := s.voterRepo.UpdateStatus(ctx, tx, vs); err != nil {
+		return nil, err
+	}
+	if err := s.voterRepo.MarkEnrollmentVoted(ctx, tx, enrollment.ID, now); err != nil {
+		return nil, err
+	}
+
+	// Audit log (same transaction, vote is rolled back if audit fails).
+	// The entry names the voter but not the vote, and its time is kept to
+	// the minute, so it cannot be matched to the vote's cast_at to recover
+	// the voter's choice.
+	if s.auditSvc != nil {
+		metadata := map[string]any{
+			"election_id": electionID,
+			"channel":     channel,
+			"tps_id":      tpsID,
+		}
+		for k, v := range extra {
+			metadata[k] = v
+		}
+		if err := s.auditSvc.Log(ctx, tx, AuditEntry{
+			ElectionID:   &electionID,
+			ActorVoterID: &voterID,
+			Action:       "CAST_VOTE_" + channel,
+			EntityType:   "ELECTION",
+			EntityID:     electionID,
+			Metadata:     metadata,
+			CreatedAt:    castAuditTime(now),
+		}); err != nil {
+			return nil, err
 		}
-		if channel == "ONLINE" && !vs.OnlineAllowed {
-			return ErrMethodNotAllowed
+	}
+
+	var tpsInfo *TPSInfo
+	if tpsID != nil && channel == "TPS" {
+		if tpsEntry, err := s.voteRepo.GetTPSByID(ctx, tx, *tpsID); err == nil {
+			tpsInfo = &TPSInfo{ID: tpsEntry.ID, Code: tpsEntry.Code, Name: tpsEntry.Name}
 		}
-		if channel == "TPS" && !vs.TPSAllowed {
-			return ErrMethodNotAllowed
+	}
+
+	return &VoteResultEntity{
+		ElectionID: electionID,
+		VoterID:    voterID,
+		Method:     channel,
+		VotedAt:    now,
+		TPS:        tpsInfo,
+		Receipt:    ReceiptDetail{TokenHash: tokenHash},
+	}, nil
+}
+
+// validateBallot checks the ballot against the voter's races and returns the
+// candidate ID of every choice (0 for abstain). Elections without races take
+// exactly one choice without race; otherwise every eligible race needs exactly
+// one choice for a candidate of that race or an abstain.
+func (s *Service) validateBallot(ctx context.Context, tx pgx.Tx, electionID, voterID int64, choices []BallotChoice) ([]int64, error) {
+	raceCount, err := s.candidateRepo.CountRaces(ctx, tx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if raceCount == 0 {
+		if len(choices) != 1 || choices[0].RaceID != nil {
+			return nil, ErrBallotIncomplete
+		}
+	} else {
+		races, err := s.candidateRepo.ListEligibleRaces(ctx, tx, electionID, voterID)
+		if err != nil {
+			return nil, err
 		}
+		eligible := make(map[int64]bool, len(races))
+		for _, race := range races {
+			eligible[race.ID] = false
+		}
+		for _, choice := range choices {
+			if choice.RaceID == nil {
+				return nil, ErrBallotIncomplete
+			}
+			chosen, ok := eligible[*choice.RaceID]
+			if !ok {
+				return nil, ErrRaceNotEligible
+			}
+			if chosen {
+				return nil, ErrDuplicateRaceChoice
+			}
+			eligible[*choice.RaceID] = true
+		}
+		if len(races) == 0 || len(choices) != len(races) {
+			return nil, ErrBallotIncomplete
+		}
+	}
 
-		// 3. Get and validate candidate
-		cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, candidateID)
+	candidateIDs := make([]int64, len(choices))
+	for i, choice := range choices {
+		if choice.Abstain {
+			if choice.CandidateID != 0 {
+				return nil, ErrBallotIncomplete
+			}
+			if err := s.ensureAbstainAllowed(ctx, electionID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, choice.CandidateID)
 		if err != nil {
-			return translateNotFound(err, ErrCandidateNotFound)
+			if errors.Is(err, shared.ErrNotFound) {
+				return nil, ErrCandidateNotFound
+			}
+			return nil, err
 		}
-		if cand.ElectionID != electionID {
-			return ErrCandidateNotFound
+		if cand.ElectionID != electionID || !sameRace(cand.RaceID, choice.RaceID) {
+			return nil, ErrCandidateNotFound
 		}
-		// Note: IsActive field removed from Candidate struct
-		// if !cand.IsActive {
-		// 	return ErrCandidateInactive
-		// }
+		candidateIDs[i] = cand.ID
+	}
+	return candidateIDs, nil
+}
 
-		// 4. Generate token hash
-		now := time.Now().UTC()
-		tokenHash := generateTokenHash(electionID, voterID)
+// ensureAbstainAllowed rejects abstain ballots unless the election's mode
+// settings enable them.
+func (s *Service) ensureAbstainAllowed(ctx context.Context, electionID int64) error {
+	e, err := s.electionRepo.GetByID(ctx, electionID)
+	if err != nil {
+		return translateNotFound(err, ErrElectionNotFound)
+	}
+	settings, err := s.loadModeSettings(ctx, e)
+	if err != nil {
+		return err
+	}
+	if !settings.AbstainEnabled {
+		return ErrAbstainNotAllowed
+	}
+	return nil
+}
 
-		// 5. Insert vote token
-		token := &VoteToken{
-			ElectionID: electionID,
-			VoterID:    voterID,
-			TokenHash:  tokenHash,
-			IssuedAt:   now,
-			Method:     channel,
-			TPSID:      tpsID,
-		}
-		if err := s.voteRepo.InsertToken(ctx, tx, token); err != nil {
+// ensureSingleRace rejects per-candidate ballot QR flows in multi-race
+// elections, since one QR can only carry a single choice.
+func (s *Service) ensureSingleRace(ctx context.Context, tx pgx.Tx, electionID int64) error {
+	raceCount, err := s.candidateRepo.CountRaces(ctx, tx, electionID)
+	if err != nil {
+		return err
+	}
+	if raceCount > 0 {
+		return ErrMultiRaceBallotQR
+	}
+	return nil
+}
+
+// ensureTPSVotingOpen checks that a TPS accepts votes: while the election is
+// open, or after it closed when the TPS holds a voting extension that has not
+// ended yet. A nil tpsID accepts any TPS with such an extension.
+func (s *Service) ensureTPSVotingOpen(ctx context.Context, tx pgx.Tx, e *election.Election, tpsID *int64) error {
+	if e.Status == election.ElectionStatusVotingOpen {
+		return nil
+	}
+	if e.Status != election.ElectionStatusVotingClosed && e.Status != election.ElectionStatusClosed {
+		return ErrElectionNotOpen
+	}
+	open, err := s.voteRepo.HasOpenTPSExtension(ctx, tx, e.ID, tpsID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !open {
+		return ErrElectionNotOpen
+	}
+	return nil
+}
+
+func sameRace(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// GetTPSVotingStatus reports which TPS voting step the voter is on, based on
+// the latest check-in in tps_checkins and the election mode settings.
+func (s *Service) GetTPSVotingStatus(ctx context.Context, voterID int64, electionID *int64) (*TPSVotingStatus, error) {
+	if s.db == nil || s.electionRepo == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	electionRow, err := s.resolveElection(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.loadModeSettings(ctx, electionRow)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TPSVotingStatus{
+		ElectionID:      electionRow.ID,
+		RequireBallotQR: settings.TPSSettings.RequireBallotQR != nil && *settings.TPSSettings.RequireBallotQR,
+	}
+	notReady := func(step, reason string) {
+		result.Eligible = false
+		result.Step = step
+		result.Reason = stringPtr(reason)
+	}
+
+	if !settings.TPSEnabled {
+		notReady(TPSStepNotTPSVoter, "TPS_DISABLED")
+		return result, nil
+	}
+
+	err = s.withTx(ctx, func(tx pgx.Tx) error {
+		status, err := s.voterRepo.GetLatestStatus(ctx, tx, voterID, &electionRow.ID)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				notReady(TPSStepNotTPSVoter, "NOT_ELIGIBLE")
+				return nil
+			}
 			return err
 		}
 
-		// 6. Insert vote
-		vote := &Vote{
-			ElectionID:  electionID,
-			CandidateID: cand.ID,
-			TokenHash:   tokenHash,
-			Channel:     channel,
-			TPSID:       tpsID,
-			CastAt:      now,
+		if status.HasVoted {
+			notReady(TPSStepAlreadyVoted, "ALREADY_VOTED")
+			return nil
 		}
-		if err := s.voteRepo.InsertVote(ctx, tx, vote); err != nil {
+		if !status.IsEligible {
+			notReady(TPSStepNotTPSVoter, "NOT_ELIGIBLE")
+			return nil
+		}
+		if !status.TPSAllowed {
+			notReady(TPSStepNotTPSVoter, "TPS_REQUIRED")
+			return nil
+		}
+		checkin, err := s.voteRepo.GetLatestCheckin(ctx, tx, electionRow.ID, voterID)
+		if err != nil && !errors.Is(err, shared.ErrNotFound) {
 			return err
 		}
 
-		// 7. Update voter_status
-		vs.HasVoted = true
-		method := channel
-		vs.VotingMethod = &method
-		vs.TPSID = tpsID
-		vs.VotedAt = &now
-		vs.TokenHash = &tokenHash
-
-		if err := s.voterRepo.UpdateStatus(ctx, tx, vs); err != nil {
+		// Before check-in any extended TPS will do; after it, the voter's TPS must be open.
+		var checkinTPS *int64
+		if checkin != nil {
+			checkinTPS = &checkin.TPSID
+		}
+		if err := s.ensureTPSVotingOpen(ctx, tx, electionRow, checkinTPS); err != nil {
+			if errors.Is(err, ErrElectionNotOpen) {
+				notReady(TPSStepElectionNotOpen, "ELECTION_NOT_OPEN")
+				return nil
+			}
 			return err
 		}
+		if checkin == nil {
+			notReady(TPSStepCheckinRequired, "CHECKIN_REQUIRED")
+			return nil
+		}
 
-		// 8. Update stats (optional)
-		if s.statsRepo != nil {
-			if err := s.statsRepo.IncrementCandidateCount(ctx, tx, electionID, cand.ID, channel, tpsID); err != nil {
-				return err
+		checkinStatus := checkin.Status
+		result.CheckinID = &checkin.ID
+		result.ScanAt = &checkin.ScanAt
+		result.ApprovedAt = checkin.ApprovedAt
+		result.ExpiresAt = checkin.ExpiresAt
+		result.TPS = &TPSInfo{ID: checkin.TPSID}
+		if tpsRow, err := s.voteRepo.GetTPSByID(ctx, tx, checkin.TPSID); err == nil {
+			result.TPS.Code = tpsRow.Code
+			result.TPS.Name = tpsRow.Name
+		}
+
+		now := time.Now().UTC()
+		switch checkin.Status {
+		case tps.CheckinStatusPending:
+			notReady(TPSStepWaitingApproval, "WAITING_APPROVAL")
+		case tps.CheckinStatusRejected:
+			reason := "CHECKIN_REJECTED"
+			if checkin.RejectionReason != nil && *checkin.RejectionReason != "" {
+				reason = *checkin.RejectionReason
+			}
+			notReady(TPSStepCheckinRejected, reason)
+		case tps.CheckinStatusApproved:
+			if checkin.ExpiresAt != nil && !checkin.ExpiresAt.After(now) {
+				checkinStatus = tps.CheckinStatusExpired
+				notReady(TPSStepCheckinExpired, "CHECKIN_EXPIRED")
+				break
+			}
+			result.Eligible = true
+			result.Step = TPSStepReadyToVote
+			if checkin.ExpiresAt != nil {
+				remaining := int64(checkin.ExpiresAt.Sub(now).Seconds())
+				result.RemainingSeconds = &remaining
+			}
+		case tps.CheckinStatusExpired:
+			notReady(TPSStepCheckinExpired, "CHECKIN_EXPIRED")
+		case tps.CheckinStatusUsed, tps.CheckinStatusVoted:
+			notReady(TPSStepAlreadyVoted, "ALREADY_VOTED")
+		default:
+			notReady(TPSStepCheckinRequired, "CHECKIN_REQUIRED")
+		}
+		result.CheckinStatus = &checkinStatus
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetVotingReceipt returns vote receipt without revealing candidate.
+// When electionID is nil the voter's most recent election is used.
+func (s *Service) GetVotingReceipt(ctx context.Context, voterID int64, electionID *int64) (*ReceiptResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	var result *ReceiptResponse
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		status, err := s.voterRepo.GetLatestStatus(ctx, tx, voterID, electionID)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				result = &ReceiptResponse{HasVoted: false, ElectionID: electionID}
+				return nil
 			}
+			return err
 		}
 
-		// 9. Audit log (async-safe, errors ignored)
-		if s.auditSvc != nil {
-			_ = s.auditSvc.Log(ctx, AuditEntry{
-				ActorVoterID: &voterID,
-				Action:       "CAST_VOTE_" + channel,
-				EntityType:   "VOTE",
-				EntityID:     vote.ID,
-				Metadata: map[string]any{
-					"election_id": electionID,
-					"channel":     channel,
-					"tps_id":      tpsID,
-				},
-			})
+		if !status.HasVoted || status.TokenHash == nil {
+			result = &ReceiptResponse{HasVoted: false, ElectionID: &status.ElectionID}
+			return nil
 		}
 
-		// 10. Build result
 		var tpsInfo *TPSInfo
-		if tpsID != nil && channel == "TPS" {
-			tpsEntry, err := s.voteRepo.GetTPSByID(ctx, tx, *tpsID)
-			if err == nil {
-				tpsInfo = &TPSInfo{
-					ID:   tpsEntry.ID,
-					Code: tpsEntry.Code,
-					Name: tpsEntry.Name,
-				}
+		if status.TPSID != nil {
+			tpsInfo = &TPSInfo{ID: *status.TPSID}
+			if tpsRow, err := s.voteRepo.GetTPSByID(ctx, tx, *status.TPSID); err == nil {
+				tpsInfo.Code = tpsRow.Code
+				tpsInfo.Name = tpsRow.Name
 			}
 		}
 
-		result = &VoteResultEntity{
-			ElectionID: electionID,
-			VoterID:    voterID,
-			Method:     channel,
-			VotedAt:    now,
+		result = &ReceiptResponse{
+			HasVoted:   true,
+			ElectionID: &status.ElectionID,
+			Method:     status.VotingMethod,
 			TPS:        tpsInfo,
-			Receipt: ReceiptDetail{
-				TokenHash: tokenHash,
-				Note:      "",
+			VotedAt:    status.VotedAt,
+			Receipt: &ReceiptDetail{
+				TokenHash: *status.TokenHash,
+				Note:      "Simpan kode ini untuk memastikan suara Anda tercatat melalui halaman verifikasi tanda terima.",
 			},
 		}
-
 		return nil
 	})
 
-	if err != nil {
-		return nil, err
+	return result, err
+}
+
+// maxReceiptTokenLength guards the public lookup against oversized input.
+const maxReceiptTokenLength = 128
+
+// VerifyReceipt confirms that a receipt token belongs to a counted vote.
+// It is public, so the response never includes the chosen candidate.
+func (s *Service) VerifyReceipt(ctx context.Context, tokenHash string) (*ReceiptVerificationResponse, error) {
+	if s.db == nil {
+		return nil, errors.New("service not initialized")
 	}
 
-	return result, nil
-}
+	tokenHash = strings.TrimSpace(tokenHash)
+	if !strings.HasPrefix(tokenHash, "vt_") || len(tokenHash) > maxReceiptTokenLength {
+		return nil, ErrInvalidReceipt
+	}
 
-// GetTPSVotingStatus checks if voter is eligible for TPS voting
-func (s *Service) GetTPSVotingStatus(ctx context.Context, voterID int64) (*TPSVotingStatus, error) {
-	// TODO: Implement
-	// Check latest TPS check-in and its status
+	var result *ReceiptVerificationResponse
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		rec, err := s.voteRepo.GetVoteReceipt(ctx, tx, tokenHash)
+		if err != nil {
+			if errors.Is(err, shared.ErrNotFound) {
+				return ErrReceiptNotFound
+			}
+			return err
+		}
 
-	return &TPSVotingStatus{
-		Eligible: false,
-		Reason:   stringPtr("TPS_REQUIRED"),
-	}, nil
-}
+		var tpsInfo *TPSInfo
+		if rec.TPSID != nil {
+			tpsInfo = &TPSInfo{ID: *rec.TPSID}
+			if tpsRow, err := s.voteRepo.GetTPSByID(ctx, tx, *rec.TPSID); err == nil {
+				tpsInfo.Code = tpsRow.Code
+				tpsInfo.Name = tpsRow.Name
+			}
+		}
 
-// GetVotingReceipt returns vote receipt without revealing candidate
-func (s *Service) GetVotingReceipt(ctx context.Context, voterID int64) (*ReceiptResponse, error) {
-	// TODO: Implement
-	// Query voter_status and return receipt info
+		result = &ReceiptVerificationResponse{
+			TokenHash:    tokenHash,
+			Counted:      true,
+			ElectionID:   rec.ElectionID,
+			ElectionName: rec.ElectionName,
+			Channel:      rec.Channel,
+			TPS:          tpsInfo,
+			CastAt:       rec.CastAt,
+		}
+		return nil
+	})
 
-	return &ReceiptResponse{
-		HasVoted: false,
-	}, nil
+	return result, err
 }
 
 func (s *Service) GetLiveCount(ctx context.Context, electionID int64) (map[int64]int64, error) {
@@ -410,10 +1083,10 @@ func (s *Service) GetVoterTPSQR(ctx context.Context, authUser auth.AuthUser, vot
 		return nil, errors.New("service not initialized")
 	}
 
-	if authUser.Role != constants.RoleStudent && authUser.Role != constants.RoleAdmin {
+	if !isVoterRole(authUser.Role) && authUser.Role != constants.RoleAdmin {
 		return nil, ErrNotEligible
 	}
-	if authUser.Role == constants.RoleStudent {
+	if isVoterRole(authUser.Role) {
 		if authUser.VoterID == nil || *authUser.VoterID != voterID {
 			return nil, ErrNotEligible
 		}
@@ -486,12 +1159,11 @@ func (s *Service) SetVoterMethod(ctx context.Context, authUser auth.AuthUser, re
 		return errors.New("service not initialized")
 	}
 
-	if authUser.Role != constants.RoleStudent || authUser.VoterID == nil {
-		return ErrVoterMappingMissing
+	voterID, _, err := voterFromAuth(authUser)
+	if err != nil {
+		return err
 	}
 
-	voterID := *authUser.VoterID
-
 	// Validate method
 	method := strings.ToUpper(req.Method)
 	if method != "ONLINE" && method != "TPS" {
@@ -554,8 +1226,9 @@ func (s *Service) ParseBallotQRForVoter(ctx context.Context, authUser auth.AuthU
 		return nil, errors.New("service not initialized")
 	}
 
-	if authUser.Role != constants.RoleStudent || authUser.VoterID == nil {
-		return nil, ErrVoterMappingMissing
+	voterID, voterType, err := voterFromAuth(authUser)
+	if err != nil {
+		return nil, err
 	}
 
 	payload := strings.TrimSpace(req.BallotQRPayload)
@@ -568,7 +1241,6 @@ func (s *Service) ParseBallotQRForVoter(ctx context.Context, authUser auth.AuthU
 		return nil, ErrInvalidBallotQR
 	}
 
-	voterID := *authUser.VoterID
 	var result *ParseBallotQRResponse
 
 	err = s.withTx(ctx, func(tx pgx.Tx) error {
@@ -577,6 +1249,13 @@ func (s *Service) ParseBallotQRForVoter(ctx context.Context, authUser auth.AuthU
 			return translateNotFound(err, ErrElectionNotFound)
 		}
 
+		if _, err := s.authorizeEnrollment(ctx, tx, qr.ElectionID, voterID, voterType); err != nil {
+			if errors.Is(err, ErrNotEligible) {
+				return ErrElectionMismatch
+			}
+			return err
+		}
+
 		status, err := s.voterRepo.GetStatusForUpdate(ctx, tx, qr.ElectionID, voterID)
 		if err != nil {
 			if errors.Is(err, shared.ErrVoterNotEligible) {
@@ -592,14 +1271,28 @@ func (s *Service) ParseBallotQRForVoter(ctx context.Context, authUser auth.AuthU
 			return ErrNotTPSVoter
 		}
 
-		qrRecord, err := s.voteRepo.FindActiveCandidateQRWithVersion(ctx, tx, qr.ElectionID, qr.CandidateID, qr.Version)
+		qrRecord, err := s.verifyBallotQR(ctx, tx, qr)
 		if err != nil {
-			if errors.Is(err, shared.ErrNotFound) {
-				return ErrInvalidBallotQR
-			}
 			return err
 		}
 
+		if err := s.ensureSingleRace(ctx, tx, qr.ElectionID); err != nil {
+			return err
+		}
+		if err := s.ensurePlurality(ctx, qr.ElectionID); err != nil {
+			return err
+		}
+
+		result = &ParseBallotQRResponse{
+			ElectionID:   electionRow.ID,
+			ElectionName: electionRow.Name,
+			Abstain:      qr.Abstain,
+			Version:      qrRecord.Version,
+		}
+		if qr.Abstain {
+			return s.ensureAbstainAllowed(ctx, qr.ElectionID)
+		}
+
 		cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, qr.CandidateID)
 		if err != nil {
 			return translateNotFound(err, ErrCandidateNotFound)
@@ -607,15 +1300,9 @@ func (s *Service) ParseBallotQRForVoter(ctx context.Context, authUser auth.AuthU
 		if cand.ElectionID != qr.ElectionID {
 			return ErrElectionMismatch
 		}
-
-		result = &ParseBallotQRResponse{
-			ElectionID:      electionRow.ID,
-			ElectionName:    electionRow.Name,
-			CandidateID:     cand.ID,
-			CandidateNumber: fmt.Sprintf("%02d", cand.Number),
-			CandidateName:   cand.Name,
-			Version:         qrRecord.Version,
-		}
+		result.CandidateID = cand.ID
+		result.CandidateNumber = fmt.Sprintf("%02d", cand.Number)
+		result.CandidateName = cand.Name
 		return nil
 	})
 
@@ -628,8 +1315,9 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 		return nil, errors.New("service not initialized")
 	}
 
-	if authUser.Role != constants.RoleStudent || authUser.VoterID == nil {
-		return nil, ErrVoterMappingMissing
+	voterID, voterType, err := voterFromAuth(authUser)
+	if err != nil {
+		return nil, err
 	}
 
 	payload := strings.TrimSpace(req.BallotQRPayload)
@@ -651,21 +1339,23 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 		electionID = *req.ElectionID
 	}
 
-	voterID := *authUser.VoterID
 	var result *CastFromBallotQRResponse
+	var checkinID int64
 
 	err = s.withTx(ctx, func(tx pgx.Tx) error {
 		electionRow, err := s.electionRepo.GetByID(ctx, electionID)
 		if err != nil {
 			return translateNotFound(err, ErrElectionNotFound)
 		}
-		if electionRow.Status != election.ElectionStatusVotingOpen {
-			return ErrElectionNotOpen
-		}
 		if !electionRow.TPSEnabled {
 			return ErrNotTPSVoter
 		}
 
+		enrollment, err := s.authorizeEnrollment(ctx, tx, electionID, voterID, voterType)
+		if err != nil {
+			return err
+		}
+
 		status, err := s.voterRepo.GetStatusForUpdate(ctx, tx, electionID, voterID)
 		if err != nil {
 			if errors.Is(err, shared.ErrVoterNotEligible) {
@@ -699,35 +1389,55 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 		if checkin.ExpiresAt != nil && checkin.ExpiresAt.Before(time.Now().UTC()) {
 			return ErrCheckinExpired
 		}
+		if err := s.ensureTPSVotingOpen(ctx, tx, electionRow, &checkin.TPSID); err != nil {
+			return err
+		}
 
-		// QR validation against active candidate QR code
-		qrRecord, err := s.voteRepo.FindActiveCandidateQRWithVersion(ctx, tx, electionID, qr.CandidateID, qr.Version)
+		// QR signature and validation against active candidate QR code
+		qrRecord, err := s.verifyBallotQR(ctx, tx, qr)
 		if err != nil {
-			if errors.Is(err, shared.ErrNotFound) {
-				return ErrInvalidBallotQR
-			}
 			return err
 		}
 
-		// Candidate validation
-		cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, qr.CandidateID)
-		if err != nil {
-			return translateNotFound(err, ErrCandidateNotFound)
+		if err := s.ensureSingleRace(ctx, tx, electionID); err != nil {
+			return err
 		}
-		if cand.ElectionID != electionID {
-			return ErrElectionMismatch
+		if err := s.ensurePlurality(ctx, electionID); err != nil {
+			return err
+		}
+
+		// Candidate validation (abstain QR has no candidate)
+		var candidateSummary *CandidateSummary
+		var scanCandidateID *int64
+		if qr.Abstain {
+			if err := s.ensureAbstainAllowed(ctx, electionID); err != nil {
+				return err
+			}
+		} else {
+			cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, qr.CandidateID)
+			if err != nil {
+				return translateNotFound(err, ErrCandidateNotFound)
+			}
+			if cand.ElectionID != electionID {
+				return ErrElectionMismatch
+			}
+			candidateSummary = &CandidateSummary{
+				ID:     cand.ID,
+				Number: fmt.Sprintf("%02d", cand.Number),
+				Name:   cand.Name,
+			}
+			scanCandidateID = &qr.CandidateID
 		}
 
 		now := time.Now().UTC()
 		tokenHash := generateTokenHash(electionID, voterID)
-		candidateNumber := fmt.Sprintf("%02d", cand.Number)
 
 		scan := &BallotScan{
 			ElectionID:      electionID,
 			TPSID:           checkin.TPSID,
 			CheckinID:       checkin.ID,
 			VoterID:         voterID,
-			CandidateID:     &qr.CandidateID,
+			CandidateID:     scanCandidateID,
 			CandidateQRID:   &qrRecord.ID,
 			RawPayload:      payload,
 			PayloadValid:    true,
@@ -743,9 +1453,11 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 		vote := &Vote{
 			ElectionID:    electionID,
 			CandidateID:   qr.CandidateID,
+			IsAbstain:     qr.Abstain,
 			TokenHash:     tokenHash,
 			Channel:       "TPS",
 			TPSID:         &checkin.TPSID,
+			VoterType:     enrollment.VoterType,
 			CandidateQRID: &qrRecord.ID,
 			BallotScanID:  &scan.ID,
 			CastAt:        now,
@@ -766,10 +1478,35 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 		if err := s.voterRepo.UpdateStatus(ctx, tx, status); err != nil {
 			return err
 		}
+		if err := s.voterRepo.MarkEnrollmentVoted(ctx, tx, enrollment.ID, now); err != nil {
+			return err
+		}
 
 		if err := s.voteRepo.MarkCheckinUsed(ctx, tx, checkin.ID, now); err != nil {
 			return err
 		}
+		checkinID = checkin.ID
+
+		if s.auditSvc != nil {
+			if err := s.auditSvc.Log(ctx, tx, AuditEntry{
+				ElectionID:   &electionID,
+				ActorVoterID: &voterID,
+				ActorUserID:  &authUser.ID,
+				Action:       "CAST_VOTE_TPS_QR",
+				EntityType:   "ELECTION",
+				EntityID:     electionID,
+				Metadata: map[string]any{
+					"election_id":    electionID,
+					"channel":        "TPS",
+					"tps_id":         checkin.TPSID,
+					"checkin_id":     checkin.ID,
+					"ballot_scan_id": scan.ID,
+				},
+				CreatedAt: castAuditTime(now),
+			}); err != nil {
+				return err
+			}
+		}
 
 		tpsInfo := TPSInfo{ID: checkin.TPSID}
 		if tpsRow, err := s.voteRepo.GetTPSByID(ctx, tx, checkin.TPSID); err == nil {
@@ -783,17 +1520,19 @@ func (s *Service) CastVoteFromBallotQR(ctx context.Context, authUser auth.AuthUs
 			Channel:    "TPS",
 			TPS:        tpsInfo,
 			Status:     tps.CheckinStatusVoted,
-			Candidate: &CandidateSummary{
-				ID:     cand.ID,
-				Number: candidateNumber,
-				Name:   cand.Name,
-			},
+			Candidate:  candidateSummary,
+			Abstain:    qr.Abstain,
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return result, err
+	s.notifyVoteCast(electionID, "TPS", &result.TPS.ID, result.VotedAt)
+	s.notifyCheckinVoted(electionID, result.TPS.ID, checkinID)
+	return result, nil
 }
 
 // ScanCandidateAtTPS handles QR ballot scan after check-in approval.
@@ -824,6 +1563,7 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 			qr = &BallotQR{
 				ElectionID:  qrRecord.ElectionID,
 				CandidateID: qrRecord.CandidateID,
+				Abstain:     qrRecord.CandidateID == 0,
 				Version:     qrRecord.Version,
 			}
 		} else {
@@ -832,10 +1572,10 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 				return ErrInvalidBallotQR
 			}
 			qr = parsed
-			// Try resolve to candidate_qr_codes for metadata
-			qrRecord, _ = s.voteRepo.FindActiveCandidateQRWithVersion(ctx, tx, qr.ElectionID, qr.CandidateID, qr.Version)
-			if qrRecord == nil {
-				qrRecord, _ = s.voteRepo.FindActiveCandidateQR(ctx, tx, qr.ElectionID, qr.CandidateID)
+			// Payload written differently from the issued token: the signature decides
+			qrRecord, err = s.verifyBallotQR(ctx, tx, qr)
+			if err != nil {
+				return err
 			}
 		}
 
@@ -863,11 +1603,18 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 		if err != nil {
 			return translateNotFound(err, ErrElectionNotFound)
 		}
-		if election.Status != "VOTING_OPEN" || !election.TPSEnabled {
+		if !election.TPSEnabled {
 			return ErrElectionNotOpen
 		}
+		if err := s.ensureTPSVotingOpen(ctx, tx, election, &checkin.TPSID); err != nil {
+			return err
+		}
 
-		// Lock voter_status
+		// Lock election enrollment and voter_status
+		enrollment, err := s.authorizeEnrollment(ctx, tx, qr.ElectionID, checkin.VoterID, "")
+		if err != nil {
+			return err
+		}
 		status, err := s.voterRepo.GetStatusForUpdate(ctx, tx, qr.ElectionID, checkin.VoterID)
 		if err != nil {
 			return translateNotFound(err, ErrNotEligible)
@@ -879,10 +1626,24 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 			return ErrMethodNotAllowed
 		}
 
-		// Validate candidate belongs to election
-		cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, qr.CandidateID)
-		if err != nil || cand.ElectionID != qr.ElectionID {
-			return ErrCandidateNotFound
+		// Validate candidate belongs to election (or abstain is enabled)
+		var scanCandidateID *int64
+		if qr.Abstain {
+			if err := s.ensureAbstainAllowed(ctx, qr.ElectionID); err != nil {
+				return err
+			}
+		} else {
+			cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, qr.CandidateID)
+			if err != nil || cand.ElectionID != qr.ElectionID {
+				return ErrCandidateNotFound
+			}
+			scanCandidateID = &qr.CandidateID
+		}
+		if err := s.ensureSingleRace(ctx, tx, qr.ElectionID); err != nil {
+			return err
+		}
+		if err := s.ensurePlurality(ctx, qr.ElectionID); err != nil {
+			return err
 		}
 
 		now := time.Now().UTC()
@@ -901,7 +1662,7 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 			TPSID:           req.TPSID,
 			CheckinID:       checkin.ID,
 			VoterID:         checkin.VoterID,
-			CandidateID:     &qr.CandidateID,
+			CandidateID:     scanCandidateID,
 			CandidateQRID:   candidateQRID,
 			RawPayload:      req.Payload,
 			PayloadValid:    payloadValid,
@@ -919,9 +1680,11 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 		vote := &Vote{
 			ElectionID:    qr.ElectionID,
 			CandidateID:   qr.CandidateID,
+			IsAbstain:     qr.Abstain,
 			TokenHash:     tokenHash,
 			Channel:       "TPS",
 			TPSID:         &req.TPSID,
+			VoterType:     enrollment.VoterType,
 			CandidateQRID: candidateQRID,
 			BallotScanID:  &scan.ID,
 			CastAt:        now,
@@ -939,12 +1702,37 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 		if err := s.voterRepo.UpdateStatus(ctx, tx, status); err != nil {
 			return err
 		}
+		if err := s.voterRepo.MarkEnrollmentVoted(ctx, tx, enrollment.ID, now); err != nil {
+			return err
+		}
 
 		// Update checkin status to USED
 		if err := s.voteRepo.MarkCheckinUsed(ctx, tx, checkin.ID, now); err != nil {
 			return err
 		}
 
+		// Audit log (operator is the actor, voter recorded for traceability)
+		if s.auditSvc != nil {
+			if err := s.auditSvc.Log(ctx, tx, AuditEntry{
+				ElectionID:   &qr.ElectionID,
+				ActorVoterID: &checkin.VoterID,
+				ActorUserID:  &authUser.ID,
+				Action:       "CAST_VOTE_TPS_SCAN",
+				EntityType:   "ELECTION",
+				EntityID:     qr.ElectionID,
+				Metadata: map[string]any{
+					"election_id":    qr.ElectionID,
+					"channel":        "TPS",
+					"tps_id":         req.TPSID,
+					"checkin_id":     checkin.ID,
+					"ballot_scan_id": scan.ID,
+				},
+				CreatedAt: castAuditTime(now),
+			}); err != nil {
+				return err
+			}
+		}
+
 		result = &VoteResultEntity{
 			ElectionID: qr.ElectionID,
 			VoterID:    checkin.VoterID,
@@ -958,6 +1746,11 @@ func (s *Service) ScanCandidateAtTPS(ctx context.Context, authUser auth.AuthUser
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return result, err
+	s.notifyVoteCast(result.ElectionID, "TPS", &req.TPSID, result.VotedAt)
+	s.notifyCheckinVoted(result.ElectionID, req.TPSID, req.CheckinID)
+	return result, nil
 }
@@ -0,0 +1,165 @@
+package voting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/shared"
+)
+
+// errBallotQRSecretUnset is returned by ballot QR operations when no
+// BALLOT_QR_SECRET is configured.
+var errBallotQRSecretUnset = errors.New("ballot qr secret not configured")
+
+// verifyBallotQR checks the signature of qr against the key of its version
+// and returns its active candidate_qr_codes row. Unsigned, forged and rotated
+// codes are rejected.
+func (s *Service) verifyBallotQR(ctx context.Context, tx pgx.Tx, qr *BallotQR) (*CandidateQR, error) {
+	if len(s.ballotQRSecret) == 0 {
+		return nil, errBallotQRSecretUnset
+	}
+	if !qr.verify(ballotQRKey(s.ballotQRSecret, qr.ElectionID, qr.Version)) {
+		return nil, ErrInvalidBallotQR
+	}
+
+	record, err := s.voteRepo.FindActiveCandidateQRWithVersion(ctx, tx, qr.ElectionID, qr.CandidateID, qr.Version)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil, ErrInvalidBallotQR
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetBallotQRSheet returns the active ballot QR codes of an election for
+// printing. It is empty until RotateBallotQR first issues codes.
+func (s *Service) GetBallotQRSheet(ctx context.Context, electionID int64) (*BallotQRSheet, error) {
+	if s.db == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	var sheet *BallotQRSheet
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		if _, err := s.electionRepo.GetByID(ctx, electionID); err != nil {
+			return translateNotFound(err, ErrElectionNotFound)
+		}
+		codes, err := s.voteRepo.ListActiveCandidateQRs(ctx, tx, electionID)
+		if err != nil {
+			return err
+		}
+		sheet, err = s.ballotQRSheet(ctx, tx, electionID, codes)
+		return err
+	})
+	return sheet, err
+}
+
+// RotateBallotQR issues a new version of the ballot QR codes of an election,
+// signed with that version's key: one code per approved candidate, and the
+// abstain code when abstaining is allowed. Codes of earlier versions stop
+// working.
+func (s *Service) RotateBallotQR(ctx context.Context, authUser auth.AuthUser, electionID int64) (*BallotQRSheet, error) {
+	if s.db == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	if len(s.ballotQRSecret) == 0 {
+		return nil, errBallotQRSecretUnset
+	}
+
+	var sheet *BallotQRSheet
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		if _, err := s.electionRepo.GetByID(ctx, electionID); err != nil {
+			return translateNotFound(err, ErrElectionNotFound)
+		}
+		if err := s.ensureSingleRace(ctx, tx, electionID); err != nil {
+			return err
+		}
+		if err := s.ensurePlurality(ctx, electionID); err != nil {
+			return err
+		}
+
+		version, err := s.voteRepo.NextBallotQRVersion(ctx, tx, electionID)
+		if err != nil {
+			return err
+		}
+		key := ballotQRKey(s.ballotQRSecret, electionID, version)
+		now := time.Now().UTC()
+		if err := s.voteRepo.DeactivateCandidateQRs(ctx, tx, electionID, now); err != nil {
+			return err
+		}
+
+		ballots := []BallotQR{}
+		candidates, err := s.candidateRepo.ListApprovedByElection(ctx, tx, electionID)
+		if err != nil {
+			return err
+		}
+		for _, cand := range candidates {
+			ballots = append(ballots, BallotQR{ElectionID: electionID, CandidateID: cand.ID, Version: version})
+		}
+		if err := s.ensureAbstainAllowed(ctx, electionID); err == nil {
+			ballots = append(ballots, BallotQR{ElectionID: electionID, Abstain: true, Version: version})
+		} else if !errors.Is(err, ErrAbstainNotAllowed) {
+			return err
+		}
+
+		codes := make([]CandidateQR, 0, len(ballots))
+		for i := range ballots {
+			code := CandidateQR{
+				ElectionID:  electionID,
+				CandidateID: ballots[i].CandidateID,
+				Version:     version,
+				QRToken:     signBallotQR(key, &ballots[i]),
+			}
+			if err := s.voteRepo.InsertCandidateQR(ctx, tx, &code); err != nil {
+				return err
+			}
+			codes = append(codes, code)
+		}
+
+		if s.auditSvc != nil {
+			if err := s.auditSvc.Log(ctx, tx, AuditEntry{
+				ElectionID:  &electionID,
+				ActorUserID: &authUser.ID,
+				Action:      "ROTATE_BALLOT_QR",
+				EntityType:  "ELECTION",
+				EntityID:    electionID,
+				Metadata: map[string]any{
+					"version": version,
+					"codes":   len(codes),
+				},
+				CreatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+
+		sheet, err = s.ballotQRSheet(ctx, tx, electionID, codes)
+		return err
+	})
+	return sheet, err
+}
+
+func (s *Service) ballotQRSheet(ctx context.Context, tx pgx.Tx, electionID int64, codes []CandidateQR) (*BallotQRSheet, error) {
+	sheet := &BallotQRSheet{ElectionID: electionID, Codes: []BallotQRSheetRow{}}
+	for _, code := range codes {
+		sheet.Version = code.Version
+		row := BallotQRSheetRow{Abstain: code.CandidateID == 0, Payload: code.QRToken}
+		if !row.Abstain {
+			cand, err := s.candidateRepo.GetByIDWithTx(ctx, tx, code.CandidateID)
+			if err != nil {
+				return nil, translateNotFound(err, ErrCandidateNotFound)
+			}
+			row.CandidateID = cand.ID
+			row.CandidateNumber = fmt.Sprintf("%02d", cand.Number)
+			row.CandidateName = cand.Name
+		}
+		sheet.Codes = append(sheet.Codes, row)
+	}
+	return sheet, nil
+}
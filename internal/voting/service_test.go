@@ -0,0 +1,238 @@
+package voting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"pemira-api/internal/candidate"
+	"pemira-api/internal/election"
+	"pemira-api/internal/shared"
+	"pemira-api/internal/tps"
+)
+
+// statusRepo is a VoterRepository that accepts status updates.
+type statusRepo struct {
+	VoterRepository
+}
+
+func (statusRepo) UpdateStatus(ctx context.Context, tx pgx.Tx, status *VoterStatusEntity) error {
+	return nil
+}
+
+func (statusRepo) MarkEnrollmentVoted(ctx context.Context, tx pgx.Tx, enrollmentID int64, votedAt time.Time) error {
+	return nil
+}
+
+// recordingAudit is an AuditService that keeps the entries it is given.
+type recordingAudit struct {
+	entries []AuditEntry
+}
+
+func (a *recordingAudit) Log(ctx context.Context, tx pgx.Tx, entry AuditEntry) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func TestCompleteCastAuditTimeDiffersFromCastAt(t *testing.T) {
+	audit := &recordingAudit{}
+	s := &Service{voterRepo: statusRepo{}, auditSvc: audit}
+	castAt := time.Date(2025, 6, 13, 9, 41, 27, 123456000, time.UTC)
+
+	result, err := s.completeCast(context.Background(), nil,
+		&ElectionVoterEnrollment{ID: 5, ElectionID: 1, VoterID: 7}, &VoterStatusEntity{},
+		"ONLINE", nil, "hash", castAt, nil)
+	if err != nil {
+		t.Fatalf("completeCast: %v", err)
+	}
+	if len(audit.entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(audit.entries))
+	}
+
+	// votes.cast_at gets the cast time; the audit entry must not match it
+	logged := audit.entries[0].CreatedAt
+	if logged.IsZero() || logged.Equal(result.VotedAt) {
+		t.Errorf("audit created_at %v, cast_at %v", logged, result.VotedAt)
+	}
+	if want := time.Date(2025, 6, 13, 9, 41, 0, 0, time.UTC); !logged.Equal(want) {
+		t.Errorf("audit created_at %v, want %v", logged, want)
+	}
+}
+
+// castStore keeps the rows of one voter's plurality cast in a single-race
+// election with candidate 3.
+type castStore struct {
+	VoterRepository
+	VoteRepository
+	CandidateRepository
+	status VoterStatusEntity
+	tokens []*VoteToken
+	votes  []*Vote
+}
+
+func newCastStore(onlineAllowed, tpsAllowed bool) *castStore {
+	return &castStore{status: VoterStatusEntity{ElectionID: 1, VoterID: 7, IsEligible: true, OnlineAllowed: onlineAllowed, TPSAllowed: tpsAllowed}}
+}
+
+func (c *castStore) GetEnrollmentForUpdate(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*ElectionVoterEnrollment, error) {
+	status := "VERIFIED"
+	if c.status.HasVoted {
+		status = "VOTED"
+	}
+	return &ElectionVoterEnrollment{ID: 5, ElectionID: electionID, VoterID: voterID, VoterType: "STUDENT", Status: status}, nil
+}
+
+func (c *castStore) GetStatusForUpdate(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (*VoterStatusEntity, error) {
+	status := c.status
+	return &status, nil
+}
+
+func (c *castStore) UpdateStatus(ctx context.Context, tx pgx.Tx, status *VoterStatusEntity) error {
+	c.status = *status
+	return nil
+}
+
+func (c *castStore) MarkEnrollmentVoted(ctx context.Context, tx pgx.Tx, enrollmentID int64, votedAt time.Time) error {
+	return nil
+}
+
+func (c *castStore) CountRaces(ctx context.Context, tx pgx.Tx, electionID int64) (int, error) {
+	return 0, nil
+}
+
+func (c *castStore) GetByIDWithTx(ctx context.Context, tx pgx.Tx, candidateID int64) (*candidate.Candidate, error) {
+	if candidateID != 3 {
+		return nil, shared.ErrNotFound
+	}
+	return &candidate.Candidate{ID: 3, ElectionID: 1}, nil
+}
+
+func (c *castStore) InsertToken(ctx context.Context, tx pgx.Tx, token *VoteToken) error {
+	c.tokens = append(c.tokens, token)
+	return nil
+}
+
+func (c *castStore) InsertVote(ctx context.Context, tx pgx.Tx, vote *Vote) error {
+	c.votes = append(c.votes, vote)
+	return nil
+}
+
+func (c *castStore) GetTPSByID(ctx context.Context, tx pgx.Tx, tpsID int64) (*tps.TPS, error) {
+	return &tps.TPS{ID: tpsID, Code: "TPS01", Name: "TPS Gedung A"}, nil
+}
+
+type abstainSettings bool
+
+func (a abstainSettings) GetModeSettings(ctx context.Context, id int64) (*election.ModeSettingsDTO, error) {
+	return &election.ModeSettingsDTO{ElectionID: id, OnlineEnabled: true, TPSEnabled: true, AbstainEnabled: bool(a), VotingSystem: election.VotingSystemPlurality}, nil
+}
+
+func newCastService(store *castStore, abstain bool) (*Service, *recordingAudit) {
+	audit := &recordingAudit{}
+	return &Service{
+		electionRepo:  openElectionRepo{e: &election.Election{ID: 1}},
+		voterRepo:     store,
+		candidateRepo: store,
+		voteRepo:      store,
+		auditSvc:      audit,
+		modeSettings:  abstainSettings(abstain),
+	}, audit
+}
+
+func TestCastVoteTxPlurality(t *testing.T) {
+	ctx := context.Background()
+	store := newCastStore(true, true)
+	s, audit := newCastService(store, false)
+
+	result, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(3, false, nil), "ONLINE", nil)
+	if err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if len(store.tokens) != 1 || len(store.votes) != 1 {
+		t.Fatalf("got %d tokens, %d votes, want 1 each", len(store.tokens), len(store.votes))
+	}
+	vote := store.votes[0]
+	if vote.CandidateID != 3 || vote.IsAbstain || vote.Channel != "ONLINE" || vote.TPSID != nil ||
+		vote.TokenHash != store.tokens[0].TokenHash || vote.VoterType != "STUDENT" {
+		t.Errorf("vote %+v", vote)
+	}
+	if !store.status.HasVoted || *store.status.VotingMethod != "ONLINE" || *store.status.TokenHash != vote.TokenHash {
+		t.Errorf("voter status %+v", store.status)
+	}
+	if result.Receipt.TokenHash != vote.TokenHash || len(audit.entries) != 1 || audit.entries[0].Action != "CAST_VOTE_ONLINE" {
+		t.Errorf("result %+v, audit %+v", result, audit.entries)
+	}
+
+	// Duplicate vote
+	if _, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(3, false, nil), "ONLINE", nil); !errors.Is(err, ErrAlreadyVoted) {
+		t.Errorf("second cast: got %v, want ErrAlreadyVoted", err)
+	}
+	if len(store.votes) != 1 || len(store.tokens) != 1 {
+		t.Errorf("second cast wrote %d votes, %d tokens", len(store.votes), len(store.tokens))
+	}
+}
+
+func TestCastVoteTxTPSChannel(t *testing.T) {
+	ctx := context.Background()
+	tpsID := int64(4)
+
+	s, _ := newCastService(newCastStore(true, false), false)
+	if _, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(3, false, nil), "TPS", &tpsID); !errors.Is(err, ErrMethodNotAllowed) {
+		t.Errorf("TPS not allowed: got %v, want ErrMethodNotAllowed", err)
+	}
+
+	store := newCastStore(false, true)
+	s, audit := newCastService(store, false)
+	result, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(3, false, nil), "TPS", &tpsID)
+	if err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if vote := store.votes[0]; vote.Channel != "TPS" || vote.TPSID == nil || *vote.TPSID != tpsID {
+		t.Errorf("vote %+v", vote)
+	}
+	if token := store.tokens[0]; token.Method != "TPS" || token.TPSID == nil || *token.TPSID != tpsID {
+		t.Errorf("token %+v", token)
+	}
+	if *store.status.VotingMethod != "TPS" || store.status.TPSID == nil || *store.status.TPSID != tpsID {
+		t.Errorf("voter status %+v", store.status)
+	}
+	if result.Method != "TPS" || result.TPS == nil || result.TPS.Code != "TPS01" {
+		t.Errorf("result %+v", result)
+	}
+	if entry := audit.entries[0]; entry.Action != "CAST_VOTE_TPS" || entry.Metadata["tps_id"] != &tpsID {
+		t.Errorf("audit %+v", entry)
+	}
+}
+
+func TestCastVoteTxAbstain(t *testing.T) {
+	ctx := context.Background()
+
+	store := newCastStore(true, true)
+	s, _ := newCastService(store, false)
+	if _, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(0, true, nil), "ONLINE", nil); !errors.Is(err, ErrAbstainNotAllowed) {
+		t.Errorf("abstain disabled: got %v, want ErrAbstainNotAllowed", err)
+	}
+	if len(store.votes) != 0 || store.status.HasVoted {
+		t.Errorf("rejected abstain wrote %d votes, has_voted %v", len(store.votes), store.status.HasVoted)
+	}
+
+	s, _ = newCastService(store, true)
+	if _, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", ballotChoices(0, true, nil), "ONLINE", nil); err != nil {
+		t.Fatalf("abstain: %v", err)
+	}
+	if vote := store.votes[0]; !vote.IsAbstain || vote.CandidateID != 0 {
+		t.Errorf("vote %+v", vote)
+	}
+	if !store.status.HasVoted {
+		t.Error("abstaining voter not marked as voted")
+	}
+
+	// An abstain ballot names no candidate
+	s, _ = newCastService(newCastStore(true, true), true)
+	if _, err := s.castVoteTx(ctx, nil, 1, 7, "STUDENT", []BallotChoice{{CandidateID: 3, Abstain: true}}, "ONLINE", nil); !errors.Is(err, ErrBallotIncomplete) {
+		t.Errorf("abstain with candidate: got %v, want ErrBallotIncomplete", err)
+	}
+}
@@ -0,0 +1,488 @@
+package voting
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/election"
+	"pemira-api/internal/shared"
+	"pemira-api/internal/shared/constants"
+	"pemira-api/internal/tps"
+	"pemira-api/internal/ws"
+)
+
+const (
+	// maxSyncBatchItems caps the items of one TPS sync upload.
+	maxSyncBatchItems = 500
+	// maxSyncClientIDLength caps client generated item IDs.
+	maxSyncClientIDLength = 100
+	// syncClockSkew is how far an item's occurred_at may lie ahead of the
+	// server, or before the approval of a ballot's check-in.
+	syncClockSkew = 5 * time.Minute
+	// syncCloseGrace is how long after voting closed at a TPS items queued
+	// before the close are still accepted.
+	syncCloseGrace = 2 * time.Hour
+)
+
+// syncApplied is what a sync item touched. It is filled as far as the item
+// got, so rejected items still report the voter.
+type syncApplied struct {
+	voterID   int64
+	checkinID int64
+	checkin   *tps.TPSCheckin   // accepted CHECKIN
+	voter     *VoterIdentity    // voter of an accepted CHECKIN, for the queue event
+	vote      *VoteResultEntity // accepted BALLOT
+}
+
+// SyncTPS applies a batch of check-ins and ballot casts a TPS panel queued
+// while offline. The batch runs in one transaction and every item in its own
+// savepoint, through the checks of the online flow (CheckinService for
+// check-ins, castVote for ballots), so a rejected item is rolled back alone
+// and reported with its reason, while an error rolls back the whole batch.
+// Items are recorded by client ID: uploading an item again returns its first
+// outcome instead of applying it twice.
+func (s *Service) SyncTPS(ctx context.Context, authUser auth.AuthUser, electionID, tpsID int64, req TPSSyncRequest) (*TPSSyncResponse, error) {
+	if s.db == nil || s.checkins == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	// TPS operators sync their own TPS only
+	if authUser.Role == constants.RoleTPSOperator && (authUser.TPSID == nil || *authUser.TPSID != tpsID) {
+		return nil, ErrTPSNotFound
+	}
+
+	now := time.Now().UTC()
+	if err := validateSyncBatch(req.Items, now); err != nil {
+		return nil, err
+	}
+
+	e, err := s.electionRepo.GetByID(ctx, electionID)
+	if err != nil {
+		return nil, translateNotFound(err, ErrElectionNotFound)
+	}
+	if !e.TPSEnabled {
+		return nil, ErrMethodNotAllowed
+	}
+	ranked, err := s.isRanked(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TPSSyncResponse{Results: make([]TPSSyncItemResult, 0, len(req.Items))}
+	var applied []*syncApplied
+
+	err = s.withTx(ctx, func(tx pgx.Tx) error {
+		duplicates := 0
+		for _, item := range req.Items {
+			result, done, err := s.syncItem(ctx, tx, authUser, e, ranked, tpsID, item, now)
+			if err != nil {
+				return err
+			}
+			resp.Results = append(resp.Results, result)
+			switch {
+			case result.Duplicate:
+				duplicates++
+			case done != nil:
+				applied = append(applied, done)
+			}
+			if result.Status == SyncStatusAccepted {
+				resp.Accepted++
+			} else {
+				resp.Rejected++
+			}
+		}
+
+		if s.auditSvc != nil {
+			return s.auditSvc.Log(ctx, tx, AuditEntry{
+				ElectionID:  &e.ID,
+				ActorUserID: &authUser.ID,
+				Action:      "TPS_SYNC_BATCH",
+				EntityType:  "TPS",
+				EntityID:    tpsID,
+				Metadata: map[string]any{
+					"tps_id":     tpsID,
+					"items":      len(req.Items),
+					"accepted":   resp.Accepted,
+					"rejected":   resp.Rejected,
+					"duplicates": duplicates,
+				},
+				CreatedAt: now,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, done := range applied {
+		if done.checkin != nil {
+			scanAt := done.checkin.ScanAt
+			event := ws.CheckinEvent{
+				CheckinID:  done.checkin.ID,
+				ElectionID: done.checkin.ElectionID,
+				TPSID:      done.checkin.TPSID,
+				Status:     done.checkin.Status,
+				ScanAt:     &scanAt,
+			}
+			if done.voter != nil {
+				event.Voter = done.voter
+			}
+			ws.PublishCheckin(s.events, ws.EventCheckinNew, event)
+		}
+		if done.vote != nil {
+			s.notifyVoteCast(e.ID, "TPS", &tpsID, done.vote.VotedAt)
+			s.notifyCheckinVoted(e.ID, tpsID, done.checkinID)
+		}
+	}
+	return resp, nil
+}
+
+// validateSyncBatch rejects batches the server cannot key or apply at all.
+// Items that are well formed but fail a check are rejected one by one.
+func validateSyncBatch(items []TPSSyncItemRequest, now time.Time) error {
+	if len(items) == 0 || len(items) > maxSyncBatchItems {
+		return ErrInvalidSyncBatch
+	}
+	for _, item := range items {
+		clientID := strings.TrimSpace(item.ClientID)
+		if clientID == "" || clientID != item.ClientID || len(clientID) > maxSyncClientIDLength {
+			return ErrInvalidSyncBatch
+		}
+		if item.OccurredAt.IsZero() || item.OccurredAt.After(now.Add(syncClockSkew)) {
+			return ErrInvalidSyncBatch
+		}
+		switch item.Type {
+		case SyncItemCheckin:
+			if item.VoterID <= 0 {
+				return ErrInvalidSyncBatch
+			}
+		case SyncItemBallot:
+			if item.CheckinClientID == "" && item.CheckinID <= 0 {
+				return ErrInvalidSyncBatch
+			}
+		default:
+			return ErrInvalidSyncBatch
+		}
+	}
+	return nil
+}
+
+// syncItem claims the client ID of an item and applies it in a savepoint. An
+// error aborts the whole batch; rejections are results.
+func (s *Service) syncItem(
+	ctx context.Context,
+	tx pgx.Tx,
+	authUser auth.AuthUser,
+	e *election.Election,
+	ranked bool,
+	tpsID int64,
+	item TPSSyncItemRequest,
+	now time.Time,
+) (TPSSyncItemResult, *syncApplied, error) {
+	record := &TPSSyncItem{
+		TPSID:      tpsID,
+		ClientID:   item.ClientID,
+		ElectionID: e.ID,
+		ItemType:   item.Type,
+		Status:     "PENDING",
+		OccurredAt: item.OccurredAt,
+		SyncedBy:   &authUser.ID,
+	}
+	claimed, err := s.voteRepo.ClaimSyncItem(ctx, tx, record)
+	if err != nil {
+		return TPSSyncItemResult{}, nil, err
+	}
+	if !claimed {
+		stored, err := s.voteRepo.GetSyncItem(ctx, tx, tpsID, item.ClientID)
+		if err != nil {
+			return TPSSyncItemResult{}, nil, err
+		}
+		result := syncItemResult(stored)
+		result.Duplicate = true
+		return result, nil, nil
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return TPSSyncItemResult{}, nil, err
+	}
+	done, applyErr := s.applySyncItem(ctx, sp, authUser, e, ranked, tpsID, item, now)
+	if applyErr == nil {
+		if err := sp.Commit(ctx); err != nil {
+			return TPSSyncItemResult{}, nil, err
+		}
+		record.Status = SyncStatusAccepted
+	} else {
+		if err := sp.Rollback(ctx); err != nil {
+			return TPSSyncItemResult{}, nil, err
+		}
+		code, message, ok := syncRejection(applyErr)
+		if !ok {
+			return TPSSyncItemResult{}, nil, applyErr
+		}
+		if code == "ALREADY_VOTED" && done.voterID != 0 {
+			code, message = s.alreadyVotedRejection(ctx, tx, e.ID, done.voterID)
+		}
+		record.Status = SyncStatusRejected
+		record.Reason = &code
+		record.Message = &message
+	}
+
+	if done.voterID != 0 {
+		record.VoterID = &done.voterID
+	}
+	if done.checkinID != 0 {
+		record.CheckinID = &done.checkinID
+	}
+	if err := s.voteRepo.FinishSyncItem(ctx, tx, record); err != nil {
+		return TPSSyncItemResult{}, nil, err
+	}
+
+	if record.Status != SyncStatusAccepted {
+		done = nil
+	}
+	return syncItemResult(record), done, nil
+}
+
+func (s *Service) applySyncItem(
+	ctx context.Context,
+	tx pgx.Tx,
+	authUser auth.AuthUser,
+	e *election.Election,
+	ranked bool,
+	tpsID int64,
+	item TPSSyncItemRequest,
+	now time.Time,
+) (*syncApplied, error) {
+	if item.Type == SyncItemBallot {
+		return s.applySyncBallot(ctx, tx, e, ranked, tpsID, item, now)
+	}
+
+	done := &syncApplied{voterID: item.VoterID}
+	if err := s.ensureSyncWindow(ctx, tx, e, tpsID, item.OccurredAt, now); err != nil {
+		return done, err
+	}
+	checkin, err := s.checkins.ApplyOfflineCheckin(ctx, tx, tps.OfflineCheckin{
+		ElectionID:     e.ID,
+		TPSID:          tpsID,
+		VoterID:        item.VoterID,
+		OperatorUserID: authUser.ID,
+		ScannedAt:      item.OccurredAt,
+	})
+	if err != nil {
+		return done, err
+	}
+	done.checkin = checkin
+	done.checkinID = checkin.ID
+	done.voter, _ = s.voterRepo.GetIdentity(ctx, tx, item.VoterID)
+	return done, nil
+}
+
+// applySyncBallot casts a queued ballot with the checks of CastTPSVote, using
+// the check-in the ballot references instead of the voter's latest one.
+func (s *Service) applySyncBallot(
+	ctx context.Context,
+	tx pgx.Tx,
+	e *election.Election,
+	ranked bool,
+	tpsID int64,
+	item TPSSyncItemRequest,
+	now time.Time,
+) (*syncApplied, error) {
+	done := &syncApplied{checkinID: item.CheckinID}
+
+	// A check-in queued offline is known by its client ID
+	if item.CheckinClientID != "" {
+		ref, err := s.voteRepo.GetSyncItem(ctx, tx, tpsID, item.CheckinClientID)
+		if errors.Is(err, shared.ErrNotFound) {
+			return done, ErrSyncCheckinMissing
+		}
+		if err != nil {
+			return done, err
+		}
+		if ref.ItemType != SyncItemCheckin || ref.Status != SyncStatusAccepted || ref.CheckinID == nil {
+			return done, ErrSyncCheckinMissing
+		}
+		done.checkinID = *ref.CheckinID
+	}
+
+	checkin, err := s.voteRepo.GetCheckinByID(ctx, tx, done.checkinID)
+	if errors.Is(err, shared.ErrNotFound) {
+		return done, ErrTPSCheckinNotFound
+	}
+	if err != nil {
+		return done, err
+	}
+	done.voterID = checkin.VoterID
+
+	if checkin.TPSID != tpsID {
+		return done, ErrTPSNotFound
+	}
+	if checkin.ElectionID != e.ID {
+		return done, ErrElectionMismatch
+	}
+	if checkin.Status != tps.CheckinStatusApproved {
+		return done, ErrTPSCheckinNotApproved
+	}
+	// The ballot was cast at occurred_at, possibly long before the upload,
+	// and no earlier than its check-in was approved
+	approvedAt := checkin.ScanAt
+	if checkin.ApprovedAt != nil {
+		approvedAt = *checkin.ApprovedAt
+	}
+	if item.OccurredAt.Before(approvedAt.Add(-syncClockSkew)) {
+		return done, ErrSyncBallotBeforeCheckin
+	}
+	if checkin.ExpiresAt != nil && checkin.ExpiresAt.Before(item.OccurredAt) {
+		return done, ErrCheckinExpired
+	}
+	if err := s.ensureSyncWindow(ctx, tx, e, tpsID, item.OccurredAt, now); err != nil {
+		return done, err
+	}
+
+	if ranked {
+		done.vote, err = s.castRankedVoteTx(ctx, tx, e.ID, checkin.VoterID, "", ballotRankings(item.Ranking, item.Rankings), "TPS", &tpsID)
+	} else if len(item.Ranking) > 0 || len(item.Rankings) > 0 {
+		err = ErrRankingNotAllowed
+	} else {
+		done.vote, err = s.castVoteTx(ctx, tx, e.ID, checkin.VoterID, "", ballotChoices(item.CandidateID, item.Abstain, item.Choices), "TPS", &tpsID)
+	}
+	if err != nil {
+		return done, err
+	}
+
+	if err := s.voteRepo.MarkCheckinUsed(ctx, tx, checkin.ID, done.vote.VotedAt); err != nil {
+		return done, err
+	}
+	return done, nil
+}
+
+// ensureSyncWindow checks that an item queued offline happened while the TPS
+// was open for voting and, once voting closed, that it is uploaded within
+// syncCloseGrace of the close.
+func (s *Service) ensureSyncWindow(ctx context.Context, tx pgx.Tx, e *election.Election, tpsID int64, occurredAt, now time.Time) error {
+	if e.VotingStartAt != nil && occurredAt.Before(*e.VotingStartAt) {
+		return ErrSyncOutsideWindow
+	}
+	switch e.Status {
+	case election.ElectionStatusVotingOpen:
+		return nil
+	case election.ElectionStatusVotingClosed, election.ElectionStatusClosed:
+	default:
+		return ErrElectionNotOpen
+	}
+
+	// Closed: the TPS must have been open at occurred_at, by the schedule or
+	// an extension, and must not have closed more than syncCloseGrace ago
+	openAt := func(at time.Time) (bool, error) {
+		if e.VotingEndAt != nil && e.VotingEndAt.After(at) {
+			return true, nil
+		}
+		return s.voteRepo.HasOpenTPSExtension(ctx, tx, e.ID, &tpsID, at)
+	}
+	open, err := openAt(occurredAt)
+	if err != nil {
+		return err
+	}
+	if !open {
+		return ErrSyncOutsideWindow
+	}
+	if open, err = openAt(now.Add(-syncCloseGrace)); err != nil {
+		return err
+	}
+	if !open {
+		return ErrElectionNotOpen
+	}
+	return nil
+}
+
+// alreadyVotedRejection tells the panel how the voter already voted, so a
+// ballot queued offline for someone who voted online is told apart.
+func (s *Service) alreadyVotedRejection(ctx context.Context, tx pgx.Tx, electionID, voterID int64) (string, string) {
+	status, err := s.voterRepo.GetLatestStatus(ctx, tx, voterID, &electionID)
+	if err == nil && status.VotingMethod != nil {
+		switch *status.VotingMethod {
+		case "ONLINE":
+			return "ALREADY_VOTED_ONLINE", "Pemilih sudah memberikan suara secara online."
+		case "TPS":
+			return "ALREADY_VOTED_TPS", "Pemilih sudah memberikan suara di TPS."
+		}
+	}
+	return "ALREADY_VOTED", "Pemilih sudah memberikan suara pada pemilu ini."
+}
+
+// syncRejection maps the error of a rejected item to its reason. Errors that
+// are not about the item (e.g. the database) are not rejections.
+func syncRejection(err error) (string, string, bool) {
+	switch {
+	case errors.Is(err, ErrAlreadyVoted), errors.Is(err, tps.ErrAlreadyVoted):
+		return "ALREADY_VOTED", "Pemilih sudah memberikan suara pada pemilu ini.", true
+	case errors.Is(err, ErrNotEligible):
+		return "NOT_ELIGIBLE", "Pemilih tidak terdaftar pada pemilu ini.", true
+	case errors.Is(err, ErrMethodNotAllowed):
+		return "CHANNEL_NOT_ALLOWED", "Pemilih tidak diizinkan memilih melalui TPS.", true
+	case errors.Is(err, ErrElectionNotOpen):
+		return "ELECTION_NOT_OPEN", "Pemilu belum dibuka atau sudah ditutup untuk voting.", true
+	case errors.Is(err, ErrSyncOutsideWindow):
+		return "OUTSIDE_VOTING_WINDOW", "Item tercatat di luar jadwal voting TPS.", true
+	case errors.Is(err, ErrSyncBallotBeforeCheckin):
+		return "BALLOT_BEFORE_CHECKIN", "Surat suara tercatat sebelum check-in disetujui.", true
+	case errors.Is(err, ErrSyncCheckinMissing):
+		return "CHECKIN_NOT_SYNCED", "Check-in surat suara ini tidak diterima atau belum disinkronkan.", true
+	case errors.Is(err, ErrTPSCheckinNotFound):
+		return "TPS_CHECKIN_NOT_FOUND", "Check-in tidak ditemukan.", true
+	case errors.Is(err, ErrTPSCheckinNotApproved):
+		return "TPS_CHECKIN_NOT_APPROVED", "Check-in tidak berstatus disetujui.", true
+	case errors.Is(err, ErrCheckinExpired):
+		return "CHECKIN_EXPIRED", "Check-in sudah kedaluwarsa saat surat suara dicatat.", true
+	case errors.Is(err, ErrTPSNotFound):
+		return "TPS_MISMATCH", "Check-in tercatat di TPS lain.", true
+	case errors.Is(err, ErrElectionMismatch):
+		return "ELECTION_MISMATCH", "Check-in tercatat pada pemilu lain.", true
+	case errors.Is(err, ErrCandidateNotFound):
+		return "CANDIDATE_NOT_FOUND", "Kandidat tidak ditemukan untuk pemilu ini.", true
+	case errors.Is(err, ErrAbstainNotAllowed):
+		return "ABSTAIN_NOT_ALLOWED", "Pemilu ini tidak menyediakan pilihan suara kosong (abstain).", true
+	case errors.Is(err, ErrBallotIncomplete):
+		return "BALLOT_INCOMPLETE", "Surat suara harus berisi tepat satu pilihan untuk setiap posisi.", true
+	case errors.Is(err, ErrRaceNotEligible):
+		return "RACE_NOT_ELIGIBLE", "Pemilih tidak berhak memilih pada salah satu posisi.", true
+	case errors.Is(err, ErrDuplicateRaceChoice):
+		return "DUPLICATE_RACE_CHOICE", "Hanya boleh satu pilihan untuk setiap posisi.", true
+	case errors.Is(err, ErrRankingRequired):
+		return "RANKING_REQUIRED", "Pemilu ini memakai sistem ranked-choice, urutkan minimal satu kandidat di setiap posisi.", true
+	case errors.Is(err, ErrRankingNotAllowed):
+		return "RANKING_NOT_ALLOWED", "Pemilu ini tidak memakai sistem ranked-choice, pilih satu kandidat.", true
+	case errors.Is(err, ErrDuplicateRankedCandidate):
+		return "DUPLICATE_RANKED_CANDIDATE", "Setiap kandidat hanya boleh diurutkan satu kali.", true
+	}
+
+	// Check-in checks of the tps package carry Indonesian messages
+	if code, status := tps.GetErrorCode(err); status < 500 {
+		return code, err.Error(), true
+	}
+	return "", "", false
+}
+
+func syncItemResult(item *TPSSyncItem) TPSSyncItemResult {
+	result := TPSSyncItemResult{
+		ClientID:  item.ClientID,
+		Type:      item.ItemType,
+		Status:    item.Status,
+		VoterID:   item.VoterID,
+		CheckinID: item.CheckinID,
+	}
+	if item.Reason != nil {
+		result.Reason = *item.Reason
+	}
+	if item.Message != nil {
+		result.Message = *item.Message
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/shared/constants"
+	"pemira-api/internal/shared/ctxkeys"
+)
+
+// Subscription errors. Their text is the close reason sent to clients that
+// authenticate with their first message.
+var (
+	errUnauthorized = errors.New("UNAUTHORIZED")
+	errTokenExpired = errors.New("TOKEN_EXPIRED")
+	errForbidden    = errors.New("FORBIDDEN")
+)
+
+// TokenValidator validates access tokens (implemented by auth.JWTManager).
+type TokenValidator interface {
+	ValidateAccessToken(token string) (*auth.JWTClaims, error)
+}
+
+// authMessage is the first message of a client that did not pass its token
+// as a subprotocol.
+type authMessage struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// authenticate validates token and checks that its holder may subscribe to
+// channel.
+func authenticate(tokens TokenValidator, token, channel string) (*auth.JWTClaims, error) {
+	if token == "" {
+		return nil, errUnauthorized
+	}
+	claims, err := tokens.ValidateAccessToken(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return nil, errTokenExpired
+		}
+		return nil, errUnauthorized
+	}
+	if !canSubscribe(claims, channel) {
+		return nil, errForbidden
+	}
+	return claims, nil
+}
+
+// canSubscribe is the channel ACL:
+//   - election:{id} and election:{id}:live are for admins;
+//   - tps:{id} and tps:{id}:queue are for the operators of that TPS, and admins.
+//
+// Any other channel is refused.
+func canSubscribe(claims *auth.JWTClaims, channel string) bool {
+	parts := strings.Split(channel, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return false
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return false
+	}
+	topic := ""
+	if len(parts) == 3 {
+		topic = parts[2]
+	}
+
+	admin := claims.Role == constants.RoleAdmin || claims.Role == constants.RoleSuperAdmin
+	switch parts[0] {
+	case "election":
+		return (topic == "" || topic == "live") && admin
+	case "tps":
+		if topic != "" && topic != "queue" {
+			return false
+		}
+		operator := claims.Role == constants.RoleTPSOperator && claims.TPSID != nil && *claims.TPSID == id
+		return admin || operator
+	}
+	return false
+}
+
+// withClaims puts the subscriber into ctx the way the HTTP auth middleware
+// does, so role-dependent data such as sealed results follows the caller.
+func withClaims(ctx context.Context, claims *auth.JWTClaims) context.Context {
+	ctx = context.WithValue(ctx, ctxkeys.UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, ctxkeys.UserRoleKey, string(claims.Role))
+	if claims.TPSID != nil {
+		ctx = context.WithValue(ctx, ctxkeys.TPSIDKey, *claims.TPSID)
+	}
+	return ctx
+}
+
+// originPatterns turns CORS_ALLOWED_ORIGINS entries into the host patterns
+// checked on accept. allowAll is set when every origin is allowed.
+func originPatterns(allowedOrigins []string) (patterns []string, allowAll bool) {
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			return nil, true
+		}
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			patterns = append(patterns, u.Host)
+		} else {
+			patterns = append(patterns, origin)
+		}
+	}
+	return patterns, false
+}
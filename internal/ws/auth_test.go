@@ -0,0 +1,83 @@
+package ws
+
+import (
+	"errors"
+	"testing"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/shared/constants"
+)
+
+func TestCanSubscribe(t *testing.T) {
+	tps1 := int64(1)
+	admin := &auth.JWTClaims{Role: constants.RoleAdmin}
+	operator := &auth.JWTClaims{Role: constants.RoleTPSOperator, TPSID: &tps1}
+	voter := &auth.JWTClaims{Role: constants.RoleStudent}
+
+	tests := []struct {
+		claims  *auth.JWTClaims
+		channel string
+		want    bool
+	}{
+		{admin, "election:1:live", true},
+		{admin, "election:1", true},
+		{operator, "election:1:live", false},
+		{voter, "election:1:live", false},
+		{operator, "tps:1:queue", true},
+		{operator, "tps:1", true},
+		{operator, "tps:2:queue", false},
+		{admin, "tps:2:queue", true},
+		{voter, "tps:1:queue", false},
+		{admin, "election:1:other", false},
+		{admin, "election:x:live", false},
+		{admin, "anything", false},
+	}
+	for _, tt := range tests {
+		if got := canSubscribe(tt.claims, tt.channel); got != tt.want {
+			t.Errorf("%s on %s = %v, want %v", tt.claims.Role, tt.channel, got, tt.want)
+		}
+	}
+}
+
+type fakeTokens map[string]*auth.JWTClaims
+
+func (f fakeTokens) ValidateAccessToken(token string) (*auth.JWTClaims, error) {
+	if token == "expired" {
+		return nil, auth.ErrExpiredToken
+	}
+	if c, ok := f[token]; ok {
+		return c, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func TestAuthenticate(t *testing.T) {
+	tokens := fakeTokens{
+		"admin": {Role: constants.RoleAdmin},
+		"voter": {Role: constants.RoleStudent},
+	}
+
+	if _, err := authenticate(tokens, "admin", "election:1:live"); err != nil {
+		t.Errorf("admin: %v", err)
+	}
+	for token, want := range map[string]error{
+		"":        errUnauthorized,
+		"garbage": errUnauthorized,
+		"expired": errTokenExpired,
+		"voter":   errForbidden,
+	} {
+		if _, err := authenticate(tokens, token, "election:1:live"); !errors.Is(err, want) {
+			t.Errorf("token %q: err = %v, want %v", token, err, want)
+		}
+	}
+}
+
+func TestOriginPatterns(t *testing.T) {
+	patterns, allowAll := originPatterns([]string{"http://localhost:3000", "https://pemira.example.ac.id"})
+	if allowAll || len(patterns) != 2 || patterns[0] != "localhost:3000" || patterns[1] != "pemira.example.ac.id" {
+		t.Errorf("patterns = %v, allowAll %v", patterns, allowAll)
+	}
+	if _, allowAll := originPatterns([]string{"*"}); !allowAll {
+		t.Error("* does not allow every origin")
+	}
+}
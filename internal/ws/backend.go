@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// publishTimeout bounds how long publishing through a backend may take.
+const publishTimeout = 5 * time.Second
+
+// Backend fans published messages out to the hubs of every API instance.
+type Backend interface {
+	// Publish sends message to every instance, this one included.
+	Publish(ctx context.Context, message Message) error
+	// Listen hands deliver every message published on any instance until ctx
+	// is done.
+	Listen(ctx context.Context, deliver func(Message)) error
+}
+
+// MemoryBackend delivers messages to the hubs listening on it within one
+// process. Hubs sharing one MemoryBackend behave like instances sharing a
+// real backend, which is what tests use it for.
+type MemoryBackend struct {
+	mu        sync.RWMutex
+	next      int
+	listeners map[int]func(Message)
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{listeners: make(map[int]func(Message))}
+}
+
+func (b *MemoryBackend) Publish(_ context.Context, message Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, deliver := range b.listeners {
+		deliver(message)
+	}
+	return nil
+}
+
+func (b *MemoryBackend) Listen(ctx context.Context, deliver func(Message)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.listeners[id] = deliver
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.listeners, id)
+	b.mu.Unlock()
+	return ctx.Err()
+}
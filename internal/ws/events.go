@@ -0,0 +1,94 @@
+package ws
+
+import (
+	"fmt"
+	"time"
+
+	"pemira-api/internal/election"
+)
+
+// Event types published on the hub.
+const (
+	// EventCheckinNew carries a CheckinEvent for a check-in joining a TPS queue.
+	EventCheckinNew = "checkin.new"
+	// EventCheckinUpdated carries a CheckinEvent for a check-in that changed
+	// status (approved, rejected, voted, expired).
+	EventCheckinUpdated = "checkin.updated"
+	// EventVoteCast carries a VoteCastEvent.
+	EventVoteCast = "vote.cast"
+	// EventTPSStatus carries a TPSStatusEvent.
+	EventTPSStatus = "tps.status"
+)
+
+// Publisher publishes events to hub channels (implemented by Hub).
+type Publisher interface {
+	Publish(channel, eventType string, data any)
+}
+
+// TPSQueueChannel is the channel the operator panel of a TPS follows.
+func TPSQueueChannel(tpsID int64) string {
+	return fmt.Sprintf("tps:%d:queue", tpsID)
+}
+
+// CheckinEvent is the data of checkin.new and checkin.updated.
+type CheckinEvent struct {
+	CheckinID  int64  `json:"checkin_id"`
+	ElectionID int64  `json:"election_id"`
+	TPSID      int64  `json:"tps_id"`
+	Status     string `json:"status"`
+	// Voter is the voter as listed in the queue; set on checkin.new.
+	Voter  any        `json:"voter,omitempty"`
+	ScanAt *time.Time `json:"scan_at,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+	At     time.Time  `json:"at"`
+}
+
+// VoteCastEvent is the data of vote.cast. It never says who voted or for whom.
+type VoteCastEvent struct {
+	ElectionID int64     `json:"election_id"`
+	Channel    string    `json:"channel"`
+	TPSID      *int64    `json:"tps_id,omitempty"`
+	CastAt     time.Time `json:"cast_at"`
+}
+
+// TPSStatusEvent is the data of tps.status.
+type TPSStatusEvent struct {
+	TPSID  int64     `json:"tps_id"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// PublishCheckin sends a check-in event to the queue of its TPS. A nil
+// publisher publishes nothing.
+func PublishCheckin(p Publisher, eventType string, e CheckinEvent) {
+	if p == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	p.Publish(TPSQueueChannel(e.TPSID), eventType, e)
+}
+
+// PublishVoteCast sends vote.cast to the election channel and, for TPS
+// votes, to the queue of the TPS.
+func PublishVoteCast(p Publisher, e VoteCastEvent) {
+	if p == nil {
+		return
+	}
+	p.Publish(election.ElectionChannel(e.ElectionID), EventVoteCast, e)
+	if e.TPSID != nil {
+		p.Publish(TPSQueueChannel(*e.TPSID), EventVoteCast, e)
+	}
+}
+
+// PublishTPSStatus sends tps.status to the queue of the TPS.
+func PublishTPSStatus(p Publisher, e TPSStatusEvent) {
+	if p == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	p.Publish(TPSQueueChannel(e.TPSID), EventTPSStatus, e)
+}
@@ -0,0 +1,36 @@
+package ws
+
+import "testing"
+
+type recorder []string
+
+func (r *recorder) Publish(channel, eventType string, _ any) {
+	*r = append(*r, eventType+"@"+channel)
+}
+
+func TestPublishRoutesEvents(t *testing.T) {
+	tpsID := int64(4)
+	var got recorder
+
+	PublishVoteCast(&got, VoteCastEvent{ElectionID: 1, Channel: "ONLINE"})
+	PublishVoteCast(&got, VoteCastEvent{ElectionID: 1, Channel: "TPS", TPSID: &tpsID})
+	PublishCheckin(&got, EventCheckinNew, CheckinEvent{CheckinID: 9, ElectionID: 1, TPSID: 4})
+	PublishTPSStatus(&got, TPSStatusEvent{TPSID: 4, Status: "CLOSED"})
+	PublishCheckin(nil, EventCheckinNew, CheckinEvent{TPSID: 4})
+
+	want := []string{
+		"vote.cast@election:1",
+		"vote.cast@election:1",
+		"vote.cast@tps:4:queue",
+		"checkin.new@tps:4:queue",
+		"tps.status@tps:4:queue",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
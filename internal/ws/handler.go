@@ -5,51 +5,205 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"nhooyr.io/websocket"
+
+	"pemira-api/internal/auth"
+	"pemira-api/internal/http/response"
 )
 
+// authTimeout is how long a client that did not pass its token as a
+// subprotocol has to send it as its first message.
+const authTimeout = 10 * time.Second
+
+// bearerProtocol is the subprotocol a client offers, followed by its token,
+// to authenticate during the handshake: new WebSocket(url, ["bearer", token]).
+const bearerProtocol = "bearer"
+
+// SnapshotFunc returns the current state of channel, sent to a client before
+// any event so it has something to apply the events to. An empty eventType
+// means the channel has no snapshot.
+type SnapshotFunc func(ctx context.Context, channel string) (eventType string, data any, err error)
+
 type Handler struct {
-	hub *Hub
+	hub            *Hub
+	tokens         TokenValidator
+	originPatterns []string
+	anyOrigin      bool
+	snapshot       SnapshotFunc
 }
 
-func NewHandler(hub *Hub) *Handler {
-	return &Handler{hub: hub}
+// NewHandler serves subscriptions authenticated by tokens, from browsers on
+// allowedOrigins (CORS_ALLOWED_ORIGINS; "*" allows any origin).
+func NewHandler(hub *Hub, tokens TokenValidator, allowedOrigins []string) *Handler {
+	patterns, anyOrigin := originPatterns(allowedOrigins)
+	return &Handler{hub: hub, tokens: tokens, originPatterns: patterns, anyOrigin: anyOrigin}
 }
 
+// SetSnapshotFunc sets the source of the snapshot sent on connect.
+func (h *Handler) SetSnapshotFunc(fn SnapshotFunc) {
+	h.snapshot = fn
+}
+
+// RegisterRoutes mounts the subscription endpoint. Mount it outside any
+// request timeout middleware: subscriptions stay open for hours.
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/ws/{channel}", h.HandleWebSocket)
 }
 
+// HandleWebSocket streams the events of a channel. The client authenticates
+// with an access token, either offered as the subprotocols "bearer", <token>
+// or sent as a first message {"type":"auth","token":"..."}, and must be
+// allowed on the channel (see canSubscribe). Tokens in the URL are refused,
+// since URLs end up in access logs. The connection is closed when the token
+// expires.
+//
+// A reconnecting client passes the seq of the last message it received as
+// last_seq and gets the messages it missed; when those are no longer kept, or
+// on a first connect, it gets a fresh snapshot instead.
 func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	channel := chi.URLParam(r, "channel")
-	
+	lastSeq, err := strconv.ParseUint(r.URL.Query().Get("last_seq"), 10, 64)
+	resume := err == nil
+
+	if r.URL.Query().Has("token") {
+		response.BadRequest(w, "TOKEN_IN_URL", "Token tidak boleh dikirim lewat URL, kirim sebagai subprotocol atau pesan pertama.")
+		return
+	}
+
+	var claims *auth.JWTClaims
+	var subprotocols []string
+	if token := protocolToken(r); token != "" {
+		claims, err = authenticate(h.tokens, token, channel)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+		subprotocols = []string{bearerProtocol}
+	}
+
+	// The connection outlives the request; the server's read and write
+	// timeouts must not carry over to it.
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		InsecureSkipVerify: true,
+		InsecureSkipVerify: h.anyOrigin,
+		OriginPatterns:     h.originPatterns,
+		Subprotocols:       subprotocols,
 	})
 	if err != nil {
 		slog.Error("failed to accept websocket", "error", err)
 		return
 	}
 
+	if claims == nil {
+		claims, err = h.readAuthMessage(r.Context(), conn, channel)
+		if err != nil {
+			conn.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+	}
+
+	expiry := time.AfterFunc(time.Until(time.Unix(claims.Exp, 0)), func() {
+		conn.Close(websocket.StatusPolicyViolation, errTokenExpired.Error())
+	})
+	defer expiry.Stop()
+
 	client := &Client{
 		Conn:    conn,
 		Channel: channel,
-		Send:    make(chan Message, 256),
+		Send:    make(chan Message, historySize),
 	}
 
-	h.hub.Register(client)
+	seq, replayed := h.hub.Subscribe(client, lastSeq, resume)
 	defer h.hub.Unregister(client)
 
-	ctx, cancel := context.WithCancel(r.Context())
+	// The subscription ends when either side closes, not with a request
+	// deadline
+	ctx, cancel := context.WithCancel(withClaims(context.WithoutCancel(r.Context()), claims))
 	defer cancel()
 
+	// The snapshot is written before the pump starts, so messages published
+	// meanwhile wait in client.Send and follow it.
+	if !replayed {
+		if err := h.sendSnapshot(ctx, client, seq); err != nil {
+			slog.Error("failed to send websocket snapshot", "channel", channel, "error", err)
+			conn.Close(websocket.StatusInternalError, "snapshot unavailable")
+			return
+		}
+	}
+
 	go h.writePump(ctx, client)
 	h.readPump(ctx, client)
 }
 
+// protocolToken returns the token a client offered after the bearer
+// subprotocol, if any.
+func protocolToken(r *http.Request) string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
+		}
+	}
+	for i, p := range protocols {
+		if p == bearerProtocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+// readAuthMessage authenticates a client by its first message.
+func (h *Handler) readAuthMessage(ctx context.Context, conn *websocket.Conn, channel string) (*auth.JWTClaims, error) {
+	ctx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
+
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	var msg authMessage
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "auth" {
+		return nil, errUnauthorized
+	}
+	return authenticate(h.tokens, msg.Token, channel)
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch err {
+	case errTokenExpired:
+		response.Unauthorized(w, "TOKEN_EXPIRED", "Token sudah kadaluarsa.")
+	case errForbidden:
+		response.Forbidden(w, "FORBIDDEN", "Akses ditolak.")
+	default:
+		response.Unauthorized(w, "INVALID_TOKEN", "Token tidak valid.")
+	}
+}
+
+// sendSnapshot writes the channel snapshot, numbered seq: events after it
+// carry higher sequence numbers.
+func (h *Handler) sendSnapshot(ctx context.Context, client *Client, seq uint64) error {
+	if h.snapshot == nil {
+		return nil
+	}
+	eventType, data, err := h.snapshot(ctx, client.Channel)
+	if err != nil || eventType == "" {
+		return err
+	}
+	payload, err := json.Marshal(Message{Type: eventType, Channel: client.Channel, Seq: seq, Data: data})
+	if err != nil {
+		return err
+	}
+	return client.Conn.Write(ctx, websocket.MessageText, payload)
+}
+
 func (h *Handler) readPump(ctx context.Context, client *Client) {
 	defer client.Conn.Close(websocket.StatusNormalClosure, "")
 
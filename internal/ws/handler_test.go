@@ -0,0 +1,91 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"nhooyr.io/websocket"
+
+	"pemira-api/internal/shared/constants"
+)
+
+func TestHandleWebSocketOutlivesTimeouts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := NewHub()
+	go hub.Run(ctx)
+
+	tokens := fakeTokens{"admin": {Role: constants.RoleAdmin, Exp: time.Now().Add(time.Hour).Unix()}}
+	// Even under a request timeout, which main keeps it out of
+	root := chi.NewRouter()
+	NewHandler(hub, tokens, []string{"*"}).RegisterRoutes(root.With(middleware.Timeout(100 * time.Millisecond)))
+
+	srv := httptest.NewUnstartedServer(root)
+	srv.Config.ReadTimeout = 200 * time.Millisecond
+	srv.Config.WriteTimeout = 200 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws/election:1:live", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.CloseNow()
+	auth, _ := json.Marshal(authMessage{Type: "auth", Token: "admin"})
+	if err := conn.Write(ctx, websocket.MessageText, auth); err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+
+	// Well past the request and server timeouts
+	time.Sleep(600 * time.Millisecond)
+	hub.Publish("election:1:live", "livecount.delta", 1)
+
+	readCtx, readCancel := context.WithTimeout(ctx, 2*time.Second)
+	defer readCancel()
+	_, data, err := conn.Read(readCtx)
+	if err != nil {
+		t.Fatalf("subscription closed: %v", err)
+	}
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil || message.Type != "livecount.delta" {
+		t.Fatalf("got %s", data)
+	}
+}
+
+func TestHandleWebSocketTokenOutsideURL(t *testing.T) {
+	ctx := context.Background()
+	tokens := fakeTokens{"admin": {Role: constants.RoleAdmin, Exp: time.Now().Add(time.Hour).Unix()}}
+	root := chi.NewRouter()
+	NewHandler(NewHub(), tokens, []string{"*"}).RegisterRoutes(root)
+	srv := httptest.NewServer(root)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/election:1:live"
+
+	// Query tokens would be written to access logs
+	_, resp, err := websocket.Dial(ctx, url+"?token=admin", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("query token: err %v, resp %v", err, resp)
+	}
+
+	_, resp, err = websocket.Dial(ctx, url, &websocket.DialOptions{Subprotocols: []string{bearerProtocol, "nope"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("bad token: err %v, resp %v", err, resp)
+	}
+
+	conn, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{Subprotocols: []string{bearerProtocol, "admin"}})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.CloseNow()
+	if conn.Subprotocol() != bearerProtocol {
+		t.Fatalf("subprotocol %q", conn.Subprotocol())
+	}
+}
@@ -3,15 +3,24 @@ package ws
 import (
 	"context"
 	"log/slog"
+	"math/rand/v2"
 	"sync"
 
 	"nhooyr.io/websocket"
 )
 
+// historySize is how many recent messages each channel keeps for clients
+// resuming after a reconnect. It is also the send buffer of a client, so a
+// full replay always fits.
+const historySize = 256
+
 type Message struct {
-	Type    string      `json:"type"`
-	Channel string      `json:"channel"`
-	Data    interface{} `json:"data"`
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	// Seq numbers the messages of a channel in publish order. Clients pass the
+	// last one they saw as last_seq when reconnecting.
+	Seq  uint64      `json:"seq,omitempty"`
+	Data interface{} `json:"data"`
 }
 
 type Client struct {
@@ -20,36 +29,108 @@ type Client struct {
 	Send    chan Message
 }
 
+// subscription registers a client, optionally resuming after lastSeq.
+type subscription struct {
+	client  *Client
+	lastSeq uint64
+	resume  bool
+	reply   chan subscribed
+}
+
+type subscribed struct {
+	seq      uint64
+	replayed bool
+}
+
+// channelLog holds the sequence counter and recent messages of a channel.
+type channelLog struct {
+	seq      uint64
+	messages []Message
+}
+
 type Hub struct {
 	clients    map[string]map[*Client]bool
 	broadcast  chan Message
-	register   chan *Client
+	register   chan subscription
 	unregister chan *Client
 	mu         sync.RWMutex
+
+	// logs is only touched by Run.
+	logs map[string]*channelLog
+	// startSeq seeds new channel counters. Sequence numbers are local to a
+	// hub, so each hub starts in its own random range: a client resuming on
+	// another instance, or across a restart, gets a snapshot instead of a
+	// wrong replay.
+	startSeq uint64
+
+	// backend, when set, carries published messages to the hubs of every
+	// instance (this one included) instead of only this hub.
+	backend Backend
 }
 
 func NewHub() *Hub {
 	return &Hub{
 		clients:    make(map[string]map[*Client]bool),
 		broadcast:  make(chan Message, 256),
-		register:   make(chan *Client),
+		register:   make(chan subscription),
 		unregister: make(chan *Client),
+		logs:       make(map[string]*channelLog),
+		startSeq:   newStartSeq(),
 	}
 }
 
+// newStartSeq picks a random range of 2^32 sequence numbers, staying below
+// 2^53 so they are exact in JavaScript clients.
+func newStartSeq() uint64 {
+	return (1 + rand.Uint64N(1<<20)) << 32
+}
+
+// SetBackend fans published messages out through b, so subscribers on every
+// instance receive them. Call it before Run.
+func (h *Hub) SetBackend(b Backend) {
+	h.backend = b
+}
+
 func (h *Hub) Run(ctx context.Context) {
+	if h.backend != nil {
+		go func() {
+			err := h.backend.Listen(ctx, func(message Message) {
+				select {
+				case h.broadcast <- message:
+				case <-ctx.Done():
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				slog.Error("realtime backend stopped", "error", err)
+			}
+		}()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case client := <-h.register:
+		case sub := <-h.register:
+			client := sub.client
 			h.mu.Lock()
 			if h.clients[client.Channel] == nil {
 				h.clients[client.Channel] = make(map[*Client]bool)
 			}
 			h.clients[client.Channel][client] = true
 			h.mu.Unlock()
-			slog.Info("client registered", "channel", client.Channel)
+
+			log := h.channelLog(client.Channel)
+			replayed := false
+			if sub.resume {
+				if missed, ok := log.since(sub.lastSeq); ok {
+					for _, message := range missed {
+						client.Send <- message
+					}
+					replayed = true
+				}
+			}
+			sub.reply <- subscribed{seq: log.seq, replayed: replayed}
+			slog.Info("client registered", "channel", client.Channel, "replayed", replayed)
 
 		case client := <-h.unregister:
 			h.mu.Lock()
@@ -66,6 +147,8 @@ func (h *Hub) Run(ctx context.Context) {
 			slog.Info("client unregistered", "channel", client.Channel)
 
 		case message := <-h.broadcast:
+			message.Seq = h.channelLog(message.Channel).append(message)
+
 			h.mu.RLock()
 			clients := h.clients[message.Channel]
 			h.mu.RUnlock()
@@ -84,8 +167,54 @@ func (h *Hub) Run(ctx context.Context) {
 	}
 }
 
+func (h *Hub) channelLog(channel string) *channelLog {
+	log, ok := h.logs[channel]
+	if !ok {
+		log = &channelLog{seq: h.startSeq}
+		h.logs[channel] = log
+	}
+	return log
+}
+
+// append numbers message, keeps it for replay and returns its sequence number.
+func (l *channelLog) append(message Message) uint64 {
+	l.seq++
+	message.Seq = l.seq
+	if len(l.messages) == historySize {
+		copy(l.messages, l.messages[1:])
+		l.messages = l.messages[:historySize-1]
+	}
+	l.messages = append(l.messages, message)
+	return l.seq
+}
+
+// since returns the messages after lastSeq. ok is false when some of them are
+// no longer kept, or lastSeq was never handed out by this hub.
+func (l *channelLog) since(lastSeq uint64) ([]Message, bool) {
+	if lastSeq > l.seq {
+		return nil, false
+	}
+	oldest := l.seq - uint64(len(l.messages)) // last seq before the kept messages
+	if lastSeq < oldest {
+		return nil, false
+	}
+	return l.messages[lastSeq-oldest:], true
+}
+
 func (h *Hub) Register(client *Client) {
-	h.register <- client
+	h.Subscribe(client, 0, false)
+}
+
+// Subscribe registers client. With resume set and the messages published after
+// lastSeq still kept, they are queued to the client and replayed is true.
+// Otherwise the client missed messages and should start from a snapshot;
+// seq is the channel's latest sequence number at the time of registering, so
+// every later message reaches the client.
+func (h *Hub) Subscribe(client *Client, lastSeq uint64, resume bool) (seq uint64, replayed bool) {
+	reply := make(chan subscribed, 1)
+	h.register <- subscription{client: client, lastSeq: lastSeq, resume: resume, reply: reply}
+	res := <-reply
+	return res.seq, res.replayed
 }
 
 func (h *Hub) Unregister(client *Client) {
@@ -93,5 +222,18 @@ func (h *Hub) Unregister(client *Client) {
 }
 
 func (h *Hub) Broadcast(message Message) {
+	if h.backend != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if err := h.backend.Publish(ctx, message); err != nil {
+			slog.Error("failed to publish realtime event", "channel", message.Channel, "type", message.Type, "error", err)
+		}
+		return
+	}
 	h.broadcast <- message
 }
+
+// Publish broadcasts an event of eventType to the clients of channel.
+func (h *Hub) Publish(channel, eventType string, data any) {
+	h.Broadcast(Message{Type: eventType, Channel: channel, Data: data})
+}
@@ -0,0 +1,115 @@
+package ws
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHubResume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := NewHub()
+	go hub.Run(ctx)
+
+	first := &Client{Channel: "election:1:live", Send: make(chan Message, historySize)}
+	seq, replayed := hub.Subscribe(first, 0, false)
+	if replayed {
+		t.Fatal("first connect replayed")
+	}
+
+	hub.Publish("election:1:live", "livecount.delta", 1)
+	hub.Publish("election:1:live", "livecount.delta", 2)
+	var last Message
+	for i := 0; i < 2; i++ {
+		select {
+		case last = <-first.Send:
+		case <-time.After(time.Second):
+			t.Fatal("message not delivered")
+		}
+	}
+	if last.Seq != seq+2 {
+		t.Fatalf("seq = %d, want %d", last.Seq, seq+2)
+	}
+
+	// A client that saw the first message gets only the second one back.
+	resumed := &Client{Channel: "election:1:live", Send: make(chan Message, historySize)}
+	if _, replayed := hub.Subscribe(resumed, seq+1, true); !replayed {
+		t.Fatal("resume within history was not replayed")
+	}
+	if got := <-resumed.Send; got.Seq != seq+2 || got.Data != 2 {
+		t.Fatalf("replayed %+v, want the second message", got)
+	}
+	if len(resumed.Send) != 0 {
+		t.Fatalf("replayed %d extra messages", len(resumed.Send))
+	}
+
+	// A sequence number from before a restart cannot be replayed.
+	stale := &Client{Channel: "election:1:live", Send: make(chan Message, historySize)}
+	if _, replayed := hub.Subscribe(stale, seq+10, true); replayed {
+		t.Fatal("unknown sequence number was replayed")
+	}
+}
+
+func TestChannelLogDropsOldMessages(t *testing.T) {
+	log := &channelLog{seq: 100}
+	for i := 0; i < historySize+10; i++ {
+		log.append(Message{Data: i})
+	}
+
+	if _, ok := log.since(100); ok {
+		t.Error("resume before the kept history succeeded")
+	}
+	missed, ok := log.since(log.seq - 3)
+	if !ok || len(missed) != 3 || missed[0].Seq != log.seq-2 {
+		t.Errorf("since = %d messages, ok %v", len(missed), ok)
+	}
+	if missed, ok := log.since(log.seq); !ok || len(missed) != 0 {
+		t.Errorf("up-to-date client got %d messages", len(missed))
+	}
+}
+
+func TestHubsShareBackend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := NewMemoryBackend()
+	a, b := NewHub(), NewHub()
+	a.SetBackend(backend)
+	b.SetBackend(backend)
+	go a.Run(ctx)
+	go b.Run(ctx)
+
+	onA := &Client{Channel: "tps:1:queue", Send: make(chan Message, historySize)}
+	onB := &Client{Channel: "tps:1:queue", Send: make(chan Message, historySize)}
+	a.Register(onA)
+	b.Register(onB)
+
+	// Wait until both hubs listen on the backend.
+	deadline := time.Now().Add(time.Second)
+	for {
+		backend.mu.RLock()
+		n := len(backend.listeners)
+		backend.mu.RUnlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("hubs did not start listening")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	a.Publish("tps:1:queue", EventCheckinNew, 7)
+	for name, client := range map[string]*Client{"publishing hub": onA, "other hub": onB} {
+		select {
+		case got := <-client.Send:
+			if got.Type != EventCheckinNew || got.Data != 7 {
+				t.Errorf("%s got %+v", name, got)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%s: message not delivered", name)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// notifyChannel is the Postgres NOTIFY channel carrying realtime events.
+	notifyChannel = "pemira_realtime"
+	// maxNotifyPayload keeps payloads under Postgres' 8000 byte NOTIFY limit.
+	// Larger messages are stored in realtime_events and sent by reference.
+	maxNotifyPayload = 7900
+	// eventRetention is how long stored messages are kept for listeners to
+	// fetch.
+	eventRetention = 5 * time.Minute
+)
+
+// PgBackend fans messages out through Postgres LISTEN/NOTIFY, so every
+// instance connected to the database receives them. Listening holds one pool
+// connection; it needs a session (not transaction-pooled) connection.
+// Messages published while an instance reconnects are not delivered to it;
+// its clients catch up from a snapshot when they reconnect.
+type PgBackend struct {
+	db *pgxpool.Pool
+}
+
+func NewPgBackend(db *pgxpool.Pool) *PgBackend {
+	return &PgBackend{db: db}
+}
+
+// pgEnvelope is a NOTIFY payload: the message itself or, when too large, the
+// id of its realtime_events row.
+type pgEnvelope struct {
+	Message *pgMessage `json:"m,omitempty"`
+	Ref     int64      `json:"ref,omitempty"`
+}
+
+// pgMessage is a Message whose data stays encoded until it is sent.
+type pgMessage struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (b *PgBackend) Publish(ctx context.Context, message Message) error {
+	data, err := json.Marshal(message.Data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(pgEnvelope{Message: &pgMessage{Type: message.Type, Channel: message.Channel, Data: data}})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		if payload, err = b.store(ctx, payload); err != nil {
+			return err
+		}
+	}
+
+	_, err = b.db.Exec(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, string(payload))
+	return err
+}
+
+// store saves an oversized payload and returns the envelope referencing it.
+func (b *PgBackend) store(ctx context.Context, payload []byte) ([]byte, error) {
+	var id int64
+	err := b.db.QueryRow(ctx, `
+INSERT INTO realtime_events (payload) VALUES ($1)
+RETURNING id
+`, payload).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("store realtime event: %w", err)
+	}
+
+	_, err = b.db.Exec(ctx, `DELETE FROM realtime_events WHERE created_at < NOW() - $1::INTERVAL`, eventRetention.String())
+	if err != nil {
+		slog.Error("failed to prune realtime events", "error", err)
+	}
+	return json.Marshal(pgEnvelope{Ref: id})
+}
+
+// Listen delivers notifications until ctx is done, reconnecting with backoff
+// when the listening connection fails.
+func (b *PgBackend) Listen(ctx context.Context, deliver func(Message)) error {
+	backoff := time.Second
+	for {
+		listening, err := b.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if listening {
+			backoff = time.Second
+		}
+		slog.Error("realtime listener disconnected", "error", err, "retry_in", backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// listen runs one LISTEN session. listening reports whether it got as far as
+// listening, which resets the reconnect backoff.
+func (b *PgBackend) listen(ctx context.Context, deliver func(Message)) (listening bool, err error) {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	// The session will listen; take it out of the pool and close it when done
+	// rather than hand it back.
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return false, err
+	}
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		message, err := b.decode(ctx, n.Payload)
+		if err != nil {
+			slog.Error("failed to decode realtime event", "error", err)
+			continue
+		}
+		deliver(message)
+	}
+}
+
+func (b *PgBackend) decode(ctx context.Context, payload string) (Message, error) {
+	var env pgEnvelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		return Message{}, err
+	}
+
+	if env.Ref != 0 {
+		var stored []byte
+		if err := b.db.QueryRow(ctx, `SELECT payload FROM realtime_events WHERE id = $1`, env.Ref).Scan(&stored); err != nil {
+			return Message{}, fmt.Errorf("load realtime event %d: %w", env.Ref, err)
+		}
+		env = pgEnvelope{}
+		if err := json.Unmarshal(stored, &env); err != nil {
+			return Message{}, err
+		}
+	}
+	if env.Message == nil {
+		return Message{}, fmt.Errorf("empty realtime event")
+	}
+	return Message{Type: env.Message.Type, Channel: env.Message.Channel, Data: env.Message.Data}, nil
+}
@@ -0,0 +1,13 @@
+-- +goose Down
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+DROP FUNCTION IF EXISTS audit_logs_append_only();
+DROP TRIGGER IF EXISTS audit_logs_chain_link ON audit_logs;
+DROP FUNCTION IF EXISTS audit_logs_chain_link();
+DROP INDEX IF EXISTS ux_audit_logs_chain;
+DROP FUNCTION IF EXISTS audit_logs_chain_payload(audit_logs);
+
+ALTER TABLE audit_logs
+    DROP COLUMN IF EXISTS row_hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS chain_seq;
@@ -0,0 +1,119 @@
+-- +goose Up
+-- =========================================================
+-- TAMPER-EVIDENT HASH CHAIN FOR AUDIT LOGS
+-- Every row stores sha256(prev_hash + row content). Chains are kept per
+-- election (election_id NULL forms its own chain) and ordered by chain_seq.
+-- Hashes are computed by trigger so every writer is chained.
+-- =========================================================
+
+-- Audit rows must stay byte-identical after the election they describe is removed.
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_election_id_fkey;
+
+ALTER TABLE audit_logs
+    ADD COLUMN IF NOT EXISTS chain_seq BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash TEXT,
+    ADD COLUMN IF NOT EXISTS row_hash TEXT;
+
+-- 1. Canonical payload. Must stay in sync with audit.ChainPayload in Go.
+CREATE OR REPLACE FUNCTION audit_logs_chain_payload(r audit_logs)
+RETURNS TEXT AS $$
+    SELECT concat_ws(chr(31),
+        r.prev_hash,
+        r.chain_seq::text,
+        r.id::text,
+        COALESCE(r.election_id::text, ''),
+        COALESCE(r.actor_user_id::text, ''),
+        COALESCE(r.actor_voter_id::text, ''),
+        r.action,
+        r.entity_type,
+        COALESCE(r.entity_id::text, ''),
+        r.metadata::text,
+        COALESCE(r.ip_address, ''),
+        COALESCE(r.user_agent, ''),
+        to_char(r.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+    );
+$$ LANGUAGE sql IMMUTABLE;
+
+-- 2. Backfill existing rows in insertion order
+DO $$
+DECLARE
+    r audit_logs%ROWTYPE;
+    cur_key BIGINT;
+    prev TEXT;
+    seq BIGINT;
+BEGIN
+    FOR r IN SELECT * FROM audit_logs ORDER BY COALESCE(election_id, 0), id LOOP
+        IF cur_key IS DISTINCT FROM COALESCE(r.election_id, 0) THEN
+            cur_key := COALESCE(r.election_id, 0);
+            prev := repeat('0', 64);
+            seq := 0;
+        END IF;
+
+        seq := seq + 1;
+        r.chain_seq := seq;
+        r.prev_hash := prev;
+        r.row_hash := encode(sha256(convert_to(audit_logs_chain_payload(r), 'UTF8')), 'hex');
+
+        UPDATE audit_logs
+        SET chain_seq = r.chain_seq, prev_hash = r.prev_hash, row_hash = r.row_hash
+        WHERE id = r.id;
+
+        prev := r.row_hash;
+    END LOOP;
+END $$;
+
+ALTER TABLE audit_logs
+    ALTER COLUMN chain_seq SET NOT NULL,
+    ALTER COLUMN prev_hash SET NOT NULL,
+    ALTER COLUMN row_hash SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_audit_logs_chain
+    ON audit_logs ((COALESCE(election_id, 0)), chain_seq);
+
+-- 3. Link new rows. The advisory lock serialises writers of the same chain
+--    until their transaction commits.
+CREATE OR REPLACE FUNCTION audit_logs_chain_link()
+RETURNS TRIGGER AS $$
+DECLARE
+    prev_row RECORD;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('audit_logs_chain'), COALESCE(NEW.election_id, 0)::int);
+
+    SELECT chain_seq, row_hash INTO prev_row
+    FROM audit_logs
+    WHERE COALESCE(election_id, 0) = COALESCE(NEW.election_id, 0)
+    ORDER BY chain_seq DESC
+    LIMIT 1;
+
+    IF NOT FOUND THEN
+        NEW.chain_seq := 1;
+        NEW.prev_hash := repeat('0', 64);
+    ELSE
+        NEW.chain_seq := prev_row.chain_seq + 1;
+        NEW.prev_hash := prev_row.row_hash;
+    END IF;
+
+    NEW.row_hash := encode(sha256(convert_to(audit_logs_chain_payload(NEW), 'UTF8')), 'hex');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_chain_link ON audit_logs;
+CREATE TRIGGER audit_logs_chain_link
+    BEFORE INSERT ON audit_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_logs_chain_link();
+
+-- 4. Append-only: edits have to bypass this trigger and will then break the chain.
+CREATE OR REPLACE FUNCTION audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_logs_append_only();
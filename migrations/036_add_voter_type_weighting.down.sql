@@ -0,0 +1,5 @@
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_votes_election_voter_type;
+ALTER TABLE votes DROP COLUMN IF EXISTS voter_type;
+DROP TABLE IF EXISTS election_voter_type_weights;
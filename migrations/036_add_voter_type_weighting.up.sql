@@ -0,0 +1,26 @@
+-- +goose Up
+-- Per-election ballot weighting by voter_type (constituency) and voter_type on votes
+
+CREATE TABLE IF NOT EXISTS election_voter_type_weights (
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    voter_type TEXT NOT NULL CHECK (voter_type IN ('STUDENT', 'LECTURER', 'STAFF')),
+    weight NUMERIC(10,4) NOT NULL CHECK (weight >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (election_id, voter_type)
+);
+
+ALTER TABLE votes
+    ADD COLUMN IF NOT EXISTS voter_type TEXT
+    CHECK (voter_type IS NULL OR voter_type IN ('STUDENT', 'LECTURER', 'STAFF'));
+
+-- Backfill from the voter that holds the vote token
+UPDATE votes vt
+SET voter_type = v.voter_type
+FROM voter_status vs
+JOIN voters v ON v.id = vs.voter_id
+WHERE vs.election_id = vt.election_id
+  AND vs.vote_token_hash = vt.token_hash
+  AND vt.voter_type IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_votes_election_voter_type ON votes(election_id, voter_type, candidate_id);
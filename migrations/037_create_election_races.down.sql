@@ -0,0 +1,14 @@
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_votes_election_race;
+DROP INDEX IF EXISTS ux_votes_token_race;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_votes_token_hash ON votes(token_hash);
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS fk_votes_race;
+ALTER TABLE votes DROP COLUMN IF EXISTS race_id;
+
+DROP INDEX IF EXISTS idx_candidates_race;
+ALTER TABLE candidates DROP CONSTRAINT IF EXISTS fk_candidates_race;
+ALTER TABLE candidates DROP COLUMN IF EXISTS race_id;
+
+DROP TABLE IF EXISTS election_race_eligibility;
+DROP TABLE IF EXISTS election_races;
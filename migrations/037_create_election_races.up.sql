@@ -0,0 +1,51 @@
+-- +goose Up
+-- Multi-race elections: an election holds one or more races (e.g. Presiden BEM,
+-- DPM per fakultas). Candidates belong to a race, a ballot holds one vote per race.
+
+CREATE TABLE IF NOT EXISTS election_races (
+    id BIGSERIAL PRIMARY KEY,
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    display_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ux_election_races_election_code UNIQUE (election_id, code),
+    -- Target of the composite FKs below so a race can't be used across elections
+    CONSTRAINT ux_election_races_id_election UNIQUE (id, election_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_election_races_election ON election_races(election_id, display_order);
+
+-- Race eligibility from master data. A race without rows is open to every voter
+-- in the election; otherwise the voter's faculty or study program must match a row.
+CREATE TABLE IF NOT EXISTS election_race_eligibility (
+    id BIGSERIAL PRIMARY KEY,
+    race_id BIGINT NOT NULL REFERENCES election_races(id) ON DELETE CASCADE,
+    faculty_id BIGINT REFERENCES faculties(id) ON DELETE CASCADE,
+    study_program_id BIGINT REFERENCES study_programs(id) ON DELETE CASCADE,
+    CHECK ((faculty_id IS NULL) <> (study_program_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_race_eligibility_faculty
+    ON election_race_eligibility(race_id, faculty_id) WHERE faculty_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_race_eligibility_program
+    ON election_race_eligibility(race_id, study_program_id) WHERE study_program_id IS NOT NULL;
+
+ALTER TABLE candidates
+    ADD COLUMN IF NOT EXISTS race_id BIGINT,
+    ADD CONSTRAINT fk_candidates_race
+        FOREIGN KEY (race_id, election_id) REFERENCES election_races(id, election_id);
+
+CREATE INDEX IF NOT EXISTS idx_candidates_race ON candidates(race_id);
+
+ALTER TABLE votes
+    ADD COLUMN IF NOT EXISTS race_id BIGINT,
+    ADD CONSTRAINT fk_votes_race
+        FOREIGN KEY (race_id, election_id) REFERENCES election_races(id, election_id);
+
+-- A ballot (token) now carries one vote per race
+DROP INDEX IF EXISTS ux_votes_token_hash;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_votes_token_race ON votes(token_hash, COALESCE(race_id, 0));
+CREATE INDEX IF NOT EXISTS idx_votes_election_race ON votes(election_id, race_id, candidate_id);
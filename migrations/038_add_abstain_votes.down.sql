@@ -0,0 +1,15 @@
+-- +goose Down
+DROP INDEX IF EXISTS ux_candidate_qr_abstain_active;
+DELETE FROM candidate_qr_codes WHERE candidate_id IS NULL;
+ALTER TABLE candidate_qr_codes
+    ALTER COLUMN candidate_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_votes_election_abstain;
+DELETE FROM votes WHERE is_abstain;
+ALTER TABLE votes
+    DROP CONSTRAINT IF EXISTS chk_votes_abstain_candidate,
+    ALTER COLUMN candidate_id SET NOT NULL,
+    DROP COLUMN IF EXISTS is_abstain;
+
+ALTER TABLE elections
+    DROP COLUMN IF EXISTS abstain_enabled;
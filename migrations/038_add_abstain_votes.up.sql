@@ -0,0 +1,21 @@
+-- +goose Up
+-- Blank/abstain ballots: an election may allow voters to abstain. An abstain vote
+-- is a row in votes with is_abstain = TRUE and no candidate.
+
+ALTER TABLE elections
+    ADD COLUMN IF NOT EXISTS abstain_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE votes
+    ADD COLUMN IF NOT EXISTS is_abstain BOOLEAN NOT NULL DEFAULT FALSE,
+    ALTER COLUMN candidate_id DROP NOT NULL,
+    ADD CONSTRAINT chk_votes_abstain_candidate
+        CHECK ((is_abstain AND candidate_id IS NULL) OR (NOT is_abstain AND candidate_id IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_votes_election_abstain ON votes(election_id) WHERE is_abstain;
+
+-- The abstain ballot QR is a candidate_qr_codes row without candidate
+ALTER TABLE candidate_qr_codes
+    ALTER COLUMN candidate_id DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_candidate_qr_abstain_active
+    ON candidate_qr_codes(election_id) WHERE candidate_id IS NULL AND is_active;
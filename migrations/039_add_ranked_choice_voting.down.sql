@@ -0,0 +1,7 @@
+-- +goose Down
+
+DROP TABLE IF EXISTS ranked_ballot_preferences;
+DROP TABLE IF EXISTS ranked_ballots;
+
+ALTER TABLE elections DROP CONSTRAINT IF EXISTS chk_elections_voting_system;
+ALTER TABLE elections DROP COLUMN IF EXISTS voting_system;
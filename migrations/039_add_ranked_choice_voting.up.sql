@@ -0,0 +1,35 @@
+-- +goose Up
+-- Ranked-choice (instant-runoff) voting. An election counts either by plurality
+-- (one candidate per race, stored in votes) or by IRV, where a ballot ranks the
+-- candidates of a race in order of preference and is stored in ranked_ballots.
+
+ALTER TABLE elections
+    ADD COLUMN IF NOT EXISTS voting_system TEXT NOT NULL DEFAULT 'PLURALITY',
+    ADD CONSTRAINT chk_elections_voting_system CHECK (voting_system IN ('PLURALITY', 'IRV'));
+
+CREATE TABLE IF NOT EXISTS ranked_ballots (
+    id BIGSERIAL PRIMARY KEY,
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    race_id BIGINT,
+    token_hash TEXT NOT NULL,
+    channel vote_channel NOT NULL,
+    tps_id BIGINT NULL REFERENCES tps(id) ON DELETE SET NULL,
+    voter_type TEXT,
+    cast_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_ranked_ballots_race
+        FOREIGN KEY (race_id, election_id) REFERENCES election_races(id, election_id)
+);
+
+-- One ranked ballot per token and race, like votes
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ranked_ballots_token_race ON ranked_ballots(token_hash, COALESCE(race_id, 0));
+CREATE INDEX IF NOT EXISTS idx_ranked_ballots_election_race ON ranked_ballots(election_id, race_id);
+
+-- Preferences of a ballot; rank 1 is the first preference. A voter may rank
+-- only some of the candidates but may not rank a candidate twice.
+CREATE TABLE IF NOT EXISTS ranked_ballot_preferences (
+    ballot_id BIGINT NOT NULL REFERENCES ranked_ballots(id) ON DELETE CASCADE,
+    rank SMALLINT NOT NULL CHECK (rank >= 1),
+    candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+    PRIMARY KEY (ballot_id, rank),
+    CONSTRAINT ux_ranked_preferences_candidate UNIQUE (ballot_id, candidate_id)
+);
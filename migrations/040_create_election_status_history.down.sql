@@ -0,0 +1,3 @@
+-- +goose Down
+
+DROP TABLE IF EXISTS election_status_history;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Every election status transition with who made it and why. Automatic
+-- transitions by the schedule have actor_type SYSTEM and no actor_user_id.
+
+CREATE TABLE IF NOT EXISTS election_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor_type TEXT NOT NULL CHECK (actor_type IN ('ADMIN', 'SYSTEM')),
+    actor_user_id BIGINT,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_election_status_history_election
+    ON election_status_history(election_id, created_at DESC);
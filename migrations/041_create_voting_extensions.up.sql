@@ -0,0 +1,23 @@
+-- +goose Up
+-- Voting window extensions granted by the committee, e.g. after a network
+-- outage. A row without tps_id moves the election's voting_end_at (reopening
+-- voting if it had closed); a row with tps_id keeps only that TPS accepting
+-- check-ins and votes until voting_end_at, even after the election closed.
+
+CREATE TABLE IF NOT EXISTS voting_extensions (
+    id BIGSERIAL PRIMARY KEY,
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    tps_id BIGINT NULL REFERENCES tps(id) ON DELETE CASCADE,
+    previous_end_at TIMESTAMPTZ,
+    voting_end_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL CHECK (btrim(reason) <> ''),
+    created_by BIGINT NULL REFERENCES user_accounts(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_voting_extensions_election
+    ON voting_extensions(election_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_voting_extensions_tps_open
+    ON voting_extensions(election_id, tps_id, voting_end_at)
+    WHERE tps_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- +goose Down
+
+DROP TABLE IF EXISTS election_result_signoffs;
+DROP TRIGGER IF EXISTS election_results_guard ON election_results;
+DROP FUNCTION IF EXISTS election_results_guard();
+DROP TABLE IF EXISTS election_results;
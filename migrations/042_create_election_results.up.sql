@@ -0,0 +1,70 @@
+-- +goose Up
+-- Official results. After voting closes an admin freezes the tally into a
+-- snapshot (tally plus its sha256); the snapshot is certified once enough
+-- committee accounts signed off on that hash, and only then published.
+-- A pending snapshot may be superseded by a recount; tally and hash never change.
+
+CREATE TABLE IF NOT EXISTS election_results (
+    id BIGSERIAL PRIMARY KEY,
+    election_id BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'PENDING_SIGNOFF'
+        CHECK (status IN ('PENDING_SIGNOFF', 'CERTIFIED', 'PUBLISHED', 'SUPERSEDED')),
+    tally JSONB NOT NULL,
+    tally_hash TEXT NOT NULL,
+    signoffs_required INT NOT NULL CHECK (signoffs_required >= 1),
+    computed_by BIGINT NULL REFERENCES user_accounts(id) ON DELETE SET NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    certified_at TIMESTAMPTZ,
+    published_at TIMESTAMPTZ
+);
+
+-- At most one live snapshot per election
+CREATE UNIQUE INDEX IF NOT EXISTS ux_election_results_current
+    ON election_results(election_id) WHERE status <> 'SUPERSEDED';
+
+CREATE TABLE IF NOT EXISTS election_result_signoffs (
+    id BIGSERIAL PRIMARY KEY,
+    result_id BIGINT NOT NULL REFERENCES election_results(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES user_accounts(id) ON DELETE RESTRICT,
+    tally_hash TEXT NOT NULL,
+    note TEXT,
+    signed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ux_election_result_signoffs_user UNIQUE (result_id, user_id)
+);
+
+-- Snapshots are immutable: only the status moves forward
+-- (PENDING_SIGNOFF -> CERTIFIED -> PUBLISHED, or PENDING_SIGNOFF -> SUPERSEDED)
+-- and certified results cannot be deleted.
+CREATE OR REPLACE FUNCTION election_results_guard()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.status IN ('CERTIFIED', 'PUBLISHED') THEN
+            RAISE EXCEPTION 'certified election results cannot be deleted';
+        END IF;
+        RETURN OLD;
+    END IF;
+
+    IF NEW.tally IS DISTINCT FROM OLD.tally
+        OR NEW.tally_hash IS DISTINCT FROM OLD.tally_hash
+        OR NEW.election_id IS DISTINCT FROM OLD.election_id
+        OR NEW.signoffs_required IS DISTINCT FROM OLD.signoffs_required
+        OR NEW.computed_at IS DISTINCT FROM OLD.computed_at THEN
+        RAISE EXCEPTION 'election results snapshot is immutable';
+    END IF;
+
+    IF NEW.status IS DISTINCT FROM OLD.status AND NOT (
+        (OLD.status = 'PENDING_SIGNOFF' AND NEW.status IN ('CERTIFIED', 'SUPERSEDED'))
+        OR (OLD.status = 'CERTIFIED' AND NEW.status = 'PUBLISHED')
+    ) THEN
+        RAISE EXCEPTION 'invalid election results status change % -> %', OLD.status, NEW.status;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS election_results_guard ON election_results;
+CREATE TRIGGER election_results_guard
+    BEFORE UPDATE OR DELETE ON election_results
+    FOR EACH ROW
+    EXECUTE FUNCTION election_results_guard();
@@ -0,0 +1,3 @@
+-- +goose Down
+
+ALTER TABLE elections DROP COLUMN IF EXISTS results_sealed;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Sealed results: while set, per-candidate vote numbers are withheld from every
+-- API and websocket payload until voting has closed (turnout stays visible).
+
+ALTER TABLE elections
+    ADD COLUMN IF NOT EXISTS results_sealed BOOLEAN NOT NULL DEFAULT FALSE;
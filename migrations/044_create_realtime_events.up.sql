@@ -0,0 +1,11 @@
+-- +goose Up
+-- Realtime events too large for a Postgres NOTIFY payload. The notification
+-- carries the row id; rows are pruned after a few minutes.
+
+CREATE TABLE IF NOT EXISTS realtime_events (
+    id         BIGSERIAL PRIMARY KEY,
+    payload    JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_realtime_events_created_at ON realtime_events (created_at);
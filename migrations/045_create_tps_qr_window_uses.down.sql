@@ -0,0 +1,3 @@
+-- +goose Down
+
+DROP TABLE IF EXISTS tps_qr_window_uses;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Rotating TPS check-in QR: the last code window each voter used per TPS, so
+-- a code (or an older one) cannot be replayed by the same voter.
+
+CREATE TABLE IF NOT EXISTS tps_qr_window_uses (
+    tps_id      BIGINT NOT NULL REFERENCES tps(id) ON DELETE CASCADE,
+    voter_id    BIGINT NOT NULL REFERENCES voters(id) ON DELETE CASCADE,
+    last_window BIGINT NOT NULL,
+    used_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tps_id, voter_id)
+);
@@ -0,0 +1,3 @@
+-- +goose Down
+
+DROP INDEX IF EXISTS ux_candidate_qr_candidate_active;
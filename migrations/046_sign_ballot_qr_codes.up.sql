@@ -0,0 +1,21 @@
+-- +goose Up
+-- Signed ballot QR codes: each version of an election's ballot QR codes is
+-- signed (HMAC-SHA256) with a key derived from the server's BALLOT_QR_SECRET,
+-- the election and the version, so no key is stored. Rotating issues a new
+-- version; codes of older versions are deactivated.
+
+-- Rotation keeps earlier versions as inactive rows, so only active codes need
+-- to be unique per candidate.
+ALTER TABLE candidate_qr_codes
+    DROP CONSTRAINT IF EXISTS uq_candidate_qr_active;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_candidate_qr_candidate_active
+    ON candidate_qr_codes(election_id, candidate_id) WHERE candidate_id IS NOT NULL AND is_active;
+
+-- Existing codes are unsigned and can no longer be verified, in every
+-- election including ones with voting open. Ballot QR voting resumes once an
+-- admin rotates and re-prints the codes of the election
+-- (docs/VOTING_TPS_QR_API_CONTRACT.md), so apply this outside TPS voting hours.
+UPDATE candidate_qr_codes
+SET is_active = FALSE, rotated_at = NOW()
+WHERE is_active;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Offline TPS sync: the outcome of every uploaded item, keyed by the client
+-- generated ID, so a re-uploaded item returns its first result instead of
+-- being applied again.
+
+CREATE TABLE IF NOT EXISTS tps_sync_items (
+    tps_id         BIGINT NOT NULL REFERENCES tps(id) ON DELETE CASCADE,
+    client_id      TEXT NOT NULL,
+    election_id    BIGINT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+    item_type      TEXT NOT NULL CHECK (item_type IN ('CHECKIN', 'BALLOT')),
+    status         TEXT NOT NULL CHECK (status IN ('PENDING', 'ACCEPTED', 'REJECTED')),
+    reason         TEXT,
+    message        TEXT,
+    voter_id       BIGINT,
+    checkin_id     BIGINT,
+    occurred_at    TIMESTAMPTZ NOT NULL,
+    synced_by      BIGINT,
+    synced_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tps_id, client_id)
+);
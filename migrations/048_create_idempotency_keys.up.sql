@@ -0,0 +1,17 @@
+-- +goose Up
+-- Idempotency-Key support for vote cast and check-in endpoints: the first
+-- response under a key is replayed for retries of the same request.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id       BIGINT NOT NULL,
+    idem_key      TEXT NOT NULL,
+    request_hash  TEXT NOT NULL,
+    status_code   INT,
+    content_type  TEXT,
+    response_body BYTEA,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at  TIMESTAMPTZ,
+    PRIMARY KEY (user_id, idem_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
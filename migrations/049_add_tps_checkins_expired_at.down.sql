@@ -0,0 +1,4 @@
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_tps_checkins_tps_expired_at;
+ALTER TABLE tps_checkins DROP COLUMN IF EXISTS expired_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Check-in expiry sweeper: records when a stale PENDING or unused APPROVED
+-- check-in was moved to EXPIRED, for the TPS timeline.
+
+ALTER TABLE tps_checkins ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tps_checkins_tps_expired_at
+    ON tps_checkins (tps_id, expired_at)
+    WHERE expired_at IS NOT NULL;
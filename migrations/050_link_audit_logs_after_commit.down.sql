@@ -0,0 +1,45 @@
+-- +goose Down
+
+-- Link whatever is still pending before linking moves back into the trigger.
+SELECT audit_logs_link_pending(2147483647);
+
+DROP FUNCTION IF EXISTS audit_logs_link_pending(INT);
+DROP INDEX IF EXISTS idx_audit_logs_unlinked;
+
+CREATE OR REPLACE FUNCTION audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION audit_logs_chain_link()
+RETURNS TRIGGER AS $$
+DECLARE
+    prev_row RECORD;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('audit_logs_chain'), COALESCE(NEW.election_id, 0)::int);
+
+    SELECT chain_seq, row_hash INTO prev_row
+    FROM audit_logs
+    WHERE COALESCE(election_id, 0) = COALESCE(NEW.election_id, 0)
+    ORDER BY chain_seq DESC
+    LIMIT 1;
+
+    IF NOT FOUND THEN
+        NEW.chain_seq := 1;
+        NEW.prev_hash := repeat('0', 64);
+    ELSE
+        NEW.chain_seq := prev_row.chain_seq + 1;
+        NEW.prev_hash := prev_row.row_hash;
+    END IF;
+
+    NEW.row_hash := encode(sha256(convert_to(audit_logs_chain_payload(NEW), 'UTF8')), 'hex');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE audit_logs
+    ALTER COLUMN chain_seq SET NOT NULL,
+    ALTER COLUMN prev_hash SET NOT NULL,
+    ALTER COLUMN row_hash SET NOT NULL;
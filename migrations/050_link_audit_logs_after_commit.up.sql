@@ -0,0 +1,102 @@
+-- +goose Up
+-- =========================================================
+-- LINK AUDIT LOG CHAINS AFTER COMMIT
+-- Linking on insert took a per-election lock inside every writer's
+-- transaction (votes included) and held it until commit, serialising every
+-- vote of an election. Rows are now inserted unlinked and a background
+-- chainer (audit.Chainer) links committed rows in id order, one chain at a
+-- time under the chain's advisory lock.
+-- =========================================================
+
+ALTER TABLE audit_logs
+    ALTER COLUMN chain_seq DROP NOT NULL,
+    ALTER COLUMN prev_hash DROP NOT NULL,
+    ALTER COLUMN row_hash DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_unlinked
+    ON audit_logs ((COALESCE(election_id, 0)), id)
+    WHERE row_hash IS NULL;
+
+-- 1. New rows are never linked by their writer.
+CREATE OR REPLACE FUNCTION audit_logs_chain_link()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.chain_seq := NULL;
+    NEW.prev_hash := NULL;
+    NEW.row_hash := NULL;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 2. Link up to batch_size unlinked rows of every chain. Returns the number
+--    of rows linked. A chain another chainer is linking is skipped rather
+--    than waited for, so chainers on different replicas never block (or
+--    deadlock) on each other; it is picked up on a later tick.
+CREATE OR REPLACE FUNCTION audit_logs_link_pending(batch_size INT)
+RETURNS BIGINT AS $$
+DECLARE
+    chain_key BIGINT;
+    prev_row RECORD;
+    r audit_logs%ROWTYPE;
+    seq BIGINT;
+    prev TEXT;
+    linked BIGINT := 0;
+BEGIN
+    FOR chain_key IN
+        SELECT DISTINCT COALESCE(election_id, 0) FROM audit_logs WHERE row_hash IS NULL
+        ORDER BY 1
+    LOOP
+        CONTINUE WHEN NOT pg_try_advisory_xact_lock(hashtext('audit_logs_chain'), chain_key::int);
+
+        SELECT chain_seq, row_hash INTO prev_row
+        FROM audit_logs
+        WHERE COALESCE(election_id, 0) = chain_key AND row_hash IS NOT NULL
+        ORDER BY chain_seq DESC
+        LIMIT 1;
+
+        IF NOT FOUND THEN
+            seq := 0;
+            prev := repeat('0', 64);
+        ELSE
+            seq := prev_row.chain_seq;
+            prev := prev_row.row_hash;
+        END IF;
+
+        FOR r IN
+            SELECT * FROM audit_logs
+            WHERE COALESCE(election_id, 0) = chain_key AND row_hash IS NULL
+            ORDER BY id
+            LIMIT batch_size
+        LOOP
+            seq := seq + 1;
+            r.chain_seq := seq;
+            r.prev_hash := prev;
+            r.row_hash := encode(sha256(convert_to(audit_logs_chain_payload(r), 'UTF8')), 'hex');
+
+            UPDATE audit_logs
+            SET chain_seq = r.chain_seq, prev_hash = r.prev_hash, row_hash = r.row_hash
+            WHERE id = r.id;
+
+            prev := r.row_hash;
+            linked := linked + 1;
+        END LOOP;
+    END LOOP;
+
+    RETURN linked;
+END;
+$$ LANGUAGE plpgsql;
+
+-- 3. Append-only, except that an unlinked row may be linked once.
+CREATE OR REPLACE FUNCTION audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+       AND OLD.row_hash IS NULL
+       AND NEW.row_hash IS NOT NULL
+       AND to_jsonb(NEW) - 'chain_seq' - 'prev_hash' - 'row_hash'
+           = to_jsonb(OLD) - 'chain_seq' - 'prev_hash' - 'row_hash' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
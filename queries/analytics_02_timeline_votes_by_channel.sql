@@ -22,16 +22,18 @@ votes_per_bucket AS (
     SELECT
         date_trunc('hour', v.cast_at) AS bucket_start,
         v.channel,
+        v.is_abstain,
         COUNT(*) AS cnt
     FROM votes v
     WHERE v.election_id = $1
-    GROUP BY date_trunc('hour', v.cast_at), v.channel
+    GROUP BY date_trunc('hour', v.cast_at), v.channel, v.is_abstain
 )
 SELECT
     b.bucket_start,
     COALESCE(SUM(CASE WHEN vpb.channel = 'ONLINE' THEN vpb.cnt END), 0) AS votes_online,
     COALESCE(SUM(CASE WHEN vpb.channel = 'TPS'    THEN vpb.cnt END), 0) AS votes_tps,
-    COALESCE(SUM(vpb.cnt), 0) AS total_votes
+    COALESCE(SUM(vpb.cnt), 0) AS total_votes,
+    COALESCE(SUM(CASE WHEN vpb.is_abstain THEN vpb.cnt END), 0) AS votes_abstain
 FROM buckets b
 LEFT JOIN votes_per_bucket vpb
        ON vpb.bucket_start = b.bucket_start
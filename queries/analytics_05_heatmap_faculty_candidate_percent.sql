@@ -4,8 +4,8 @@
 
 WITH faculty_totals AS (
     SELECT
-        v.faculty_code,
-        v.faculty_name,
+        COALESCE(v.faculty_code, 'UNKNOWN') AS faculty_code,
+        COALESCE(v.faculty_name, '')        AS faculty_name,
         COUNT(*) AS total_votes_faculty
     FROM votes vts
     JOIN voter_status vs
@@ -14,15 +14,15 @@ WITH faculty_totals AS (
     JOIN voters v
       ON v.id = vs.voter_id
     WHERE vts.election_id = $1
-    GROUP BY v.faculty_code, v.faculty_name
+    GROUP BY 1, 2
 ),
 faculty_candidate AS (
     SELECT
-        v.faculty_code,
-        v.faculty_name,
+        COALESCE(v.faculty_code, 'UNKNOWN') AS faculty_code,
+        COALESCE(v.faculty_name, '')        AS faculty_name,
         c.id               AS candidate_id,
-        c.candidate_number AS candidate_number,
-        c.chairman_name    AS candidate_name,
+        c.number           AS candidate_number,
+        c.name             AS candidate_name,
         COUNT(*) AS total_votes
     FROM votes vts
     JOIN voter_status vs
@@ -34,7 +34,7 @@ faculty_candidate AS (
       ON c.id = vts.candidate_id
      AND c.election_id = vts.election_id
     WHERE vts.election_id = $1
-    GROUP BY v.faculty_code, v.faculty_name, c.id, c.candidate_number, c.chairman_name
+    GROUP BY 1, 2, c.id, c.number, c.name
 )
 SELECT
     fc.faculty_code,
@@ -48,10 +48,10 @@ SELECT
     bwc.bucket_start,
     bwc.votes_in_hour,
     bwc.cumulative_votes,
-    ROUND(
+    COALESCE(ROUND(
         bwc.cumulative_votes::NUMERIC / NULLIF(te.total, 0) * 100,
         2
-    ) AS cumulative_turnout_percent
+    ), 0) AS cumulative_turnout_percent
 FROM bucket_with_cum bwc
 CROSS JOIN total_eligible te
 ORDER BY bwc.bucket_start;
@@ -3,10 +3,10 @@
 -- Output: data untuk clustered bar chart atau grouped analysis
 
 SELECT
-    v.cohort_year,
+    COALESCE(v.cohort_year, 0) AS cohort_year,
     c.id               AS candidate_id,
-    c.candidate_number AS candidate_number,
-    c.chairman_name    AS candidate_name,
+    c.number           AS candidate_number,
+    c.name             AS candidate_name,
     COUNT(*) AS total_votes
 FROM votes vts
 JOIN voter_status vs
@@ -18,5 +18,5 @@ JOIN candidates c
   ON c.id = vts.candidate_id
  AND c.election_id = vts.election_id
 WHERE vts.election_id = $1
-GROUP BY v.cohort_year, c.id, c.candidate_number, c.chairman_name
-ORDER BY v.cohort_year, c.candidate_number;
+GROUP BY 1, c.id, c.number, c.name
+ORDER BY 1, c.number;
@@ -23,7 +23,7 @@ ranked AS (
 )
 SELECT
     vote_hour,
-    EXTRACT(HOUR FROM vote_hour) AS hour_of_day,
+    EXTRACT(HOUR FROM vote_hour)::INT AS hour_of_day,
     TO_CHAR(vote_hour, 'Day') AS day_name,
     total_votes,
     votes_online,
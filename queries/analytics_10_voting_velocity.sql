@@ -17,9 +17,9 @@ gaps AS (
 )
 SELECT
     COUNT(*) AS total_intervals,
-    ROUND(AVG(gap_minutes)::NUMERIC, 2) AS avg_gap_minutes,
-    ROUND(MIN(gap_minutes)::NUMERIC, 2) AS min_gap_minutes,
-    ROUND(MAX(gap_minutes)::NUMERIC, 2) AS max_gap_minutes,
-    ROUND(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY gap_minutes)::NUMERIC, 2) AS median_gap_minutes,
-    ROUND(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY gap_minutes)::NUMERIC, 2) AS p95_gap_minutes
+    COALESCE(ROUND(AVG(gap_minutes)::NUMERIC, 2), 0) AS avg_gap_minutes,
+    COALESCE(ROUND(MIN(gap_minutes)::NUMERIC, 2), 0) AS min_gap_minutes,
+    COALESCE(ROUND(MAX(gap_minutes)::NUMERIC, 2), 0) AS max_gap_minutes,
+    COALESCE(ROUND(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY gap_minutes)::NUMERIC, 2), 0) AS median_gap_minutes,
+    COALESCE(ROUND(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY gap_minutes)::NUMERIC, 2), 0) AS p95_gap_minutes
 FROM gaps;
@@ -0,0 +1,29 @@
+// Package queries embeds the reporting SQL kept in this directory so other
+// packages can use it without //go:embed paths leaving their own directory.
+package queries
+
+import _ "embed"
+
+// Analytics dashboard queries, all taking $1 = election_id.
+var (
+	//go:embed analytics_02_timeline_votes_by_channel.sql
+	AnalyticsHourlyVotesByChannel string
+
+	//go:embed analytics_03_timeline_votes_per_candidate.sql
+	AnalyticsHourlyVotesByCandidate string
+
+	//go:embed analytics_05_heatmap_faculty_candidate_percent.sql
+	AnalyticsFacultyCandidateHeatmap string
+
+	//go:embed analytics_06_turnout_cumulative_timeline.sql
+	AnalyticsTurnoutTimeline string
+
+	//go:embed analytics_07_votes_by_cohort_candidate.sql
+	AnalyticsCohortCandidateVotes string
+
+	//go:embed analytics_09_peak_hours_analysis.sql
+	AnalyticsPeakHours string
+
+	//go:embed analytics_10_voting_velocity.sql
+	AnalyticsVotingVelocity string
+)